@@ -0,0 +1,51 @@
+package easyflag
+
+import (
+	"flag"
+	"unicode/utf8"
+)
+
+/*
+Backend parses the command line arguments instead of the native flag package, e.g. to support the POSIX-style
+--long and -s short flags. The github.com/matusvla/easyflag/pflagbackend module provides the backend
+based on the github.com/spf13/pflag package.
+*/
+type Backend interface {
+	// Parse sets the flags of the flag set from the arguments. The short names map the flag names
+	// to their single-character short names defined by the short field tag.
+	// It returns the names of the flags used and the non-flag arguments remaining after the flags.
+	// If the help is requested, it prints the usage by the Usage function of the flag set and returns flag.ErrHelp.
+	Parse(fs *flag.FlagSet, args []string, shortNames map[string]string) (used []string, remaining []string, err error)
+}
+
+// WithBackend replaces the native flag package parsing the command line arguments by the backend.
+func WithBackend(b Backend) Option {
+	return func(o *options) {
+		o.backend = b
+	}
+}
+
+// shortNames returns the short names of the flags defined by the short field tag.
+func (fb *flagBuilder) shortNames() map[string]string {
+	names := make(map[string]string)
+	for _, f := range fb.flags {
+		if f.short != "" {
+			names[f.name] = f.short
+		}
+	}
+	return names
+}
+
+// remainingArgs returns the non-flag arguments remaining after the flags.
+func (fb *flagBuilder) remainingArgs() []string {
+	if fb.opts.backend != nil {
+		return fb.remaining
+	}
+	return fb.flagSet.Args()
+}
+
+// isShortName reports whether the name is a valid short name of a flag, i.e. a single character other than a hyphen.
+func isShortName(name string) bool {
+	r, size := utf8.DecodeRuneInString(name)
+	return size > 0 && size == len(name) && r != utf8.RuneError && r != '-' && r != '=' && r != ' '
+}
@@ -0,0 +1,104 @@
+package easyflag
+
+import (
+	"flag"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// shortBackend is a test backend accepting only the short flags in the -s value form.
+type shortBackend struct{}
+
+func (shortBackend) Parse(fs *flag.FlagSet, args []string, shortNames map[string]string) ([]string, []string, error) {
+	long := make(map[string]string, len(shortNames))
+	for name, short := range shortNames {
+		long[short] = name
+	}
+	var used []string
+	for len(args) >= 2 && strings.HasPrefix(args[0], "-") {
+		name := long[strings.TrimPrefix(args[0], "-")]
+		if err := fs.Set(name, args[1]); err != nil {
+			return nil, nil, err
+		}
+		used = append(used, name)
+		args = args[2:]
+	}
+	return used, args, nil
+}
+
+func TestParse_backend(t *testing.T) {
+	type backendParams struct {
+		Port int    `flag:"port|Server port|80" short:"p"`
+		Host string `flag:"host|Server host|localhost" short:"H" env:"HOST"`
+	}
+	os.Args = []string{"executable_name", "-p", "8080", "file.txt"}
+	var p backendParams
+	var used []string
+	res, err := Parse(&p, WithBackend(shortBackend{}), WithEnvSource(MapEnv{"HOST": "example.com"}),
+		WithUsedFlagsHook(func(names []string) { used = names }))
+	assert.NoError(t, err)
+	assert.Equal(t, backendParams{Port: 8080, Host: "example.com"}, p)
+	assert.Equal(t, []string{"file.txt"}, res.Args)
+	assert.Equal(t, []string{"port"}, used)
+	assert.Equal(t, map[string]Source{"port": SourceCommandLine, "host": SourceEnv}, res.Sources)
+}
+
+func TestParseAndLoad_invalidShortName(t *testing.T) {
+	os.Args = []string{"executable_name"}
+	var p struct {
+		Port int `flag:"port|Server port|80" short:"po"`
+	}
+	err := ParseAndLoad(&p, WithOutput(io.Discard))
+	assert.EqualError(t, err, `invalid field Port: invalid short name "po" of the flag -port`)
+}
+
+func TestParseAndLoad_duplicateShortName(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  interface{}
+		wantErr string
+	}{
+		{
+			name: "same short names",
+			params: &struct {
+				Verbose bool `flag:"verbose" short:"v"`
+				Version bool `flag:"print-version" short:"v"`
+			}{},
+			wantErr: "invalid field Version: short name -v of the flag -print-version already defined by the field Verbose",
+		},
+		{
+			name: "short name of a single-character flag",
+			params: &struct {
+				V       bool `flag:"v"`
+				Verbose bool `flag:"verbose" short:"v"`
+			}{},
+			wantErr: "invalid field Verbose: short name -v of the flag -verbose already defined by the field V",
+		},
+		{
+			name: "single-character flag of a short name",
+			params: &struct {
+				Verbose bool `flag:"verbose" short:"v"`
+				V       bool `flag:"v"`
+			}{},
+			wantErr: "invalid field V: flag -v already defined as the short name by the field Verbose",
+		},
+		{
+			name: "short name of a reserved flag",
+			params: &struct {
+				Help bool `flag:"show-help" short:"h"`
+			}{},
+			wantErr: "invalid field Help: reserved flag -h overwriting not allowed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = []string{"executable_name"}
+			err := ParseAndLoad(tt.params, WithOutput(io.Discard))
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package easyflag
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	errInvalidBigInt = errors.New("invalid integer, expected a base 10 number or a base 16, 8 or 2 number " +
+		"with the 0x, 0o or 0b prefix")
+	errInvalidBigRat = errors.New("invalid rational number, expected a base 10 decimal number or a fraction, e.g. 3/4")
+)
+
+// bigIntValue is the flag value of the *big.Int fields.
+type bigIntValue struct {
+	i **big.Int
+}
+
+func (v *bigIntValue) String() string {
+	if v.i == nil || *v.i == nil {
+		return ""
+	}
+	return (*v.i).String()
+}
+
+func (v *bigIntValue) Set(s string) error {
+	i, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return errInvalidBigInt
+	}
+	*v.i = i
+	return nil
+}
+
+func (v *bigIntValue) valueName() string {
+	return "int"
+}
+
+// bigRatValue is the flag value of the *big.Rat fields.
+type bigRatValue struct {
+	r **big.Rat
+}
+
+func (v *bigRatValue) String() string {
+	if v.r == nil || *v.r == nil {
+		return ""
+	}
+	return (*v.r).RatString()
+}
+
+func (v *bigRatValue) Set(s string) error {
+	// the float parsing protects the big.Rat parsing from the huge exponents
+	if strings.ContainsAny(s, "eE") {
+		if _, err := strconv.ParseFloat(s, 64); errors.Is(err, strconv.ErrRange) {
+			return errRange
+		}
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return errInvalidBigRat
+	}
+	*v.r = r
+	return nil
+}
+
+func (v *bigRatValue) valueName() string {
+	return "rat"
+}
@@ -0,0 +1,72 @@
+package easyflag
+
+import (
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAndLoad_big(t *testing.T) {
+	type amountParams struct {
+		Supply *big.Int `flag:"supply|Total supply|1_000_000_000_000_000_000_000"`
+		Wei    *big.Int `flag:"wei|Amount in wei"`
+		Rate   *big.Rat `flag:"rate|Exchange rate|3/4"`
+	}
+	tests := []struct {
+		name       string
+		args       []string
+		wantSupply string
+		wantWei    string
+		wantRate   string
+		wantErr    string
+	}{
+		{
+			name:       "defaults",
+			args:       []string{"program"},
+			wantSupply: "1000000000000000000000",
+			wantRate:   "3/4",
+		},
+		{
+			name:       "set",
+			args:       []string{"program", "-wei=0xde0b6b3a7640000", "-rate=1.25", "-supply=-1"},
+			wantSupply: "-1",
+			wantWei:    "1000000000000000000",
+			wantRate:   "5/4",
+		},
+		{
+			name:    "invalid integer",
+			args:    []string{"program", "-wei=1.5"},
+			wantErr: `invalid value "1.5" for flag -wei: invalid integer, expected a base 10 number or a base 16, 8 or 2 number with the 0x, 0o or 0b prefix`,
+		},
+		{
+			name:    "invalid rational number",
+			args:    []string{"program", "-rate=1/0"},
+			wantErr: `invalid value "1/0" for flag -rate: invalid rational number, expected a base 10 decimal number or a fraction, e.g. 3/4`,
+		},
+		{
+			name:    "huge exponent",
+			args:    []string{"program", "-rate=1e1000000000"},
+			wantErr: `invalid value "1e1000000000" for flag -rate: value out of range`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p amountParams
+			err := ParseAndLoad(&p, WithArgsSource(StaticArgs(tt.args)), WithOutput(io.Discard))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSupply, p.Supply.String())
+			assert.Equal(t, tt.wantRate, p.Rate.RatString())
+			if tt.wantWei == "" {
+				assert.Nil(t, p.Wei)
+			} else {
+				assert.Equal(t, tt.wantWei, p.Wei.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package easyflag
+
+import (
+	"errors"
+	"flag"
+)
+
+/*
+Bind registers the flags of the params structure into the flag set owned by the caller, which is responsible
+for the parsing. The params must be a pointer to a structure just like in the case of the ParseAndLoad function.
+The fields are set to the default values of the flags immediately and they are filled by the flag values
+when the flag set is parsed:
+
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	var p params
+	if err := easyflag.Bind(fs, &p); err != nil {
+		[...]
+	}
+	err := fs.Parse(os.Args[2:])
+
+Only the flags are registered: the built-in flags are not defined, except the configuration file flag
+of the WithConfigFlag option, and the environment variables, required flags, validations and extensions
+are not processed, see the Register function. The usage function and the output of the flag set are left untouched.
+A flag name already defined in the flag set is reported as an error.
+*/
+func Bind(fs *flag.FlagSet, params interface{}, opts ...Option) error {
+	_, err := Register(fs, params, opts...)
+	return err
+}
+
+// Registration is the registration of the params structure flags into a flag set owned by the caller
+// returned by the Register function.
+type Registration struct {
+	params interface{}
+	fb     *flagBuilder
+}
+
+/*
+Register registers the flags of the params structure into the flag set owned by the caller the same way
+as the Bind function. The returned Registration completes the loading once the flag set is parsed.
+This allows a library to expose its configuration structure to the applications parsing the flags themselves:
+
+	reg, err := easyflag.Register(flag.CommandLine, &p)
+	[...]
+	flag.Parse()
+	if err := reg.Load(); err != nil {
+		[...]
+	}
+*/
+func Register(fs *flag.FlagSet, params interface{}, opts ...Option) (*Registration, error) {
+	if err := checkParams(params); err != nil {
+		return nil, err
+	}
+	fb := newBoundFlagBuilder(fs, newParamsOptions(params, opts))
+	if fb.opts.configFlag != "" {
+		if err := fb.addReservedFlags([]ReservedFlag{fb.configFlag()}); err != nil {
+			return nil, err
+		}
+	}
+	if err := fb.setUpFlags(params); err != nil {
+		return nil, err
+	}
+	if err := fb.setUpNamespaces(false); err != nil {
+		return nil, err
+	}
+	if err := fb.checkDefaultFuncs(); err != nil {
+		return nil, err
+	}
+	return &Registration{params: params, fb: fb}, nil
+}
+
+// Load completes the loading of the params after the flag set is parsed the same way as the ParseAndLoad function:
+// it sets the flags not used on the command line from the environment variables, .env file, secret provider,
+// remote sources, configuration file and prompts, checks the required flags and runs the validations,
+// post-processors and extensions. It must be called at most once after the flag set is parsed.
+func (r *Registration) Load() error {
+	if !r.fb.flagSet.Parsed() {
+		return errors.New("flag set not parsed yet")
+	}
+	return r.LoadParsed(r.fb.usedFlags())
+}
+
+// LoadParsed completes the loading of the params the same way as the Load method if the command line was parsed
+// by another parser setting the values of the registered flags directly, e.g. by the cobra or pflag packages.
+// The used are the names of the flags used on the command line.
+func (r *Registration) LoadParsed(used []string) error {
+	r.fb.recordUsedFlags(used)
+	if err := r.fb.loadSources(); err != nil {
+		return err
+	}
+	return r.fb.check(r.params)
+}
@@ -0,0 +1,131 @@
+package easyflag
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBind(t *testing.T) {
+	type bindParams struct {
+		Host string `flag:"host|Server host|localhost"`
+		Port int    `flag:"port|Server port|80"`
+	}
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	verbose := fs.Bool("v", false, "Verbose output")
+	var p bindParams
+	assert.NoError(t, Bind(fs, &p))
+	assert.Equal(t, bindParams{Host: "localhost", Port: 80}, p)
+	assert.Nil(t, fs.Lookup("help"))
+	assert.Nil(t, fs.Lookup("easyflag-completion"))
+
+	assert.NoError(t, fs.Parse([]string{"-v", "-port=8080", "arg"}))
+	assert.Equal(t, bindParams{Host: "localhost", Port: 8080}, p)
+	assert.True(t, *verbose)
+	assert.Equal(t, []string{"arg"}, fs.Args())
+
+	err := Bind(fs, &struct {
+		Verbose bool `flag:"v|Verbose output"`
+	}{})
+	assert.EqualError(t, err, "invalid field Verbose: flag -v defined more than once")
+
+	err = Bind(fs, p)
+	assert.EqualError(t, err, "flags parse: got non-pointer easyflag.bindParams")
+}
+
+func TestRegister(t *testing.T) {
+	type registerParams struct {
+		Port  int    `flag:"port|Server port|80" env:"PORT"`
+		Token string `flag:"token|API token||required"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		parse   bool
+		want    registerParams
+		wantErr string
+	}{
+		{
+			name:  "loaded",
+			args:  []string{"-token=t"},
+			parse: true,
+			want:  registerParams{Port: 8080, Token: "t"},
+		},
+		{
+			name:    "required flag missing",
+			parse:   true,
+			want:    registerParams{Port: 8080},
+			wantErr: `missing required flag "token" or its value`,
+		},
+		{
+			name:    "not parsed",
+			want:    registerParams{Port: 80},
+			wantErr: "flag set not parsed yet",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("app", flag.ContinueOnError)
+			var p registerParams
+			reg, err := Register(fs, &p, WithEnvSource(MapEnv{"PORT": "8080"}))
+			assert.NoError(t, err)
+			if tt.parse {
+				assert.NoError(t, fs.Parse(tt.args))
+			}
+			err = reg.Load()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
+
+func TestRegistration_LoadParsed(t *testing.T) {
+	type registerParams struct {
+		Port int    `flag:"port|Server port|80" env:"PORT"`
+		Host string `flag:"host|Server host|localhost" env:"HOST"`
+	}
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	var p registerParams
+	reg, err := Register(fs, &p, WithEnvSource(MapEnv{"PORT": "8080", "HOST": "example.com"}))
+	assert.NoError(t, err)
+	// another parser sets the flag values directly
+	assert.NoError(t, fs.Lookup("port").Value.Set("9090"))
+	assert.NoError(t, reg.LoadParsed([]string{"port"}))
+	assert.Equal(t, registerParams{Port: 9090, Host: "example.com"}, p)
+}
+
+func TestRegistration_sources(t *testing.T) {
+	type sourceParams struct {
+		Port     int    `flag:"port|Server port|80"`
+		Host     string `flag:"host|Server host|localhost" env:"HOST"`
+		Password string `flag:"db-password|Database password||secret" secretRef:"db/password"`
+		Region   string `flag:"region|Cloud region"`
+		Workers  int    `flag:"workers|Number of workers|1"`
+	}
+	dir := t.TempDir()
+	dotEnv := filepath.Join(dir, ".env")
+	assert.NoError(t, os.WriteFile(dotEnv, []byte("HOST=example.com\n"), 0o600))
+	config := filepath.Join(dir, "config.json")
+	assert.NoError(t, os.WriteFile(config, []byte(`{"port": 1, "workers": 4}`), 0o600))
+
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	var p sourceParams
+	reg, err := Register(fs, &p, WithEnvSource(MapEnv{}), WithDotEnv(dotEnv), WithConfigFlag("config"),
+		WithSecretProvider(mapSecrets{"db/password": "s3cret"}), WithRemoteSource(mapRemote{"region": "eu-west-1"}))
+	assert.NoError(t, err)
+	assert.NoError(t, fs.Parse([]string{"-port=8080", "-config", config}))
+	assert.NoError(t, reg.Load())
+	assert.Equal(t, sourceParams{Port: 8080, Host: "example.com", Password: "s3cret", Region: "eu-west-1", Workers: 4}, p)
+
+	_, err = Register(fs, &struct{}{}, WithConfigFlag("config"))
+	assert.EqualError(t, err, "flag -config defined more than once")
+}
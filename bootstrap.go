@@ -0,0 +1,108 @@
+package easyflag
+
+import (
+	"os"
+	"strings"
+)
+
+/*
+ParseBootstrap fills the params structure from the CLI flags the same way as the ParseAndLoad function, but it ignores
+all the arguments which are not the flags of the params structure, including the built-in flags and the help.
+It is meant for the first phase of a two-phase parsing, in which a small bootstrap structure (e.g. with the -config
+or -profile flags) is parsed first and its values configure how the full structure is parsed:
+
+	type BootstrapParams struct {
+		Config string `flag:"config|Configuration file"`
+	}
+
+	type params struct {
+		BootstrapParams
+		Port int `flag:"port|Server port|80"`
+	}
+
+	var bp BootstrapParams
+	if err := easyflag.ParseBootstrap(&bp); err != nil {
+		[...]
+	}
+	p := loadConfig(bp.Config)
+	err := easyflag.ParseAndLoad(&p, easyflag.WithPrepopulatedDefaults())
+
+The bootstrap flags must be defined by the full structure as well (e.g. by embedding the bootstrap structure),
+otherwise they are rejected by the second phase. The values of the flags are still validated, the required flags
+are checked and the environment variables are used.
+*/
+func ParseBootstrap(params interface{}, opts ...Option) error {
+	return ParseAndLoad(params, append(opts, func(o *options) {
+		o.bootstrap = true
+	})...)
+}
+
+/*
+ParseKnown fills the params structure from the flags in the args the same way as the ParseBootstrap function
+and returns the rest of the args untouched, i.e. all the arguments which are not the flags of the params structure
+in their original order. The args do not contain the program name. This allows for layering the params structure
+in front of another consumer of the arguments, e.g. a test framework or a plugin host:
+
+	rest, err := easyflag.ParseKnown(&p, os.Args[1:])
+	[...]
+	plugin.Run(rest)
+
+The help flags and the arguments after the -- terminator are left in the rest as well.
+*/
+func ParseKnown(params interface{}, args []string, opts ...Option) (rest []string, err error) {
+	program := "" // the program name is used only in the messages, it is taken from os.Args if available
+	if len(os.Args) > 0 {
+		program = os.Args[0]
+	}
+	opts = append(opts, WithArgsSource(StaticArgs(append([]string{program}, args...))), func(o *options) {
+		o.bootstrap = true
+	})
+	_, fb, err := parse(params, opts)
+	if err != nil {
+		return nil, err
+	}
+	return fb.rest, nil
+}
+
+// knownArgs splits the arguments to the ones setting the flags of the params structure including their values
+// and all the other arguments, which keep their order. The arguments after the "--" terminator are never flags,
+// so the terminator and the arguments after it are always left in the rest.
+func (fb *flagBuilder) knownArgs(args []string) (result []string, rest []string) {
+	known := make(map[string]flagInfo, len(fb.flags))
+	for _, f := range fb.flags {
+		known[f.name] = f
+	}
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			return result, append(rest, args[i:]...)
+		}
+		name, hasValue, ok := flagArgName(args[i])
+		f, isKnown := known[name]
+		if !ok || !isKnown {
+			rest = append(rest, args[i])
+			continue
+		}
+		result = append(result, args[i])
+		if !hasValue && !f.isBool && i+1 < len(args) {
+			i++
+			result = append(result, args[i])
+		}
+	}
+	return result, rest
+}
+
+// flagArgName returns the name of the flag set by the argument in the -name, --name, -name=value or --name=value form,
+// and whether the argument contains the value as well.
+func flagArgName(arg string) (name string, hasValue bool, ok bool) {
+	if len(arg) < 2 || arg[0] != '-' {
+		return "", false, false
+	}
+	name = strings.TrimPrefix(arg[1:], "-")
+	if name == "" || name[0] == '-' || name[0] == '=' {
+		return "", false, false
+	}
+	if i := strings.IndexByte(name, '='); i >= 0 {
+		return name[:i], true, true
+	}
+	return name, false, true
+}
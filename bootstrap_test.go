@@ -0,0 +1,104 @@
+package easyflag
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type BootstrapParams struct {
+	Config  string `flag:"config|Configuration file"`
+	Verbose bool   `flag:"v|Verbose output"`
+}
+
+func TestParseBootstrap(t *testing.T) {
+	type fullParams struct {
+		BootstrapParams
+		Port int `flag:"port|Server port|80"`
+	}
+	os.Args = []string{"executable_name", "-port", "8080", "-v", "--config", "app.json", "-unknown=x", "-h", "--", "-config=other.json"}
+	var bp BootstrapParams
+	err := ParseBootstrap(&bp, WithVersion("1.0.0"), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, BootstrapParams{Config: "app.json", Verbose: true}, bp)
+
+	os.Args = []string{"executable_name", "-port", "8080", "-v", "--config", "app.json"}
+	p := fullParams{Port: 1}
+	err = ParseAndLoad(&p, WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, fullParams{BootstrapParams: bp, Port: 8080}, p)
+}
+
+func TestFlagArgName(t *testing.T) {
+	tests := []struct {
+		arg          string
+		wantName     string
+		wantHasValue bool
+		wantOk       bool
+	}{
+		{arg: "-config", wantName: "config", wantOk: true},
+		{arg: "--config=a.json", wantName: "config", wantHasValue: true, wantOk: true},
+		{arg: "-config=", wantName: "config", wantHasValue: true, wantOk: true},
+		{arg: "config"},
+		{arg: "-"},
+		{arg: "--"},
+		{arg: "---config"},
+		{arg: "-=x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			name, hasValue, ok := flagArgName(tt.arg)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantHasValue, hasValue)
+			assert.Equal(t, tt.wantOk, ok)
+		})
+	}
+}
+
+func TestParseKnown(t *testing.T) {
+	type layerParams struct {
+		Verbose bool   `flag:"v|Verbose output"`
+		Seed    int    `flag:"seed|Random seed|1"`
+		Name    string `flag:"name|Name||required"`
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		want     layerParams
+		wantRest []string
+		wantErr  string
+	}{
+		{
+			name:     "interleaved arguments",
+			args:     []string{"-test.run", "TestX", "-v", "pkg", "-seed=7", "-h", "-name", "a", "-count", "2"},
+			want:     layerParams{Verbose: true, Seed: 7, Name: "a"},
+			wantRest: []string{"-test.run", "TestX", "pkg", "-h", "-count", "2"},
+		},
+		{
+			name:     "terminator",
+			args:     []string{"-name=a", "--", "-seed=2", "x"},
+			want:     layerParams{Seed: 1, Name: "a"},
+			wantRest: []string{"--", "-seed=2", "x"},
+		},
+		{
+			name:    "invalid value",
+			args:    []string{"-name=a", "-seed=x"},
+			wantErr: `invalid value "x" for flag -seed: parse error`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p layerParams
+			rest, err := ParseKnown(&p, tt.args, WithOutput(io.Discard))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+			assert.Equal(t, tt.wantRest, rest)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package easyflag
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var (
+	errInvalidHex    = errors.New("invalid hex encoding")
+	errInvalidBase64 = errors.New("invalid base64 encoding")
+)
+
+// bytesValue is the flag value of the []byte fields. The values are decoded according to the hex or base64 flag option,
+// otherwise the bytes of the values are used as they are.
+type bytesValue struct {
+	bytes    *[]byte
+	encoding string
+}
+
+func (v *bytesValue) String() string {
+	if v.bytes == nil || len(*v.bytes) == 0 {
+		return ""
+	}
+	switch v.encoding {
+	case hexValue:
+		return hex.EncodeToString(*v.bytes)
+	case base64Value:
+		return base64.StdEncoding.EncodeToString(*v.bytes)
+	default:
+		return string(*v.bytes)
+	}
+}
+
+func (v *bytesValue) Set(s string) error {
+	b, err := v.decode(s)
+	if err != nil {
+		return err
+	}
+	*v.bytes = b
+	return nil
+}
+
+// decode decodes the value. The base64 values can use the standard or the URL-safe alphabet, with or without
+// the padding.
+func (v *bytesValue) decode(s string) ([]byte, error) {
+	switch v.encoding {
+	case hexValue:
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, errInvalidHex
+		}
+		return b, nil
+	case base64Value:
+		enc := base64.RawStdEncoding
+		if strings.ContainsAny(s, "-_") {
+			enc = base64.RawURLEncoding
+		}
+		b, err := enc.DecodeString(strings.TrimRight(s, "="))
+		if err != nil {
+			return nil, errInvalidBase64
+		}
+		return b, nil
+	default:
+		return []byte(s), nil
+	}
+}
+
+func (v *bytesValue) valueName() string {
+	switch v.encoding {
+	case hexValue, base64Value:
+		return v.encoding
+	default:
+		return "string"
+	}
+}
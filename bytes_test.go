@@ -0,0 +1,59 @@
+package easyflag
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytesValue_Set(t *testing.T) {
+	tests := []struct {
+		name       string
+		encoding   string
+		in         string
+		want       []byte
+		wantString string
+		wantErr    error
+	}{
+		{name: "raw", in: "salt", want: []byte("salt"), wantString: "salt"},
+		{name: "hex", encoding: hexValue, in: "DEADbeef", want: []byte{0xde, 0xad, 0xbe, 0xef}, wantString: "deadbeef"},
+		{name: "invalid hex", encoding: hexValue, in: "abc", wantErr: errInvalidHex},
+		{name: "base64", encoding: base64Value, in: "+/8=", want: []byte{0xfb, 0xff}, wantString: "+/8="},
+		{name: "base64 without padding", encoding: base64Value, in: "+/8", want: []byte{0xfb, 0xff}, wantString: "+/8="},
+		{name: "base64 url", encoding: base64Value, in: "-_8", want: []byte{0xfb, 0xff}, wantString: "+/8="},
+		{name: "invalid base64", encoding: base64Value, in: "a", wantErr: errInvalidBase64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b []byte
+			v := &bytesValue{bytes: &b, encoding: tt.encoding}
+			assert.Equal(t, tt.wantErr, v.Set(tt.in))
+			assert.Equal(t, tt.want, b)
+			assert.Equal(t, tt.wantString, v.String())
+		})
+	}
+}
+
+func TestParseAndLoad_bytes(t *testing.T) {
+	type keyParams struct {
+		Key   []byte `flag:"key|Encryption key||hex,secret" env:"KEY"`
+		Salt  []byte `flag:"salt|Salt|c2FsdA==|base64"`
+		Token []byte `flag:"token|Token"`
+	}
+	var p keyParams
+	err := ParseAndLoad(&p, WithArgsSource(StaticArgs{"program", "-token=abc"}), WithEnvSource(MapEnv{"KEY": "00ff"}),
+		WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, keyParams{Key: []byte{0x00, 0xff}, Salt: []byte("salt"), Token: []byte("abc")}, p)
+
+	err = ParseAndLoad(&p, WithArgsSource(StaticArgs{"program"}), WithEnvSource(MapEnv{"KEY": "xyz"}),
+		WithOutput(io.Discard))
+	assert.EqualError(t, err, `invalid value "***" of the environment variable KEY for the flag -key: invalid hex encoding`)
+
+	type invalidParams struct {
+		Port int `flag:"port|Port|80|hex"`
+	}
+	err = ParseAndLoad(&invalidParams{}, WithArgsSource(StaticArgs{"program"}), WithOutput(io.Discard))
+	assert.EqualError(t, err, "invalid field Port: encoding hex not supported for the flag -port")
+}
@@ -0,0 +1,105 @@
+package easyflag
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a flag field type holding a number of bytes parsed from a human-readable size, e.g. 10MiB or 4GB.
+//
+// The size is a non-negative decimal number, optionally with a fraction (e.g. 1.5GiB) as long as the number of bytes
+// is an integer, followed by an optional unit. Both the SI units (kB, MB, GB, TB, PB, EB - the powers of 1000)
+// and the IEC units (KiB, MiB, GiB, TiB, PiB, EiB - the powers of 1024) are supported. The units are case-insensitive,
+// the B suffix can be omitted (e.g. 10M or 10Mi) and the number without a unit is the number of bytes.
+type ByteSize uint64
+
+// The byte size units.
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+	PB ByteSize = 1000 * TB
+	EB ByteSize = 1000 * PB
+
+	KiB ByteSize = 1024 * Byte
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+	PiB ByteSize = 1024 * TiB
+	EiB ByteSize = 1024 * PiB
+)
+
+type byteSizeUnit struct {
+	name string
+	size ByteSize
+}
+
+// byteSizeUnits are the units from the largest one. The IEC ones are preferred when a size is formatted.
+var byteSizeUnits = []byteSizeUnit{
+	{"EiB", EiB}, {"PiB", PiB}, {"TiB", TiB}, {"GiB", GiB}, {"MiB", MiB}, {"KiB", KiB},
+	{"EB", EB}, {"PB", PB}, {"TB", TB}, {"GB", GB}, {"MB", MB}, {"kB", KB},
+}
+
+// String returns the size as the smallest integer of any unit, e.g. 10MiB, 4GB or 1023B.
+func (s ByteSize) String() string {
+	best := byteSizeUnit{"B", Byte}
+	if s != 0 {
+		for _, u := range byteSizeUnits {
+			if s%u.size == 0 && u.size > best.size {
+				best = u
+			}
+		}
+	}
+	return strconv.FormatUint(uint64(s/best.size), 10) + best.name
+}
+
+// ParseByteSize parses a human-readable size, see the ByteSize type for the accepted format.
+func ParseByteSize(s string) (ByteSize, error) {
+	num := strings.TrimRight(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	unit, err := parseByteSizeUnit(strings.TrimSpace(s[len(num):]))
+	if err != nil {
+		return 0, err
+	}
+	num = strings.ReplaceAll(strings.TrimSpace(num), "_", "")
+	if num == "" || strings.ContainsAny(num, "+-/eExX") {
+		return 0, errParse
+	}
+	r, ok := new(big.Rat).SetString(num)
+	if !ok {
+		return 0, errParse
+	}
+	r.Mul(r, new(big.Rat).SetUint64(uint64(unit)))
+	if !r.IsInt() {
+		return 0, errNotInteger
+	}
+	if !r.Num().IsUint64() {
+		return 0, errRange
+	}
+	return ByteSize(r.Num().Uint64()), nil
+}
+
+// byteSizePrefixes are the SI and IEC units by their lower-case prefixes, e.g. MB and MiB by m.
+var byteSizePrefixes = map[string][2]ByteSize{
+	"k": {KB, KiB}, "m": {MB, MiB}, "g": {GB, GiB}, "t": {TB, TiB}, "p": {PB, PiB}, "e": {EB, EiB},
+}
+
+// parseByteSizeUnit returns the size of the unit, the empty unit being a byte.
+func parseByteSizeUnit(unit string) (ByteSize, error) {
+	u := strings.TrimSuffix(strings.ToLower(unit), "b")
+	if u == "" {
+		return Byte, nil
+	}
+	prefix, isIEC := cutSuffix(u, "i")
+	units, ok := byteSizePrefixes[prefix]
+	if !ok {
+		return 0, errParse
+	}
+	if isIEC {
+		return units[1], nil
+	}
+	return units[0], nil
+}
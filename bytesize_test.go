@@ -0,0 +1,78 @@
+package easyflag
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ByteSize
+		wantErr error
+	}{
+		{in: "0", want: 0},
+		{in: "512", want: 512},
+		{in: "512B", want: 512},
+		{in: "10MiB", want: 10 * MiB},
+		{in: "10mib", want: 10 * MiB},
+		{in: "10Mi", want: 10 * MiB},
+		{in: "4GB", want: 4 * GB},
+		{in: "4G", want: 4 * GB},
+		{in: "4 kB", want: 4 * KB},
+		{in: "1.5GiB", want: 1536 * MiB},
+		{in: "1_000KiB", want: 1000 * KiB},
+		{in: "16EiB", wantErr: errRange},
+		{in: "1.1B", wantErr: errNotInteger},
+		{in: "-1MB", wantErr: errParse},
+		{in: "1e6", wantErr: errParse},
+		{in: "10XB", wantErr: errParse},
+		{in: "MB", wantErr: errParse},
+		{in: "", wantErr: errParse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseByteSize(tt.in)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestByteSize_String(t *testing.T) {
+	tests := []struct {
+		size ByteSize
+		want string
+	}{
+		{size: 0, want: "0B"},
+		{size: 1000, want: "1kB"},
+		{size: 1023, want: "1023B"},
+		{size: 10 * MiB, want: "10MiB"},
+		{size: 4 * GB, want: "4GB"},
+		{size: 1536 * MiB, want: "1536MiB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.size.String())
+			parsed, err := ParseByteSize(tt.want)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.size, parsed)
+		})
+	}
+}
+
+func TestParseAndLoad_byteSize(t *testing.T) {
+	type sizeParams struct {
+		Buffer ByteSize `flag:"buffer|Buffer size|64KiB"`
+		Limit  ByteSize `flag:"limit|Upload limit"`
+	}
+	var p sizeParams
+	err := ParseAndLoad(&p, WithArgsSource(StaticArgs{"program", "-limit=2.5GB"}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, sizeParams{Buffer: 64 * KiB, Limit: 2500 * MB}, p)
+
+	err = ParseAndLoad(&p, WithArgsSource(StaticArgs{"program", "-limit=lots"}), WithOutput(io.Discard))
+	assert.EqualError(t, err, `invalid value "lots" for flag -limit: parse error`)
+}
@@ -0,0 +1,84 @@
+package easyflag
+
+import (
+	"io"
+)
+
+// Catalog is the machine-readable catalog of the configuration values accepted by a program. Unlike the Description,
+// it contains only the values loaded into the params structure, i.e. not the built-in flags, and it is meant
+// for the configuration management systems validating the deployment manifests against the program.
+type Catalog struct {
+	Program string         `json:"program"`
+	Version string         `json:"version,omitempty"`
+	Values  []CatalogValue `json:"values"`
+}
+
+// CatalogValue is a single configuration value of the Catalog.
+type CatalogValue struct {
+	Name        string             `json:"name"`
+	Type        string             `json:"type"`
+	Env         string             `json:"env,omitempty"`
+	Default     string             `json:"default,omitempty"` // always empty for the secret values
+	Secret      bool               `json:"secret"`
+	Reloadable  bool               `json:"reloadable"` // the value can be changed without restarting the program
+	Constraints CatalogConstraints `json:"constraints"`
+}
+
+// CatalogConstraints are the constraints of a configuration value of the Catalog.
+type CatalogConstraints struct {
+	Required   bool     `json:"required"`
+	RequiredIn []string `json:"requiredIn,omitempty"` // the environments in which the value is required
+	Choices    []string `json:"choices,omitempty"`
+}
+
+/*
+NewCatalog returns the catalog of the configuration values generated from the flag metadata of the params structure,
+which must be a pointer to a structure just like in the case of the ParseAndLoad function.
+The passed structure is not modified.
+
+The values marked by the secret tag option are flagged as secret and their default values are omitted.
+*/
+func NewCatalog(params interface{}, opts ...Option) (*Catalog, error) {
+	fb, err := newDetachedFlagBuilder(params, opts)
+	if err != nil {
+		return nil, err
+	}
+	return fb.catalog(), nil
+}
+
+// WriteCatalog writes the catalog of the configuration values returned by the NewCatalog function to w
+// in the JSON format.
+func WriteCatalog(w io.Writer, params interface{}, opts ...Option) error {
+	c, err := NewCatalog(params, opts...)
+	if err != nil {
+		return err
+	}
+	return writeIndentedJSON(w, c)
+}
+
+func (fb *flagBuilder) catalog() *Catalog {
+	c := &Catalog{
+		Program: fb.opts.programName(),
+		Version: fb.opts.version,
+		Values:  []CatalogValue{},
+	}
+	for _, f := range fb.flags {
+		v := CatalogValue{
+			Name:       f.name,
+			Type:       f.typeName(),
+			Env:        f.env,
+			Secret:     f.isSecret,
+			Reloadable: f.isReloadable,
+			Constraints: CatalogConstraints{
+				Required:   f.isRequired,
+				RequiredIn: f.requiredEnvs,
+				Choices:    f.choices,
+			},
+		}
+		if !f.isSecret {
+			v.Default = f.defaultVal
+		}
+		c.Values = append(c.Values, v)
+	}
+	return c
+}
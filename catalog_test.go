@@ -0,0 +1,53 @@
+package easyflag
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCatalog(t *testing.T) {
+	params := &struct {
+		Env      string `flag:"env|Environment|dev|choices=dev prod,reloadable"`
+		Password string `flag:"password|Database password|changeme|secret" env:"DB_PASSWORD" required_env:"prod"`
+		Port     int    `flag:"port|Server port||required"`
+	}{}
+	want := &Catalog{
+		Program: "my-tool",
+		Values: []CatalogValue{
+			{Name: "env", Type: "string", Default: "dev", Reloadable: true, Constraints: CatalogConstraints{Choices: []string{"dev", "prod"}}},
+			{Name: "password", Type: "string", Env: "DB_PASSWORD", Secret: true, Constraints: CatalogConstraints{RequiredIn: []string{"prod"}}},
+			{Name: "port", Type: "int", Constraints: CatalogConstraints{Required: true}},
+		},
+	}
+	got, err := NewCatalog(params, WithProgramName("my-tool"))
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, "", params.Env)
+}
+
+func TestWriteCatalog(t *testing.T) {
+	params := &struct {
+		Token string `flag:"token|API token||secret,required"`
+	}{}
+	want := `{
+  "program": "my-tool",
+  "version": "v1.0.0",
+  "values": [
+    {
+      "name": "token",
+      "type": "string",
+      "secret": true,
+      "reloadable": false,
+      "constraints": {
+        "required": true
+      }
+    }
+  ]
+}
+`
+	var buf bytes.Buffer
+	assert.NoError(t, WriteCatalog(&buf, params, WithProgramName("my-tool"), WithVersion("v1.0.0")))
+	assert.Equal(t, want, buf.String())
+}
@@ -0,0 +1,77 @@
+/*
+Package cobraadapter integrates the easyflag params structures into the github.com/spf13/cobra commands:
+
+	var p params
+	cmd := &cobra.Command{
+		Use: "serve",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve(p)
+		},
+	}
+	if err := cobraadapter.BindCobra(cmd, &p); err != nil {
+		[...]
+	}
+
+The flags are registered as the cobra flags including the short names defined by the short field tag.
+The environment variables, required flags, validations, post-processors and extensions are processed
+by the PreRunE function of the command before its own PreRunE or PreRun function is called.
+*/
+package cobraadapter
+
+import (
+	"flag"
+
+	"github.com/matusvla/easyflag"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// BindCobra registers the flags of the params structure as the flags of the command and wires the loading
+// of the params into the PreRunE function of the command. The params must be a pointer to a structure just like
+// in the case of the easyflag.ParseAndLoad function.
+func BindCobra(cmd *cobra.Command, params interface{}, opts ...easyflag.Option) error {
+	desc, err := easyflag.Describe(params, opts...)
+	if err != nil {
+		return err
+	}
+	shortNames := make(map[string]string, len(desc.Flags))
+	for _, f := range desc.Flags {
+		shortNames[f.Name] = f.Short
+	}
+
+	fs := flag.NewFlagSet(cmd.Name(), flag.ContinueOnError)
+	reg, err := easyflag.Register(fs, params, opts...)
+	if err != nil {
+		return err
+	}
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) {
+		pf := pflag.PFlagFromGoFlag(f)
+		if short := shortNames[f.Name]; short != "" {
+			pf.Shorthand = short
+		}
+		cmd.Flags().AddFlag(pf)
+		names = append(names, f.Name)
+	})
+
+	preRunE, preRun := cmd.PreRunE, cmd.PreRun
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		var used []string
+		for _, name := range names {
+			if cmd.Flags().Changed(name) {
+				used = append(used, name)
+			}
+		}
+		if err := reg.LoadParsed(used); err != nil {
+			return err
+		}
+		if preRunE != nil {
+			return preRunE(cmd, args)
+		}
+		if preRun != nil { // cobra ignores the PreRun function if the PreRunE function is set
+			preRun(cmd, args)
+		}
+		return nil
+	}
+	return nil
+}
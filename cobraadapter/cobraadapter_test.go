@@ -0,0 +1,107 @@
+package cobraadapter
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/matusvla/easyflag"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+type serveParams struct {
+	Port    int    `flag:"port|Server port|80" short:"p" env:"PORT"`
+	Host    string `flag:"host|Server host|localhost" env:"HOST"`
+	Token   string `flag:"token|API token||required"`
+	Verbose bool   `flag:"verbose|Verbose output" short:"v"`
+}
+
+// newServeCommand returns the command doing nothing on its own, the params are loaded by its PreRunE function.
+func newServeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use: "serve",
+		RunE: func(*cobra.Command, []string) error {
+			return nil
+		},
+	}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	return cmd
+}
+
+func TestBindCobra(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     easyflag.MapEnv
+		want    serveParams
+		wantErr string
+	}{
+		{
+			name: "flags",
+			args: []string{"--port=8080", "--token", "t", "-v"},
+			want: serveParams{Port: 8080, Host: "localhost", Token: "t", Verbose: true},
+		},
+		{
+			name: "short flags and environment",
+			args: []string{"-vp", "9090", "--token=t"},
+			env:  easyflag.MapEnv{"PORT": "8080", "HOST": "example.com"},
+			want: serveParams{Port: 9090, Host: "example.com", Token: "t", Verbose: true},
+		},
+		{
+			name:    "required flag missing",
+			args:    []string{"--port=8080"},
+			wantErr: `missing required flag "token" or its value`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p serveParams
+			cmd := newServeCommand()
+			assert.NoError(t, BindCobra(cmd, &p, easyflag.WithEnvSource(tt.env)))
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
+
+func TestBindCobra_preRun(t *testing.T) {
+	var p serveParams
+	cmd := newServeCommand()
+	var calls []string
+	cmd.PreRunE = func(*cobra.Command, []string) error {
+		// the params are already loaded when the original PreRunE function is called
+		calls = append(calls, "preRunE "+p.Host)
+		return errors.New("not ready")
+	}
+	assert.NoError(t, BindCobra(cmd, &p, easyflag.WithEnvSource(easyflag.MapEnv{"HOST": "example.com"})))
+	cmd.SetArgs([]string{"--token=t"})
+	assert.EqualError(t, cmd.Execute(), "not ready")
+	assert.Equal(t, []string{"preRunE example.com"}, calls)
+
+	cmd = newServeCommand()
+	calls = nil
+	cmd.PreRun = func(*cobra.Command, []string) {
+		calls = append(calls, "preRun")
+	}
+	assert.NoError(t, BindCobra(cmd, &p, easyflag.WithEnvSource(easyflag.MapEnv{})))
+	cmd.SetArgs([]string{"--token=t"})
+	assert.NoError(t, cmd.Execute())
+	assert.Equal(t, []string{"preRun"}, calls)
+}
+
+func TestBindCobra_duplicateShortName(t *testing.T) {
+	var p struct {
+		Verbose bool `flag:"verbose|Verbose output" short:"v"`
+		Version bool `flag:"print-version|Prints the version" short:"v"`
+	}
+	err := BindCobra(&cobra.Command{Use: "serve"}, &p)
+	assert.EqualError(t, err, "invalid field Version: short name -v of the flag -print-version already defined by the field Verbose")
+}
@@ -0,0 +1,77 @@
+package easyflag
+
+import (
+	"io"
+	"os"
+)
+
+// ColorMode specifies whether the usage message is styled by the ANSI escape sequences.
+type ColorMode int
+
+const (
+	// ColorAuto styles the usage message only if it is written to a terminal and the NO_COLOR
+	// environment variable is not set. It is the default.
+	ColorAuto ColorMode = iota
+	// ColorAlways always styles the usage message.
+	ColorAlways
+	// ColorNever never styles the usage message.
+	ColorNever
+)
+
+const (
+	ansiBold  = "\x1b[1m"
+	ansiFaint = "\x1b[2m"
+	ansiRed   = "\x1b[31m"
+	ansiReset = "\x1b[0m"
+)
+
+// style applies the ANSI styles to the parts of the usage message if it is enabled.
+type style struct {
+	enabled bool
+}
+
+func (s style) apply(code, text string) string {
+	if !s.enabled || text == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+func (s style) flagName(text string) string {
+	return s.apply(ansiBold, text)
+}
+
+func (s style) required(text string) string {
+	return s.apply(ansiRed, text)
+}
+
+func (s style) defaultValue(text string) string {
+	return s.apply(ansiFaint, text)
+}
+
+// usageStyle returns the style of the usage message written to out according to the WithColor option.
+func (fb *flagBuilder) usageStyle(out io.Writer) style {
+	switch fb.opts.color {
+	case ColorAlways:
+		return style{enabled: true}
+	case ColorNever:
+		return style{}
+	}
+	if _, ok := fb.opts.lookupEnv("NO_COLOR"); ok {
+		return style{}
+	}
+	return style{enabled: isTerminal(out)}
+}
+
+// isTerminal reports whether the writer is a character device, e.g. a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,221 @@
+package easyflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Shell is a shell for which the completion script can be generated.
+type Shell string
+
+// The shells supported by the WriteCompletion function.
+const (
+	Bash Shell = "bash"
+	Zsh  Shell = "zsh"
+	Fish Shell = "fish"
+)
+
+// ErrCompletion is the error returned by the ParseAndLoad function if the shell completion script was requested
+// by the hidden -easyflag-completion flag. The script is already printed to the standard output at that point.
+var ErrCompletion = errors.New("completion requested")
+
+// CompletionProvider returns the completion candidates of a flag value starting with the prefix,
+// e.g. the names of the resources queried from an API. The context is derived from the one set by the WithContext
+// option and it is canceled after a short timeout, because the user is waiting for the shell completion.
+type CompletionProvider func(ctx context.Context, prefix string) ([]string, error)
+
+// completionTimeout is the timeout of the CompletionProvider calls.
+const completionTimeout = 2 * time.Second
+
+// WithCompletionProvider registers the completion provider of the flag values under the name. The flags referencing
+// the name by the complete field tag, e.g. `flag:"cluster" complete:"clusters"`, are completed by the provider
+// in the generated shell completion scripts. The scripts call the program with the hidden -__complete flag,
+// e.g. program -__complete=cluster -- prefix, which prints the candidates one per line.
+func WithCompletionProvider(name string, provider CompletionProvider) Option {
+	return func(o *options) {
+		if o.completionProviders == nil {
+			o.completionProviders = make(map[string]CompletionProvider)
+		}
+		o.completionProviders[name] = provider
+	}
+}
+
+// completeValue prints the completion candidates of the flag value for the hidden -__complete flag.
+// The prefix is the first argument after the flags.
+func (fb *flagBuilder) completeValue(w io.Writer, name string) error {
+	var provider CompletionProvider
+	for _, f := range fb.flags {
+		if f.name == name && f.completer != "" {
+			provider = fb.opts.completionProviders[f.completer]
+		}
+	}
+	if provider == nil {
+		return ErrCompletion // nothing to complete
+	}
+	ctx, cancel := context.WithTimeout(fb.opts.context(), completionTimeout)
+	defer cancel()
+	var prefix string
+	if args := fb.remainingArgs(); len(args) > 0 {
+		prefix = args[0]
+	}
+	candidates, err := provider(ctx, prefix)
+	if err != nil {
+		return fmt.Errorf("completion of the flag -%s failed: %w", name, err)
+	}
+	for _, c := range candidates {
+		fmt.Fprintln(w, c)
+	}
+	return ErrCompletion
+}
+
+// UnsupportedShellError is an error returned in case that the completion script for an unknown shell is requested.
+type UnsupportedShellError struct {
+	Shell Shell
+}
+
+// Error prints the description of the UnsupportedShellError.
+func (e *UnsupportedShellError) Error() string {
+	return fmt.Sprintf("unsupported shell %q, the supported shells are %s, %s and %s", e.Shell, Bash, Zsh, Fish)
+}
+
+/*
+WriteCompletion writes the completion script for the given shell to w. The script is generated from the flag metadata
+of the params structure, which must be a pointer to a structure just like in the case of the ParseAndLoad function.
+The passed structure is not modified.
+
+The completion script can also be printed by any program using the ParseAndLoad function by the hidden
+-easyflag-completion flag, e.g.
+
+	source <(program -easyflag-completion=bash)
+*/
+func WriteCompletion(w io.Writer, shell Shell, params interface{}, opts ...Option) error {
+	fb, err := newDetachedFlagBuilder(params, opts)
+	if err != nil {
+		return err
+	}
+	return fb.writeCompletion(w, shell)
+}
+
+func (fb *flagBuilder) writeCompletion(w io.Writer, shell Shell) error {
+	flags := append(fb.builtinFlags(), fb.flags...)
+	prog := fb.opts.programName()
+	switch shell {
+	case Bash:
+		return writeBashCompletion(w, prog, flags)
+	case Zsh:
+		return writeZshCompletion(w, prog, flags)
+	case Fish:
+		return writeFishCompletion(w, prog, flags)
+	default:
+		return &UnsupportedShellError{shell}
+	}
+}
+
+var nonIdentifierRegexp = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+func writeBashCompletion(w io.Writer, prog string, flags []flagInfo) error {
+	fnName := "_" + nonIdentifierRegexp.ReplaceAllString(prog, "_") + "_completion"
+	var names, valueCases []string
+	for _, f := range flags {
+		names = append(names, "-"+f.name)
+		if f.isBool {
+			continue
+		}
+		compgen := "-f"
+		switch {
+		case len(f.choices) > 0:
+			compgen = fmt.Sprintf("-W %s", shellQuote(strings.Join(f.choices, " ")))
+		case f.completer != "":
+			compgen = fmt.Sprintf("-W \"$(%s %s=%s -- \"$cur\" 2>/dev/null)\"", prog, completeArg, f.name)
+		}
+		valueCases = append(valueCases, fmt.Sprintf("\t\t-%[1]s|--%[1]s)\n\t\t\tCOMPREPLY=($(compgen %[2]s -- \"$cur\"))\n\t\t\treturn\n\t\t\t;;", f.name, compgen))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %s\n", prog)
+	fmt.Fprintf(&b, "%s() {\n", fnName)
+	b.WriteString("\tlocal cur prev\n")
+	b.WriteString("\tcur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("\tprev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	if len(valueCases) > 0 {
+		b.WriteString("\tcase \"$prev\" in\n")
+		b.WriteString(strings.Join(valueCases, "\n"))
+		b.WriteString("\n\tesac\n")
+	}
+	fmt.Fprintf(&b, "\tCOMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shellQuote(strings.Join(names, " ")))
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -o default -F %s %s\n", fnName, prog)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeZshCompletion(w io.Writer, prog string, flags []flagInfo) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %s\n\n", prog)
+	b.WriteString("_arguments")
+	for _, f := range flags {
+		spec := fmt.Sprintf("(-%[1]s --%[1]s)'{-%[1]s,--%[1]s}'[%[2]s]", f.name, zshEscape(f.usage))
+		if !f.isBool {
+			action := "_files"
+			switch {
+			case len(f.choices) > 0:
+				action = fmt.Sprintf("(%s)", strings.Join(f.choices, " "))
+			case f.completer != "":
+				action = fmt.Sprintf("{compadd -- ${(f)\"$(%s %s=%s -- \"$PREFIX\" 2>/dev/null)\"}}", prog, completeArg, f.name)
+			}
+			message := f.name
+			if f.example != "" {
+				message += fmt.Sprintf(" (e.g. %s)", f.example)
+			}
+			spec += fmt.Sprintf(":%s:%s", zshEscape(message), zshEscape(action))
+		}
+		fmt.Fprintf(&b, " \\\n\t'%s'", spec)
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeFishCompletion(w io.Writer, prog string, flags []flagInfo) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s\n", prog)
+	for _, f := range flags {
+		line := fmt.Sprintf("complete -c %s -o %s -l %s", prog, f.name, f.name)
+		description := f.usage
+		if f.example != "" {
+			description = strings.TrimSpace(fmt.Sprintf("%s (e.g. %s)", description, f.example))
+		}
+		if description != "" {
+			line += " -d " + shellQuote(description)
+		}
+		switch {
+		case f.isBool:
+		case len(f.choices) > 0:
+			line += " -x -a " + shellQuote(strings.Join(f.choices, " "))
+		case f.completer != "":
+			line += " -x -a " + shellQuote(fmt.Sprintf("(%s %s=%s -- (commandline -ct) 2>/dev/null)", prog, completeArg, f.name))
+		default:
+			line += " -r -F"
+		}
+		b.WriteString(line + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// shellQuote quotes the string by single quotes so that it can be used in the bash and fish scripts.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// zshEscape escapes the characters with a special meaning in the zsh _arguments specs.
+// The result is meant to be enclosed in single quotes.
+func zshEscape(s string) string {
+	r := strings.NewReplacer(`'`, `'\''`, `[`, `\[`, `]`, `\]`, `:`, `\:`)
+	return r.Replace(s)
+}
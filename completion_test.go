@@ -0,0 +1,182 @@
+package easyflag
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type completionParams struct {
+	In     string `flag:"in|Input file: path|"`
+	Format string `flag:"fmt|Output format|json|choices=json yaml"`
+	IsV    bool   `flag:"v|Verbose output"`
+	Since  string `flag:"since|Start date" example:"2024-01-01"`
+	Region string `flag:"region|Cloud region" complete:"regions"`
+}
+
+func TestWriteCompletion(t *testing.T) {
+	tests := []struct {
+		name    string
+		shell   Shell
+		params  interface{}
+		want    []string
+		wantErr error
+	}{
+		{
+			name:   "bash",
+			shell:  Bash,
+			params: &completionParams{},
+			want: []string{
+				"_my_tool_completion() {",
+				"\t\t-in|--in)\n\t\t\tCOMPREPLY=($(compgen -f -- \"$cur\"))",
+				"\t\t-fmt|--fmt)\n\t\t\tCOMPREPLY=($(compgen -W 'json yaml' -- \"$cur\"))",
+				"\t\t-region|--region)\n\t\t\tCOMPREPLY=($(compgen -W \"$(my-tool -__complete=region -- \"$cur\" 2>/dev/null)\" -- \"$cur\"))",
+				"COMPREPLY=($(compgen -W '-h -help -in -fmt -v -since -region' -- \"$cur\"))",
+				"complete -o default -F _my_tool_completion my-tool\n",
+			},
+		},
+		{
+			name:   "zsh",
+			shell:  Zsh,
+			params: &completionParams{},
+			want: []string{
+				"#compdef my-tool\n",
+				"'(-in --in)'{-in,--in}'[Input file\\: path]:in:_files'",
+				"'(-fmt --fmt)'{-fmt,--fmt}'[Output format]:fmt:(json yaml)'",
+				"'(-v --v)'{-v,--v}'[Verbose output]'",
+				"'(-since --since)'{-since,--since}'[Start date]:since (e.g. 2024-01-01):_files'",
+				"'(-region --region)'{-region,--region}'[Cloud region]:region:{compadd -- ${(f)\"$(my-tool -__complete=region -- \"$PREFIX\" 2>/dev/null)\"}}'\n",
+			},
+		},
+		{
+			name:   "fish",
+			shell:  Fish,
+			params: &completionParams{},
+			want: []string{
+				"complete -c my-tool -o in -l in -d 'Input file: path' -r -F\n",
+				"complete -c my-tool -o fmt -l fmt -d 'Output format' -x -a 'json yaml'\n",
+				"complete -c my-tool -o v -l v -d 'Verbose output'\n",
+				"complete -c my-tool -o since -l since -d 'Start date (e.g. 2024-01-01)' -r -F\n",
+				"complete -c my-tool -o region -l region -d 'Cloud region' -x -a '(my-tool -__complete=region -- (commandline -ct) 2>/dev/null)'\n",
+			},
+		},
+		{
+			name:    "unsupported shell",
+			shell:   "powershell",
+			params:  &completionParams{},
+			wantErr: &UnsupportedShellError{Shell: "powershell"},
+		},
+		{
+			name:    "invalid params",
+			shell:   Bash,
+			params:  completionParams{},
+			wantErr: &InvalidParamsError{Type: reflect.TypeOf(completionParams{})},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := WriteCompletion(&buf, tt.shell, tt.params, WithProgramName("my-tool"))
+			assert.Equal(t, tt.wantErr, err)
+			for _, w := range tt.want {
+				assert.Contains(t, buf.String(), w)
+			}
+		})
+	}
+}
+
+func TestFlagBuilder_completeValue(t *testing.T) {
+	regions := func(ctx context.Context, prefix string) ([]string, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			return nil, errors.New("no deadline")
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var result []string
+		for _, r := range []string{"eu-central-1", "eu-west-1", "us-east-1"} {
+			if strings.HasPrefix(r, prefix) {
+				result = append(result, r)
+			}
+		}
+		return result, nil
+	}
+	failing := func(context.Context, string) ([]string, error) {
+		return nil, errors.New("api unavailable")
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name    string
+		flag    string
+		args    []string
+		opts    []Option
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "candidates",
+			flag:    "region",
+			args:    []string{"--", "eu-"},
+			opts:    []Option{WithCompletionProvider("regions", regions)},
+			want:    "eu-central-1\neu-west-1\n",
+			wantErr: ErrCompletion,
+		},
+		{
+			name:    "candidates with a backend",
+			flag:    "region",
+			args:    []string{"us-"},
+			opts:    []Option{WithBackend(shortBackend{}), WithCompletionProvider("regions", regions)},
+			want:    "us-east-1\n",
+			wantErr: ErrCompletion,
+		},
+		{
+			name:    "no prefix",
+			flag:    "region",
+			opts:    []Option{WithCompletionProvider("regions", regions)},
+			want:    "eu-central-1\neu-west-1\nus-east-1\n",
+			wantErr: ErrCompletion,
+		},
+		{
+			name:    "flag without provider",
+			flag:    "in",
+			opts:    []Option{WithCompletionProvider("regions", regions)},
+			wantErr: ErrCompletion,
+		},
+		{
+			name:    "provider not registered",
+			flag:    "region",
+			wantErr: ErrCompletion,
+		},
+		{
+			name:    "canceled context",
+			flag:    "region",
+			opts:    []Option{WithContext(canceled), WithCompletionProvider("regions", regions)},
+			wantErr: fmt.Errorf("completion of the flag -region failed: %w", context.Canceled),
+		},
+		{
+			name:    "provider failure",
+			flag:    "region",
+			opts:    []Option{WithCompletionProvider("regions", failing)},
+			wantErr: fmt.Errorf("completion of the flag -region failed: %w", errors.New("api unavailable")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := &completionParams{}
+			fb, err := newFlagBuilder(newOptions(tt.opts))
+			assert.NoError(t, err)
+			assert.NoError(t, fb.setUpFlags(params))
+			assert.NoError(t, fb.parseFlags(tt.args))
+			var buf bytes.Buffer
+			assert.Equal(t, tt.wantErr, fb.completeValue(&buf, tt.flag))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package easyflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+/*
+WithConfigFlag adds the reserved flag of the given name (e.g. "config") setting the path of a JSON configuration file.
+If the flag is used, the values of the flags neither used on the command line nor set by the environment variables
+are loaded from the file. The keys of the file are the flag names, e.g.
+
+	{"host": "example.com", "port": 8080, "verbose": true, "label": ["a", "b"]}
+
+The command line takes precedence over the environment, which takes precedence over the configuration file,
+which takes precedence over the default value. The keys which are not the flag names are ignored,
+unless the WithStrictConfig option is used.
+*/
+func WithConfigFlag(name string) Option {
+	return func(o *options) {
+		o.configFlag = name
+	}
+}
+
+// WithStrictConfig makes the loading of the configuration file set by the WithConfigFlag option fail if the file
+// contains a key which is not a flag name, e.g. a typo like "prot". The error reports the key and its line and column.
+func WithStrictConfig() Option {
+	return func(o *options) {
+		o.strictConfig = true
+	}
+}
+
+// ErrConfigFile matches the errors of reading, parsing or loading the configuration file set by the WithConfigFlag
+// option by the errors.Is function.
+var ErrConfigFile = errors.New("configuration file error")
+
+// configFileError is an error of the configuration file matching the ErrConfigFile error.
+type configFileError struct {
+	err error
+}
+
+func (e *configFileError) Error() string {
+	return e.err.Error()
+}
+
+func (e *configFileError) Unwrap() error {
+	return e.err
+}
+
+func (e *configFileError) Is(target error) bool {
+	return target == ErrConfigFile
+}
+
+// configFlag returns the reserved flag setting the path of the configuration file. The file is loaded by the loadConfig
+// method before the handlers of the reserved flags are run, so its handler does nothing.
+func (fb *flagBuilder) configFlag() ReservedFlag {
+	return ReservedFlag{
+		Names:   []string{fb.opts.configFlag},
+		Usage:   "Loads the flag values from the JSON configuration `file`",
+		Handler: func(string) error { return nil },
+	}
+}
+
+// loadConfig sets the flags not set on the command line or by the environment from the configuration file
+// set by the config flag, see the WithConfigFlag option.
+func (fb *flagBuilder) loadConfig() error {
+	if fb.opts.configFlag == "" {
+		return nil
+	}
+	path := fb.reservedValue(fb.opts.configFlag)
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading the configuration file: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var values map[string]interface{}
+	if err := dec.Decode(&values); err != nil {
+		return fmt.Errorf("parsing the configuration file %s: %w", path, err)
+	}
+	fb.configPath = path
+	if fb.opts.strictConfig {
+		if err := fb.checkConfigKeys(data); err != nil {
+			return err
+		}
+	}
+	for _, f := range fb.flags {
+		v, ok := values[f.name]
+		if _, used := fb.sources[f.name]; !ok || used || v == nil {
+			continue
+		}
+		if f.isJSON {
+			// the flags with the json option take the value of the key as it is, e.g. a whole object
+			b, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			v = string(b)
+		}
+		if err := fb.setConfigValue(f.name, v); err != nil {
+			return fmt.Errorf(fb.opts.messages.InvalidConfigValue, f.displayed(fmt.Sprint(v)), f.name, path, err)
+		}
+		fb.sources[f.name] = SourceConfigFile
+	}
+	return nil
+}
+
+// setConfigValue sets the flag from a value of the configuration file. The arrays set the flag repeatedly.
+func (fb *flagBuilder) setConfigValue(name string, v interface{}) error {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if err := fb.setConfigValue(name, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		return errParse
+	default:
+		return fb.flagSet.Set(name, fmt.Sprint(v))
+	}
+}
+
+// checkConfigKeys checks that all the keys of the configuration file are the flag names. The data are the content
+// of the file, which is already known to be a valid JSON object. It is decoded again token by token to find
+// the position of the first unknown key.
+func (fb *flagBuilder) checkConfigKeys(data []byte) error {
+	known := make(map[string]bool, len(fb.flags))
+	for _, f := range fb.flags {
+		known[f.name] = true
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil { // the opening brace
+		return err
+	}
+	for dec.More() {
+		start := int(dec.InputOffset())
+		for start < len(data) && (data[start] == ',' || isJSONSpace(data[start])) {
+			start++
+		}
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if key, _ := t.(string); !known[key] {
+			line, col := position(data, start)
+			return fmt.Errorf("unknown key %q at line %d, column %d of the configuration file %s", key, line, col, fb.configPath)
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// position returns the 1-based line and column of the byte offset in the data.
+func position(data []byte, offset int) (line, col int) {
+	line = 1 + bytes.Count(data[:offset], []byte("\n"))
+	col = offset - bytes.LastIndexByte(data[:offset], '\n')
+	return line, col
+}
@@ -0,0 +1,135 @@
+package easyflag
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAndLoad_configFlag(t *testing.T) {
+	type configParams struct {
+		Host    string `flag:"host|Server host|localhost"`
+		Port    int    `flag:"port|Server port|80" env:"PORT"`
+		Verbose bool   `flag:"v|Verbose output"`
+		Labels  Set    `flag:"label|Labels|x"`
+		Token   string `flag:"token|Token||required"`
+	}
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	valid := writeFile("valid.json", `{"host": "example.com", "port": 8080, "v": true, "label": ["a", "b"], "token": "t", "unknown": 1}`)
+	invalid := writeFile("invalid.json", `{"port": "eighty", "token": "t"}`)
+	object := writeFile("object.json", `{"port": {"value": 1}, "token": "t"}`)
+	malformed := writeFile("malformed.json", `{"port": `)
+
+	tests := []struct {
+		name    string
+		args    []string
+		env     MapEnv
+		want    configParams
+		wantErr string
+	}{
+		{
+			name: "config file",
+			args: []string{"-config", valid},
+			want: configParams{Host: "example.com", Port: 8080, Verbose: true, Labels: newTestSet("a", "b"), Token: "t"},
+		},
+		{
+			name: "command line and environment override config file",
+			args: []string{"-config", valid, "-host=cli.com", "-label=c"},
+			env:  MapEnv{"PORT": "1"},
+			want: configParams{Host: "cli.com", Port: 1, Verbose: true, Labels: newTestSet("c"), Token: "t"},
+		},
+		{
+			name:    "no config file",
+			wantErr: `missing required flag "token" or its value`,
+		},
+		{
+			name:    "invalid value",
+			args:    []string{"-config", invalid},
+			wantErr: `invalid value "eighty" of the key port in the configuration file ` + invalid + `: parse error`,
+		},
+		{
+			name:    "object value",
+			args:    []string{"-config", object},
+			wantErr: `invalid value "map[value:1]" of the key port in the configuration file ` + object + `: parse error`,
+		},
+		{
+			name:    "malformed file",
+			args:    []string{"-config", malformed},
+			wantErr: "parsing the configuration file " + malformed + ": unexpected EOF",
+		},
+		{
+			name:    "missing file",
+			args:    []string{"-config", filepath.Join(dir, "missing.json")},
+			wantErr: "reading the configuration file: open " + filepath.Join(dir, "missing.json") + ": no such file or directory",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = append([]string{"executable_name"}, tt.args...)
+			var p configParams
+			err := ParseAndLoad(&p, WithConfigFlag("config"), WithEnvSource(tt.env), WithOutput(io.Discard))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
+
+func TestParseAndLoad_strictConfig(t *testing.T) {
+	type configParams struct {
+		Host string `flag:"host|Server host|localhost"`
+		Port int    `flag:"port|Server port|80"`
+	}
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	valid := writeFile("valid.json", `{"host": "example.com", "port": 8080}`)
+	typo := writeFile("typo.json", "{\n  \"host\": \"example.com\",\n  \"prot\": 8080\n}")
+	nested := writeFile("nested.json", `{"host": {"prot": 1}, "a\"b": 2}`)
+
+	tests := []struct {
+		name    string
+		path    string
+		want    configParams
+		wantErr string
+	}{
+		{name: "known keys", path: valid, want: configParams{Host: "example.com", Port: 8080}},
+		{
+			name:    "unknown key",
+			path:    typo,
+			wantErr: `unknown key "prot" at line 3, column 3 of the configuration file ` + typo,
+		},
+		{
+			name:    "unknown key after a nested object",
+			path:    nested,
+			wantErr: `unknown key "a\"b" at line 1, column 23 of the configuration file ` + nested,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p configParams
+			err := ParseAndLoad(&p, WithConfigFlag("config"), WithStrictConfig(),
+				WithArgsSource(StaticArgs{"program", "-config", tt.path}), WithOutput(io.Discard))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
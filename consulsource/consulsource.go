@@ -0,0 +1,76 @@
+/*
+Package consulsource provides the easyflag remote source reading the flag values from the Consul key-value store
+and notifying about their changes:
+
+	client, err := api.NewClient(api.DefaultConfig())
+	[...]
+	src := consulsource.New(client, "my-service/")
+	err = easyflag.ParseAndLoad(&p, easyflag.WithRemoteSource(src))
+	[...]
+	go easyflag.WatchSources(ctx, reload, easyflag.WithRemoteSource(src))
+
+The key of a flag value is the prefix followed by the flag name, e.g. my-service/db-host for the db-host flag.
+The changes are detected by the blocking queries.
+*/
+package consulsource
+
+import (
+	"context"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/matusvla/easyflag"
+)
+
+type source struct {
+	kv     *api.KV
+	prefix string
+}
+
+// New returns the easyflag remote source reading the keys named by the prefix followed by the flag name.
+func New(client *api.Client, prefix string) easyflag.WatchableSource {
+	return source{kv: client.KV(), prefix: prefix}
+}
+
+// Lookup returns the value of the key named by the prefix followed by the key and whether it exists.
+func (s source) Lookup(ctx context.Context, key string) (string, bool, error) {
+	pair, _, err := s.kv.Get(s.prefix+key, (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return "", false, err
+	}
+	if pair == nil {
+		return "", false, nil
+	}
+	return string(pair.Value), true, nil
+}
+
+// Watch calls the changed function whenever a key with the prefix changes until the context is canceled.
+func (s source) Watch(ctx context.Context, changed func()) error {
+	_, meta, err := s.kv.Keys(s.prefix, "", (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return ignoreCanceled(ctx, err)
+	}
+	index := meta.LastIndex
+	for {
+		_, meta, err := s.kv.List(s.prefix, (&api.QueryOptions{WaitIndex: index}).WithContext(ctx))
+		if err != nil {
+			return ignoreCanceled(ctx, err)
+		}
+		// the index can go backwards e.g. after a snapshot restore, in which case the watch starts over
+		if meta.LastIndex < index {
+			index = 0
+			continue
+		}
+		if meta.LastIndex > index {
+			index = meta.LastIndex
+			changed()
+		}
+	}
+}
+
+// ignoreCanceled returns nil if the error is caused by the canceled context.
+func ignoreCanceled(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
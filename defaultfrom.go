@@ -0,0 +1,64 @@
+package easyflag
+
+import (
+	"fmt"
+	"strings"
+)
+
+const defaultFromPrefix = "defaultFrom="
+
+// loadInheritedDefaults sets the flags with the defaultFrom option which are not set by any source to the resolved
+// values of the flags they inherit from, e.g. the -metrics-host flag tagged by defaultFrom=host falls back
+// to the value of the -host flag. The chains of the inherited defaults are resolved in their order.
+func (fb *flagBuilder) loadInheritedDefaults() error {
+	resolved := make(map[string]bool)
+	for _, f := range fb.flags {
+		if err := fb.inheritDefault(f, resolved, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inheritDefault sets the value of the flag inheriting its default from another flag after resolving that flag.
+// The chain holds the names of the flags being resolved to detect the cycles.
+func (fb *flagBuilder) inheritDefault(f flagInfo, resolved map[string]bool, chain []string) error {
+	if f.defaultFrom == "" || resolved[f.name] {
+		return nil
+	}
+	if contains(chain, f.name) {
+		return fmt.Errorf("flag -%s: cyclic defaultFrom chain %s", f.name, joinFlagNames(append(chain, f.name)))
+	}
+	from, ok := fb.lookupFlagInfo(f.defaultFrom)
+	if !ok {
+		return fmt.Errorf("flag -%s: defaultFrom flag -%s not defined", f.name, f.defaultFrom)
+	}
+	if err := fb.inheritDefault(from, resolved, append(chain, f.name)); err != nil {
+		return err
+	}
+	resolved[f.name] = true
+	if _, used := fb.sources[f.name]; used {
+		return nil
+	}
+	val := fb.flagSet.Lookup(from.name).Value.String()
+	if err := fb.flagSet.Lookup(f.name).Value.Set(val); err != nil {
+		return fmt.Errorf("invalid value %q inherited by the flag -%s from the flag -%s: %w",
+			f.displayed(val), f.name, from.name, err)
+	}
+	return nil
+}
+
+// lookupFlagInfo returns the metadata of the attached flag with the name.
+func (fb *flagBuilder) lookupFlagInfo(name string) (flagInfo, bool) {
+	for _, f := range fb.flags {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return flagInfo{}, false
+}
+
+// joinFlagNames joins the flag names with the arrows, e.g. -a -> -b -> -a.
+func joinFlagNames(names []string) string {
+	return "-" + strings.Join(names, " -> -")
+}
@@ -0,0 +1,95 @@
+package easyflag
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAndLoad_defaultFrom(t *testing.T) {
+	type hostParams struct {
+		Host        string `flag:"host|Server host|localhost"`
+		MetricsHost string `flag:"metrics-host|Metrics server host||defaultFrom=host"`
+		AdminHost   string `flag:"admin-host|Admin server host||defaultFrom=metrics-host"`
+		Port        int    `flag:"port|Server port|8080"`
+		AdminPort   int    `flag:"admin-port|Admin server port||defaultFrom=port" env:"ADMIN_PORT"`
+	}
+	tests := []struct {
+		name string
+		args []string
+		env  MapEnv
+		want hostParams
+	}{
+		{
+			name: "inherited default values",
+			want: hostParams{Host: "localhost", MetricsHost: "localhost", AdminHost: "localhost", Port: 8080, AdminPort: 8080},
+		},
+		{
+			name: "inherited values",
+			args: []string{"-host=example.com", "-port=80"},
+			want: hostParams{
+				Host: "example.com", MetricsHost: "example.com", AdminHost: "example.com", Port: 80, AdminPort: 80,
+			},
+		},
+		{
+			name: "set values",
+			args: []string{"-host=example.com", "-metrics-host=metrics.example.com"},
+			env:  MapEnv{"ADMIN_PORT": "9090"},
+			want: hostParams{
+				Host:        "example.com",
+				MetricsHost: "metrics.example.com",
+				AdminHost:   "metrics.example.com",
+				Port:        8080,
+				AdminPort:   9090,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p hostParams
+			err := ParseAndLoad(&p, WithArgsSource(StaticArgs(append([]string{"program"}, tt.args...))),
+				WithEnvSource(tt.env), WithOutput(io.Discard))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
+
+func TestParseAndLoad_defaultFromErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  interface{}
+		wantErr string
+	}{
+		{
+			name: "undefined flag",
+			params: &struct {
+				A string `flag:"a|A||defaultFrom=b"`
+			}{},
+			wantErr: "flag -a: defaultFrom flag -b not defined",
+		},
+		{
+			name: "cycle",
+			params: &struct {
+				A string `flag:"a|A||defaultFrom=b"`
+				B string `flag:"b|B||defaultFrom=a"`
+			}{},
+			wantErr: "flag -a: cyclic defaultFrom chain -a -> -b -> -a",
+		},
+		{
+			name: "invalid inherited value",
+			params: &struct {
+				Name  string `flag:"name|Name|abc"`
+				Count int    `flag:"count|Count||defaultFrom=name"`
+			}{},
+			wantErr: `invalid value "abc" inherited by the flag -count from the flag -name: parse error`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ParseAndLoad(tt.params, WithArgsSource(StaticArgs{"program"}), WithOutput(io.Discard))
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package easyflag
+
+import (
+	"io"
+)
+
+// Description is the machine-readable description of all the flags accepted by a program.
+type Description struct {
+	Program     string            `json:"program"`
+	Version     string            `json:"version,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Examples    []string          `json:"examples,omitempty"`
+	Flags       []FlagDescription `json:"flags"`
+}
+
+// FlagDescription is the machine-readable description of a single flag.
+type FlagDescription struct {
+	Name     string   `json:"name"`
+	Short    string   `json:"short,omitempty"` // the short name of the flag used by the backends supporting it
+	Type     string   `json:"type"`
+	Usage    string   `json:"usage,omitempty"`
+	Default  string   `json:"default,omitempty"`
+	Required bool     `json:"required"`
+	Choices  []string `json:"choices,omitempty"`
+	Group    string   `json:"group,omitempty"` // the flagGroup tag or the path of the nested structure defining the flag
+	Env      string   `json:"env,omitempty"`   // the environment variable setting the flag
+	Example  string   `json:"example,omitempty"`
+	// Reloadable flags can be changed without restarting the program, see the reloadable tag option
+	Reloadable bool `json:"reloadable,omitempty"`
+}
+
+/*
+Describe returns the description of all the flags generated from the flag metadata of the params structure,
+which must be a pointer to a structure just like in the case of the ParseAndLoad function.
+The passed structure is not modified.
+
+The built-in flags (e.g. -h or -version) are described as well. The type of a flag is the name of the value used
+in the usage message for the built-in field types (string, int, uint, float, duration or bool)
+and the Go type name for the other ones (e.g. easyflag.Set).
+*/
+func Describe(params interface{}, opts ...Option) (*Description, error) {
+	fb, err := newDetachedFlagBuilder(params, opts)
+	if err != nil {
+		return nil, err
+	}
+	return fb.describe(), nil
+}
+
+// WriteJSON writes the description of all the flags returned by the Describe function to w in the JSON format.
+func WriteJSON(w io.Writer, params interface{}, opts ...Option) error {
+	d, err := Describe(params, opts...)
+	if err != nil {
+		return err
+	}
+	return writeIndentedJSON(w, d)
+}
+
+func (fb *flagBuilder) describe() *Description {
+	d := &Description{
+		Program:     fb.opts.programName(),
+		Version:     fb.opts.version,
+		Description: fb.opts.description,
+		Examples:    fb.opts.examples,
+		Flags:       []FlagDescription{},
+	}
+	for _, f := range append(fb.flags, fb.builtinFlags()...) {
+		d.Flags = append(d.Flags, FlagDescription{
+			Name:       f.name,
+			Short:      f.short,
+			Type:       f.typeName(),
+			Usage:      f.usage,
+			Default:    f.displayedDefault(),
+			Required:   f.isRequired,
+			Choices:    f.choices,
+			Group:      f.group,
+			Env:        f.env,
+			Example:    f.example,
+			Reloadable: f.isReloadable,
+		})
+	}
+	return d
+}
+
+func (f flagInfo) typeName() string {
+	switch {
+	case f.isBool:
+		return "bool"
+	case f.valueName == "value" && f.fieldType != nil:
+		return f.fieldType.String()
+	default:
+		return f.valueName
+	}
+}
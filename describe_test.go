@@ -0,0 +1,71 @@
+package easyflag
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribe(t *testing.T) {
+	type serverInfo struct {
+		Port int `flag:"p|Server port|80" env:"PORT"`
+	}
+	params := &struct {
+		Username   string `flag:"user|Username||required"`
+		Format     string `flag:"fmt|Output format|json|choices=json yaml"`
+		Labels     Set    `flag:"label|Labels" example:"a,b"`
+		ServerInfo serverInfo
+	}{}
+	want := &Description{
+		Program: "my-tool",
+		Version: "v1.2.3",
+		Flags: []FlagDescription{
+			{Name: "user", Type: "string", Usage: "Username", Required: true},
+			{Name: "fmt", Type: "string", Usage: "Output format", Default: "json", Choices: []string{"json", "yaml"}},
+			{Name: "label", Type: "easyflag.Set", Usage: "Labels", Example: "a,b"},
+			{Name: "p", Type: "int", Usage: "Server port", Default: "80", Group: "ServerInfo", Env: "PORT"},
+			{Name: "h", Type: "bool", Usage: "Prints the usage information"},
+			{Name: "help", Type: "bool", Usage: "Prints the usage information"},
+			{Name: "V", Type: "bool", Usage: "Prints the version information"},
+			{Name: "version", Type: "bool", Usage: "Prints the version information"},
+		},
+	}
+	got, err := Describe(params, WithProgramName("my-tool"), WithVersion("v1.2.3"))
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestWriteJSON(t *testing.T) {
+	params := &struct {
+		Port int `flag:"p|Server port|80|required"`
+	}{}
+	want := `{
+  "program": "my-tool",
+  "flags": [
+    {
+      "name": "p",
+      "type": "int",
+      "usage": "Server port",
+      "required": true
+    },
+    {
+      "name": "h",
+      "type": "bool",
+      "usage": "Prints the usage information",
+      "required": false
+    },
+    {
+      "name": "help",
+      "type": "bool",
+      "usage": "Prints the usage information",
+      "required": false
+    }
+  ]
+}
+`
+	var buf bytes.Buffer
+	err := WriteJSON(&buf, params, WithProgramName("my-tool"))
+	assert.NoError(t, err)
+	assert.Equal(t, want, buf.String())
+}
@@ -16,13 +16,56 @@ and an instance of this structure is filled simply by using the following code s
 		[...]
 	}
 
-Moreover, the package supports nested structures and user defined extensions executed immediately after the flag parsing.
+Moreover, the package supports nested structures, user defined validations and user defined extensions executed
+immediately after the flag parsing.
+
+The integrations with the third-party packages, e.g. the pflag backend, the cobra adapter or the HashiCorp Vault
+secret provider, are provided by the separate modules of this repository, so that this package stays free
+of the third-party dependencies.
+
+The common failures are reported as the typed errors, which can be matched by errors.Is against the
+ErrMissingRequired, ErrUnknownFlag, ErrBadDefault and ErrUnsupportedType errors, or inspected by errors.As,
+e.g. MissingRequiredError lists the names of the missing flags.
+
+Alternatively, the MustParseAndLoad function prints the error followed by the usage message and exits
+with the status code 2 (configurable by the WithErrorExitCode option) if the parsing fails.
+The WithExitCode option sets the status code of an error category matched by errors.Is, e.g. ErrUsage
+for the errors of the command line arguments or ErrConfigFile for the errors of the configuration file.
+
+The Parse function works the same way, but it returns also the Result holding the non-flag arguments remaining
+after the flags, the sources of the flag values (command line, environment or default) and the usage message renderer.
+The usage message is available without parsing as well, by the UsageString and WriteUsage functions.
 
 Flag definition
 
 Flags are defined as fields in a structure. The type of the flag corresponds to the type of the
 field and the additional flag details are described using the `flag` field tag.
-The currently supported field types are: string, bool, int, int64, uint, uint64, float64 and time.Duration.
+The currently supported field types are: string, bool, all the integer types (int, int8 to int64, uint, uint8
+to uint64), float32, float64, time.Duration, ByteSize, Set, HostPort, LogLevel, File, net.IP, net.IPNet,
+netip.Addr, netip.Prefix, *regexp.Regexp, []byte, *big.Int, *big.Rat and slog.Level (Go 1.21+).
+Moreover, any field whose pointer implements the flag.Value interface is supported as well.
+The named types with these underlying types (e.g. type Port int) are supported too.
+The fields which cannot be set up as flags, e.g. because of an unsupported type or an invalid default value,
+are reported as FieldError errors naming the path of the field.
+
+The Set type collects the values of a repeated flag (-label=a -label=b or -label=a,b) into a deduplicated set.
+The duplicate values are ignored and reported as warnings.
+
+The time.Duration values support the d (day) and w (week) units besides the ones of the time.ParseDuration function
+(e.g. 2d12h or 1w). A day is always 24 hours long.
+
+The LogLevel and slog.Level fields accept the debug, info, warn and error levels case-insensitively and the levels
+are listed in the usage message.
+
+The HostPort type holds a network address in the host:port form exposing its parts by the Host and Port methods.
+The defaultPort field tag (e.g. defaultPort:"443") sets the port used when the value contains the host only.
+
+The ByteSize type holds a number of bytes parsed from a human-readable size with the SI or IEC unit (e.g. 64KiB
+or 2.5GB).
+
+The File type holds a file opened once all the flag values are loaded and checked in the mode set by the mode flag
+option (read by default, create or append), so a failed parsing never creates or truncates it. The file is not opened if the flag is not set and the caller closes it.
+The path - stands for the standard input in the read mode and for the standard output in the other modes.
 
 The value of the flag field tag consists of four parts separated by the '|' character. Only the first value is
 mandatory.
@@ -30,23 +73,292 @@ mandatory.
 	The first value is the name of the matching CLI flag.
 	The second value is the flag's usage description.
 	The third value is the default value of this flag.
-	The fourth value is used to specify that a flag is required. This overrides the default value of the flag.
+	The fourth value is a comma separated list of the flag options.
+
+Alternatively, the flag metadata can be written in the self-describing key=value dialect, where the name, usage
+and default keys correspond to the first three parts and the other comma separated items are the flag options,
+e.g. `flag:"name=in,usage=Input file,default=/tmp/x,required"`. The dialect is recognized by the '=' character
+in the first item. The ',' character can be used in the values if it is escaped by a backslash.
+
+The usage, default value and required marker can also be defined by the separate usage, default and required
+field tags, e.g. `flag:"in" usage:"Input file" default:"in.txt" required:"false"`. A value cannot be defined
+by both the flag tag and the separate tag.
+
+The default values which cannot be expressed by a literal (e.g. the number of CPUs or the hostname) can be computed
+by the functions passed by the WithDefaultFunc option.
+
+The '|' character can be used in the parts as well if it is escaped by a backslash. Note that the backslash itself
+must be escaped in the struct tag literal, e.g. `flag:"fmt|Output format: json\\|yaml|json"`.
+
+The currently supported flag options are:
+
+	required - the flag is required. This overrides the default value of the flag (mandatory is accepted as an alias).
+	secret - the value of the flag is sensitive, e.g. a password. Its value is shown as *** in the usage message,
+	         the generated documentation, the printed configuration and the error messages.
+	prompt - the value is read interactively if the flag is not set by any other source (see the WithPrompt option).
+	hex, base64 - the value of a []byte field is decoded from the hex or base64 encoding.
+	json - the value is decoded from JSON, e.g. into a structure, map or slice field.
+	expand - a leading ~ and the $VAR and ${VAR} references of the value of a string field are expanded.
+	mustExist, mustBeDir, mustBeFile - the path passed to a string field must exist, be a directory or a regular file.
+	url, url=https - the value of a string field must be an absolute URL, optionally using one of the listed schemes.
+	notblank - the value of a string field set by any source must not be empty or whitespace-only.
+	defaultFrom=host - the flag not set by any source falls back to the resolved value of the -host flag.
+	reloadable - the flag can be changed without restarting the program (see the CheckReload function).
+	trim, keepspace, rejectspace - overrides the whitespace policy set by the WithWhitespacePolicy option.
+	choices=a b c - the space separated list of the allowed values of the flag.
+	placeholder=FILE - the name of the flag value shown in the usage message instead of the value type.
+	mode=read|create|append - the mode in which the file of a File field is opened.
+	priority=N - the flags with a higher priority are listed first in the usage message (the default priority is 0).
+
+By default, the leading and trailing whitespace of the values passed on the command line is kept as it is.
+This can be changed for all the flags by the WithWhitespacePolicy option, or for a single flag by the tag options above.
+
+A flag used more than once on the command line gets its last value. The WithRepeatedFlagPolicy option can make
+the parsing warn about it (WarnRepeated) or fail (RejectRepeated) instead.
+
+The fields without the flag field tag are ignored. The WithAutoNames option makes them flags as well,
+named after the fields, e.g. the MaxRetryCount field becomes the -max-retry-count flag. A single field can use
+the derived name by the `flag:",auto"` tag even without this option. The fields tagged by `flag:"-"` are always ignored.
+The naming convention can be changed by the WithNamingStrategy option, e.g. to SnakeCase or CamelCase.
+
+A flag can be required only in some environments by the required_env field tag listing them,
+e.g. `flag:"password" required_env:"prod,staging"`. The current environment is the value of the flag set
+by the WithEnvironmentFlag option, which can be set by an environment variable as well.
+
+An example value of a flag can be set by the example field tag, e.g. `flag:"since" example:"2024-01-01"`.
+It is shown in the usage message, the shell completion hints and the generated documentation.
+
+A flag which is not used on the command line can be set by an environment variable named by the env field tag,
+e.g. `flag:"port|Server port|80" env:"APP_PORT"`. The names of the variables can be prefixed by the WithEnvPrefix option.
+The command line takes precedence over the environment,
+which takes precedence over the default value. The environment variables are shown in the usage message
+and the generated documentation.
+
+By default, the fields of the passed structure are overwritten by the default values from the tags.
+With the WithPrepopulatedDefaults option, the non-zero values already set in the structure (e.g. loaded
+from a configuration file) are used as the default values instead, and the command line overrides them.
+
+The secret flags can be set by the file whose path is set by the environment variable with the _FILE suffix
+as well (e.g. DB_PASSWORD_FILE for the DB_PASSWORD variable), following the convention of the secrets mounted
+by Docker and Kubernetes. The trailing newlines of the file are trimmed.
 
-The fields without the flag field tag are ignored.
+The flags with the secretRef field tag (e.g. secretRef:"db/password") are set from the secret store passed
+by the WithSecretProvider option unless they are used on the command line or set by the environment.
+The github.com/matusvla/easyflag/vaultsecrets module provides the provider for HashiCorp Vault. The secrets
+are looked up with the context passed by the WithContext option, e.g. with a timeout.
+
+The WithRemoteSource option adds a source of the flag values stored remotely, e.g. in the AWS SSM Parameter Store
+(see the github.com/matusvla/easyflag/ssmsource module). The remote sources are consulted by the flag names
+for the flags not set on the command line, by the environment or by the secret store, with the context passed
+by the WithContext option. The WatchSources function
+calls a function whenever a value of the remote sources implementing the WatchableSource interface changes
+(see the github.com/matusvla/easyflag/etcdsource and github.com/matusvla/easyflag/consulsource modules).
+
+The Reloadable type holds a params structure which can be reloaded while the program is running, e.g. on SIGHUP
+by the ReloadOnSignal method. The reload parses the same command line arguments again and it replaces the current
+structure atomically only if it succeeds and the flags not marked by the reloadable tag option do not change.
+The OnChange method registers a function called with the old and the new value of a flag changed by a reload.
+
+The WithDotEnv option loads the variables from a .env file used as the fallback of the process environment.
+The file is optional and the variables set in the process environment take precedence over the ones from the file.
+
+The WithConfigFlag option adds the reserved flag (e.g. -config) setting the path of a JSON configuration file
+whose keys are the flag names. The values of the flags neither used on the command line nor set by the environment
+variables are loaded from the file, so the file takes precedence only over the default values.
+The WithStrictConfig option reports the keys which are not the flag names as errors.
+
+The flags are listed in the usage message alphabetically, just like in the native flag package.
+The required flags are marked by "(required)". When the usage message is written to a terminal, the flag names,
+required markers and default values are styled by the ANSI escape sequences unless the NO_COLOR environment
+variable is set. This can be controlled by the WithColor option (ColorAuto, ColorAlways or ColorNever).
+The WithFlagOrder(DefinitionOrder) option lists them in the order of the structure fields instead.
 
 Nested structures
 
 There is a support for nested structures as well. This reduces boilerplate code as it allows for the reuse of predefined
 blocks of CLI parameters.
 
+The flags of the nested structures are listed in separate sections of the usage message. The sections are named
+by the nested structure field names, or by the flagGroup field tag if it is present:
+
+	type params struct {
+		ServerInfo serverInfo `flagGroup:"Server options"`
+	}
+
+Caller-owned flag sets
+
+The flags of a params structure can be registered into a flag set owned by an existing application by the Bind
+function. Only the flags are registered, the parsing is left to the caller.
+The Register function registers the flags the same way, and the returned Registration loads the other sources
+in the same order as the ParseAndLoad function, checks the required flags and runs the validations, post-processors
+and extensions once the flags are parsed, e.g. by the flag.Parse function. The flag of the WithConfigFlag option
+is the only built-in flag registered into the caller's flag set.
+
+Parsing backends
+
+The command line arguments can be parsed by a backend passed by the WithBackend option instead of the native flag
+package. The github.com/matusvla/easyflag/pflagbackend module provides the backend based on the pflag package,
+which supports the POSIX-style --long flags and the -s short flags defined by the short field tag,
+e.g. `flag:"port" short:"p"`. A short name used by two flags, or equal to the name of a single-character flag,
+is reported as an error.
+
+The github.com/matusvla/easyflag/cobraadapter module registers the flags of a params structure as the flags
+of a cobra command by the BindCobra function, and it processes the environment variables, required flags,
+validations and extensions by the PreRunE function of the command.
+
+Bootstrap flags
+
+The flags configuring how the rest of the flags is parsed (e.g. -config or -profile) can be parsed first
+by the ParseBootstrap function. It fills a small bootstrap structure and ignores all the other arguments,
+including the help and the built-in flags. The bootstrap flags must be defined by the full structure as well,
+e.g. by embedding the bootstrap structure.
+The ParseKnown function works the same way, but it returns all the other arguments untouched in their original order,
+e.g. to be passed to another consumer of the arguments.
+
+Multiple params structures
+
+The configuration composed from the structures defined by several packages can be parsed at once by the WithParams
+option. All the flags share a single flag set, so a flag name defined by more than one structure is reported as an error
+naming both fields.
+
+	err := easyflag.ParseAndLoad(&httpParams, easyflag.WithParams(&dbParams, &logParams))
+
+Namespaces
+
+The flags contributed by the plugins registered at runtime can be added by the WithNamespace option.
+The flags of the passed params structure are prefixed by the namespace name and a dot (e.g. -cache.size),
+and they are listed in a separate section of the usage message. The Validate and Extend methods of the namespace
+structures are called as well.
+
+User defined validations
+
+The passed structure can implement the Validator interface if there is a need for validation of the flag values
+passed by the user. The structure's Validate method is called after the CLI flag values are loaded, but before
+any Extend method is run. The required flags are checked after the Extend methods, so that they can fill them.
+
+If any of the nested substructures implements the Validator interface, its Validate method is called as well.
+The same applies to the types of the flag fields, so the domain types (e.g. Port, Email or Percentage) can carry
+their own validation.
+
+Post-processors
+
+The application-wide policies (e.g. clamping all the timeouts) can be implemented once by the post-processors passed
+by the WithPostProcessors option. The post-processors receive the whole params structure and they are applied
+in the order in which they are passed, after all the Validate methods and before any Extend method is called.
+
 User defined extensions
 
-The passed structure can implement the Extender interface if there is a need for validation or modification
+The passed structure can implement the Extender interface if there is a need for modification
 of the flag values passed by the user.
 The structure's Extend method is then automatically called after the CLI flag values are loaded.
 
 If any of the nested substructures implements the Extender interface, its Extend method is called as well.
 
+Program description
+
+The program description and usage examples shown in the usage message above the list of the flags can be passed
+using the WithDescription and WithExamples options, or by the params structure implementing the Describer interface.
+They are included in the generated documentation as well.
+
+Version information
+
+If the version information is passed using the WithVersion option or the params structure implements
+the Versioner interface, the built-in -version and -V flags are available. If a user provides one of these,
+the version information is printed to the standard output and ParseAndLoad returns the ErrVersion error
+without checking the required flags.
+
+Argument pre-processors
+
+The raw CLI arguments can be transformed before the flags are parsed by the argument pre-processors passed
+by the WithArgsPreprocessors option, e.g. to expand aliases or to rewrite a legacy syntax.
+The pre-processors are applied in the order in which they are passed.
+
+The WithResponseFiles option replaces the arguments starting with the @ character (e.g. @args.txt) by the whitespace
+separated arguments read from the files, which is useful for the very long argument lists.
+The WithWindowsSyntax option accepts the Windows-style /name value and /name:value flags and the /? help request.
+The WithShortFlagGrouping option expands the groups of the single-character flags (e.g. -xvf archive.tar)
+for the native parser, including the short names defined by the short field tag.
+
+The WithUnknownFlags option makes the parsing tolerate the flags not defined by the params structure. They are
+returned with their values in the Unknown field of the Result instead, e.g. to be forwarded to another program.
+
+Testing
+
+The easyflagtest package provides the helpers for the tests of the flag parsing. They pass the arguments
+to the parser directly instead of replacing os.Args, use an empty environment and capture the output:
+
+	var p params
+	easyflagtest.Parse(t, &p, "-str foo -num 3")
+
+Tag parsing
+
+The ParseTag function parses the value of a flag tag by the same grammar which is used when the flags are set up,
+e.g. for the linters or documentation generators consuming the tags. It returns the FlagMetadata or a TagSyntaxError.
+
+Reserved flags
+
+The -h and -help flags, as well as the built-in -version, -V and -easyflag-completion flags are reserved,
+so they cannot be used by the fields of the params structure. Additional reserved flags with their own handlers
+can be added by the WithReservedFlag option. The handler of a reserved flag is called after the flags are parsed
+if the flag was used, and it can stop the parsing by returning an error.
+
+Shell completion
+
+The completion scripts for bash, zsh and fish can be generated from the flag metadata by the WriteCompletion function.
+Moreover, every program using the ParseAndLoad function prints its completion script if the hidden
+-easyflag-completion flag is used:
+
+	source <(program -easyflag-completion=bash)
+
+The values of a flag can be completed dynamically by a CompletionProvider registered by the WithCompletionProvider
+option and referenced by the complete field tag, e.g. `flag:"region" complete:"regions"`. The generated completion
+scripts call the program with the hidden -__complete flag to get the candidates.
+
+Man pages
+
+The man page of a program in the roff format can be generated from the flag metadata by the WriteManPage function.
+
+Markdown reference
+
+The reference of all the program flags in the Markdown format can be generated from the flag metadata
+by the WriteMarkdown function. The flags of the nested structures are listed in separate sections.
+
+Machine-readable description
+
+The full flag metadata can be obtained as a structured Go value by the Describe function,
+or in the JSON format by the WriteJSON function.
+This allows the external tools to introspect the options accepted by a program.
+
+The catalog of the configuration values (name, type, environment variable, default value, secret marker
+and constraints) can be obtained by the NewCatalog function, or in the JSON format by the WriteCatalog function.
+It is meant for the configuration management systems validating the deployment manifests.
+
+Generator flags
+
+The WithGeneratorFlags option adds the reserved -generate-docs and -generate-completion flags generating
+the documentation (man, markdown, json or catalog) and the completion scripts directly by the program binary:
+
+	program -generate-docs=man > program.1
+	program -generate-completion=bash > program.bash
+
+If one of the flags is used, the output is printed to the standard output and ParseAndLoad returns
+the ErrGenerated error.
+
+Effective configuration
+
+The Dump function writes the values of all the flags of a loaded params structure in the name = value form,
+with the values of the secret flags masked. The WithPrintConfigFlag option adds the reserved -print-config flag
+printing the same output to the standard output after the params are loaded, checked and extended.
+The Explain method of the Result returned by the Parse function writes the values together with their sources
+(command line, environment variable or default).
+
+Marshaling to arguments
+
+The MarshalArgs function returns the CLI arguments reproducing a params structure, e.g. to spawn a worker subprocess
+with the same configuration. The flags whose values are equal to their default values are omitted, except for
+the required flags. The secret flags are never included, because the command line arguments are visible to the other
+users of the system, e.g. by the ps command.
+
 Usage notes
 
 - The package does not distinguish between the flag form with one and two leading hyphens (e.g. -help and --help are
@@ -55,9 +367,44 @@ both valid, and they mean the same).
 - The allowed form of a boolean flag is either -boo without any value or -boo=true for an explicit value setup.
 This corresponds to the behavior of the native go flag package.
 
+- The boolean flags accept also the yes/no and on/off values (case-insensitively) if the WithBoolSynonyms option
+is used.
+
 - For any field type other than boolean both forms -str val and str=val are allowed.
 
+- The integer flags accept the Go integer literals including the digit separators (1_000_000) and the base
+prefixes (0x, 0o, 0b), as well as the scientific notation (1e6) as long as the value is an exact integer.
+The leading zeros are decimal, e.g. 010 is 10, the octal values need the explicit 0o prefix.
+
+- The usage message, the parsing errors and the warnings are written to the standard error output by default.
+This can be changed by the WithOutput option.
+
+- An invalid command line prints the error followed by the whole usage message, like the native flag package does.
+The WithErrorUsage option replaces the usage message by a one-line hint (UsageHintOnError) or suppresses
+the printing completely (SilentOnError). The WithErrorOutput option redirects the errors to a separate writer.
+
+- The flag definitions can be checked for the style issues by the WithLintWarnings option. The warnings
+about missing usage texts, default values equal to the zero values or default values of the required flags
+are written to the passed writer and never cause the parsing to fail.
+
+- The user-facing messages of the parse errors and the usage message can be overridden by the WithMessages option,
+e.g. to localize them. The messages of the native flag package are not affected.
+
+- Several options can be composed into a single one by the Options function, so that an organization can ship
+a standard set of the options applied consistently across all its programs. The ServerDefaults bundle contains
+the options suitable for the server programs.
+
+- The names of the flags explicitly used on the command line (never their values) can be reported to a hook set
+by the WithUsedFlagsHook option, e.g. to collect the telemetry of the flag usage.
+
+- The command line arguments are read from os.Args by default. A different source of the arguments can be set
+by the WithArgsSource option (e.g. StaticArgs), which allows reusing the same params structures in the runtimes
+without the usual process arguments, such as GOOS=js or GOOS=wasip1. Similarly, the source of the environment
+variables can be set by the WithEnvSource option (e.g. MapEnv).
+
 - There are two reserved flags -h and -help. If a user provides one of these, only the information about
-the available flags is printed and the program exits.
+the available flags is printed and the program exits. The exit function can be replaced by the WithExitFunc option,
+or the program termination can be disabled altogether by the WithoutExit option, a shorthand for
+WithExitFunc(func(int) {}).
 */
 package easyflag
@@ -0,0 +1,126 @@
+package easyflag
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+/*
+WithDotEnv loads the environment variables from the .env file at the given path, e.g. ".env", and uses them
+as the fallback of the process environment for the flags with the env field tag. The file is optional,
+so it is ignored if it does not exist. The variables set in the process environment take precedence
+over the ones from the file.
+
+The file contains one KEY=value assignment per line, optionally prefixed by the export keyword.
+The empty lines and the lines starting with # are ignored. The values can be quoted by the single quotes
+taken literally, or by the double quotes supporting the escape sequences of the Go string literals (e.g. \n).
+The unquoted values end before the " #" comment.
+*/
+func WithDotEnv(path string) Option {
+	return func(o *options) {
+		o.dotEnvPath = path
+	}
+}
+
+// fallbackEnv is an EnvSource looking up the variables in the primary source first and in the fallback one then.
+type fallbackEnv struct {
+	primary  EnvSource
+	fallback EnvSource
+}
+
+func (e fallbackEnv) LookupEnv(key string) (string, bool) {
+	if v, ok := e.primary.LookupEnv(key); ok {
+		return v, true
+	}
+	return e.fallback.LookupEnv(key)
+}
+
+// loadDotEnv loads the .env file set by the WithDotEnv option as the fallback of the environment variables.
+func (fb *flagBuilder) loadDotEnv() error {
+	if fb.opts.dotEnvPath == "" {
+		return nil
+	}
+	f, err := os.Open(fb.opts.dotEnvPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading the .env file: %w", err)
+	}
+	defer f.Close()
+	vars, err := parseDotEnv(f)
+	if err != nil {
+		return fmt.Errorf("parsing the .env file %s: %w", fb.opts.dotEnvPath, err)
+	}
+	var primary EnvSource = osEnv{}
+	if fb.opts.envSource != nil {
+		primary = fb.opts.envSource
+	}
+	fb.opts.envSource = fallbackEnv{primary, vars}
+	return nil
+}
+
+// parseDotEnv parses the variables of a .env file, see the WithDotEnv option for the description of the format.
+func parseDotEnv(r io.Reader) (MapEnv, error) {
+	vars := make(MapEnv)
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		key, val, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("invalid assignment on line %d", lineNo)
+		}
+		val, err := parseDotEnvValue(strings.TrimSpace(val))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s on line %d: %w", key, lineNo, err)
+		}
+		vars[key] = val
+	}
+	return vars, scanner.Err()
+}
+
+func parseDotEnvValue(val string) (string, error) {
+	switch {
+	case strings.HasPrefix(val, `"`):
+		end := closingQuote(val)
+		if end < 0 {
+			return "", errors.New("missing closing quote")
+		}
+		return strconv.Unquote(val[:end+1])
+	case strings.HasPrefix(val, "'"):
+		end := strings.IndexByte(val[1:], '\'')
+		if end < 0 {
+			return "", errors.New("missing closing quote")
+		}
+		return val[1 : end+1], nil
+	default:
+		if i := strings.Index(val, " #"); i >= 0 {
+			val = val[:i]
+		}
+		return strings.TrimSpace(val), nil
+	}
+}
+
+// closingQuote returns the index of the double quote closing the string starting by a double quote, or -1.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
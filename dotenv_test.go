@@ -0,0 +1,77 @@
+package easyflag
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    MapEnv
+		wantErr string
+	}{
+		{
+			name: "assignments",
+			input: `# comment
+HOST=example.com
+export PORT = 8080
+
+EMPTY=
+URL=http://example.com/#anchor # comment
+`,
+			want: MapEnv{"HOST": "example.com", "PORT": "8080", "EMPTY": "", "URL": "http://example.com/#anchor"},
+		},
+		{
+			name:  "quoted values",
+			input: `A="multi\nline # not a comment" # comment` + "\n" + `B='lit\n "x"'`,
+			want:  MapEnv{"A": "multi\nline # not a comment", "B": `lit\n "x"`},
+		},
+		{
+			name:    "missing equals sign",
+			input:   "HOST",
+			wantErr: "invalid assignment on line 1",
+		},
+		{
+			name:    "unterminated quote",
+			input:   "A=1\nB=\"x",
+			wantErr: "invalid value of B on line 2: missing closing quote",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDotEnv(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseAndLoad_dotEnv(t *testing.T) {
+	type dotEnvParams struct {
+		Host string `flag:"host|Server host|localhost" env:"HOST"`
+		Port int    `flag:"port|Server port|80" env:"PORT"`
+	}
+	path := filepath.Join(t.TempDir(), ".env")
+	assert.NoError(t, os.WriteFile(path, []byte("HOST=dotenv.com\nPORT=8080\n"), 0o600))
+
+	os.Args = []string{"executable_name"}
+	var p dotEnvParams
+	err := ParseAndLoad(&p, WithDotEnv(path), WithEnvSource(MapEnv{"PORT": "1"}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, dotEnvParams{Host: "dotenv.com", Port: 1}, p)
+
+	err = ParseAndLoad(&p, WithDotEnv(filepath.Join(t.TempDir(), ".env")), WithEnvSource(MapEnv{}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, dotEnvParams{Host: "localhost", Port: 80}, p)
+}
@@ -0,0 +1,93 @@
+package easyflag
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+)
+
+const (
+	printConfigArg = "-print-config"
+
+	// maskedValue replaces the values of the secret flags in the printed configuration
+	maskedValue = "***"
+)
+
+// ErrConfigPrinted is the error returned by the ParseAndLoad function if the effective configuration was requested
+// by the -print-config flag. The configuration is already printed to the standard output at that point.
+var ErrConfigPrinted = errors.New("configuration printed")
+
+/*
+Dump writes the values of all the flags of the params structure to w, one flag per line in the name = value form,
+e.g. to log the configuration a process is actually running with. The values of the secret flags are masked.
+The params must be a pointer to a structure just like in the case of the ParseAndLoad function, and it is expected
+to be already loaded. The flags of the structures passed by the WithNamespace and WithParams options are not included.
+*/
+func Dump(w io.Writer, params interface{}, opts ...Option) error {
+	fb, err := newDetachedFlagBuilder(params, opts)
+	if err != nil {
+		return err
+	}
+	fb.detached.Elem().Set(reflect.ValueOf(params).Elem())
+	return fb.writeConfig(w, false)
+}
+
+// WithPrintConfigFlag adds the reserved -print-config flag. If it is used, the params are loaded, checked
+// and extended as usual, and then the values of all the flags are printed the same way as by the Dump function
+// to the standard output and the ErrConfigPrinted error is returned.
+func WithPrintConfigFlag() Option {
+	return func(o *options) {
+		o.printConfigFlag = true
+	}
+}
+
+// printConfigFlag returns the reserved -print-config flag. Its handler only records the request,
+// because the configuration is printed after all the checks and extensions.
+func (fb *flagBuilder) printConfigFlag() ReservedFlag {
+	return ReservedFlag{
+		Names:  []string{printConfigArg[1:]},
+		Usage:  "Prints the effective configuration",
+		IsBool: true,
+		Handler: func(string) error {
+			fb.printConfig = true
+			return nil
+		},
+	}
+}
+
+// printConfigIfRequested prints the configuration if it was requested by the -print-config flag.
+func (fb *flagBuilder) printConfigIfRequested() error {
+	if !fb.printConfig {
+		return nil
+	}
+	if err := fb.writeConfig(os.Stdout, false); err != nil {
+		return err
+	}
+	return ErrConfigPrinted
+}
+
+// writeConfig writes the values of the flags. If explain is true, the sources of the values are written as well.
+func (fb *flagBuilder) writeConfig(w io.Writer, explain bool) error {
+	for _, f := range fb.flags {
+		if f.external && fb.detached.IsValid() {
+			continue
+		}
+		val := fb.flagSet.Lookup(f.name).Value.String()
+		switch {
+		case f.isSecret:
+			val = maskedValue
+		case f.valueName == "string":
+			val = strconv.Quote(val)
+		}
+		if explain {
+			val += " (" + fb.explainSource(f) + ")"
+		}
+		if _, err := fmt.Fprintf(w, "%s = %s\n", f.name, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
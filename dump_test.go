@@ -0,0 +1,47 @@
+package easyflag
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type dumpParams struct {
+	Host     string `flag:"host|Server host|localhost"`
+	Port     int    `flag:"port|Server port|80"`
+	Password string `flag:"password|Database password||secret"`
+	Labels   Set    `flag:"label|Labels"`
+}
+
+func TestDump(t *testing.T) {
+	p := dumpParams{Host: "example.com", Port: 8080, Password: "hunter2", Labels: newTestSet("a", "b")}
+	var buf bytes.Buffer
+	assert.NoError(t, Dump(&buf, &p))
+	want := `host = "example.com"
+port = 8080
+password = ***
+label = a,b
+`
+	assert.Equal(t, want, buf.String())
+	assert.Equal(t, "hunter2", p.Password)
+}
+
+func TestPrintConfigFlag(t *testing.T) {
+	var p dumpParams
+	fb, err := newFlagBuilder(newOptions([]Option{WithPrintConfigFlag()}))
+	assert.NoError(t, err)
+	assert.NoError(t, fb.setUpFlags(&p))
+	assert.NoError(t, fb.parseFlags([]string{"-print-config", "-port=1", "-password=x"}))
+	assert.NoError(t, fb.runReservedHandlers())
+	assert.True(t, fb.printConfig)
+	var buf bytes.Buffer
+	assert.NoError(t, fb.writeConfig(&buf, false))
+	assert.Equal(t, "host = \"localhost\"\nport = 1\npassword = ***\nlabel = \n", buf.String())
+
+	fb, err = newFlagBuilder(newOptions([]Option{WithOutput(io.Discard)}))
+	assert.NoError(t, err)
+	assert.NoError(t, fb.setUpFlags(&p))
+	assert.EqualError(t, fb.parseFlags([]string{"-print-config"}), "flag provided but not defined: -print-config")
+}
@@ -0,0 +1,85 @@
+package easyflag
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The units of the durations not supported by the time.ParseDuration function.
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// parseDuration parses a duration the same way as the time.ParseDuration function, but it supports the d (day)
+// and w (week) units as well, e.g. 2d12h or 1w. A day is always 24 hours long.
+func parseDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err == nil || !strings.ContainsAny(s, "dw") {
+		return d, err
+	}
+	invalid := fmt.Errorf("invalid duration %q", s)
+	rest := s
+	neg := strings.HasPrefix(rest, "-")
+	if neg || strings.HasPrefix(rest, "+") {
+		rest = rest[1:]
+	}
+	if rest == "" {
+		return 0, invalid
+	}
+	var total time.Duration
+	for rest != "" {
+		numEnd := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if numEnd <= 0 {
+			return 0, invalid
+		}
+		unitEnd := strings.IndexAny(rest[numEnd:], "0123456789.")
+		if unitEnd < 0 {
+			unitEnd = len(rest) - numEnd
+		}
+		num, unit := rest[:numEnd], rest[numEnd:numEnd+unitEnd]
+		rest = rest[numEnd+unitEnd:]
+		part, err := parseDurationPart(num, unit)
+		if err == errRange || err == nil && part > math.MaxInt64-total {
+			return 0, fmt.Errorf("duration %q out of range", s)
+		}
+		if err != nil {
+			return 0, invalid
+		}
+		total += part
+	}
+	if neg {
+		return -total, nil
+	}
+	return total, nil
+}
+
+// parseDurationPart parses a single number with its unit, e.g. 1.5d.
+func parseDurationPart(num, unit string) (time.Duration, error) {
+	var size time.Duration
+	switch unit {
+	case "d":
+		size = day
+	case "w":
+		size = week
+	default:
+		return time.ParseDuration(num + unit)
+	}
+	if n, err := strconv.ParseInt(num, 10, 64); err == nil {
+		if n > math.MaxInt64/int64(size) {
+			return 0, errRange
+		}
+		return time.Duration(n) * size, nil
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, errParse
+	}
+	if f*float64(size) >= math.MaxInt64 {
+		return 0, errRange
+	}
+	return time.Duration(f * float64(size)), nil
+}
@@ -0,0 +1,53 @@
+package easyflag
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr string
+	}{
+		{in: "1h30m", want: 90 * time.Minute},
+		{in: "2d12h", want: 60 * time.Hour},
+		{in: "1w", want: 168 * time.Hour},
+		{in: "1w2d3h4m5s", want: 9*24*time.Hour + 3*time.Hour + 4*time.Minute + 5*time.Second},
+		{in: "1.5d", want: 36 * time.Hour},
+		{in: "-2d", want: -48 * time.Hour},
+		{in: "+1d", want: 24 * time.Hour},
+		{in: "15250w", want: 15250 * 168 * time.Hour},
+		{in: "20000w", wantErr: `duration "20000w" out of range`},
+		{in: "d", wantErr: `invalid duration "d"`},
+		{in: "1d2x", wantErr: `invalid duration "1d2x"`},
+		{in: "-", wantErr: `time: invalid duration "-"`},
+		{in: "1y", wantErr: `time: unknown unit "y" in duration "1y"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseDuration(tt.in)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseAndLoad_durationDays(t *testing.T) {
+	type retentionParams struct {
+		Retention time.Duration `flag:"retention|Retention period|2w"`
+		Expiry    time.Duration `flag:"expiry|Expiry"`
+	}
+	var p retentionParams
+	err := ParseAndLoad(&p, WithArgsSource(StaticArgs{"program", "-expiry=1d12h"}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, retentionParams{Retention: 336 * time.Hour, Expiry: 36 * time.Hour}, p)
+}
@@ -0,0 +1,60 @@
+package easyflag
+
+import (
+	"fmt"
+	"sort"
+)
+
+/*
+WithDefaultFunc sets the function computing the default value of the flag with the name when the flags are set up,
+for the defaults which cannot be expressed by a literal in the field tag, e.g. the number of CPUs, the current user
+or the hostname:
+
+	easyflag.WithDefaultFunc("workers", func() string { return strconv.Itoa(runtime.NumCPU()) })
+
+The default value is then handled the same way as the one defined by the field tag, e.g. it is shown in the usage
+message. The flag cannot have a default value defined by the field tag as well. The names of the namespaced flags
+include the namespace, e.g. db.host.
+*/
+func WithDefaultFunc(name string, fn func() string) Option {
+	return func(o *options) {
+		if o.defaultFuncs == nil {
+			o.defaultFuncs = make(map[string]func() string)
+		}
+		o.defaultFuncs[name] = fn
+	}
+}
+
+// dynamicDefault returns the default value of the flag computed by the function set by the WithDefaultFunc option.
+// The function is called only once, because the metadata of a field can be parsed repeatedly.
+func (fb *flagBuilder) dynamicDefault(name string) (string, bool) {
+	fn, ok := fb.opts.defaultFuncs[name]
+	if !ok {
+		return "", false
+	}
+	if fb.dynamicDefaults == nil {
+		fb.dynamicDefaults = make(map[string]string)
+	}
+	val, ok := fb.dynamicDefaults[name]
+	if !ok {
+		val = fn()
+		fb.dynamicDefaults[name] = val
+	}
+	return val, true
+}
+
+// checkDefaultFuncs checks that the flags with the default values computed by the functions set
+// by the WithDefaultFunc option are defined.
+func (fb *flagBuilder) checkDefaultFuncs() error {
+	var undefined []string
+	for name := range fb.opts.defaultFuncs {
+		if fb.flagSet.Lookup(name) == nil {
+			undefined = append(undefined, name)
+		}
+	}
+	if len(undefined) == 0 {
+		return nil
+	}
+	sort.Strings(undefined)
+	return fmt.Errorf("default function set for the undefined flag -%s", undefined[0])
+}
@@ -0,0 +1,52 @@
+package easyflag
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAndLoad_defaultFunc(t *testing.T) {
+	type workerParams struct {
+		Workers int    `flag:"workers|Number of workers"`
+		User    string `flag:"user|User name"`
+	}
+	calls := 0
+	opts := []Option{
+		WithDefaultFunc("workers", func() string {
+			calls++
+			return "8"
+		}),
+		WithDefaultFunc("user", func() string { return "alice" }),
+	}
+
+	var p workerParams
+	err := ParseAndLoad(&p, append(opts, WithArgsSource(StaticArgs{"program", "-user=bob"}), WithOutput(io.Discard))...)
+	assert.NoError(t, err)
+	assert.Equal(t, workerParams{Workers: 8, User: "bob"}, p)
+	assert.Equal(t, 1, calls)
+
+	var out bytes.Buffer
+	var help workerParams
+	_ = ParseAndLoad(&help, append(opts, WithArgsSource(StaticArgs{"program", "-h"}), WithOutput(&out),
+		WithExitFunc(func(int) {}))...)
+	assert.Contains(t, out.String(), "Number of workers (default 8)")
+}
+
+func TestParseAndLoad_defaultFuncErrors(t *testing.T) {
+	fn := WithDefaultFunc("workers", func() string { return "8" })
+
+	var tagged struct {
+		Workers int `flag:"workers|Number of workers|4"`
+	}
+	err := ParseAndLoad(&tagged, fn, WithArgsSource(StaticArgs{"program"}), WithOutput(io.Discard))
+	assert.EqualError(t, err, "invalid field Workers: default value of the flag -workers defined by both the tag and the default function")
+
+	var undefined struct {
+		Threads int `flag:"threads|Number of threads"`
+	}
+	err = ParseAndLoad(&undefined, fn, WithArgsSource(StaticArgs{"program"}), WithOutput(io.Discard))
+	assert.EqualError(t, err, "default function set for the undefined flag -workers")
+}
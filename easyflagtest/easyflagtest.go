@@ -0,0 +1,89 @@
+/*
+Package easyflagtest provides the helpers testing the programs which parse their flags by the easyflag package:
+
+	func TestParams(t *testing.T) {
+		var p params
+		easyflagtest.Parse(t, &p, "-str foo -num 3")
+		[...]
+	}
+
+The helpers never touch the global state of the process: the arguments are passed to the parser directly instead
+of replacing os.Args, the environment is empty unless it is set by the easyflag.WithEnvSource option, the usage,
+the errors and the warnings are captured instead of being written to the standard error output, and the help
+does not terminate the test binary. The options passed to the helpers are applied after these defaults,
+so they can override them.
+*/
+package easyflagtest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/matusvla/easyflag"
+	"github.com/matusvla/easyflag/internal/cmdline"
+)
+
+// ProgramName is the program name passed to the parser by the helpers, e.g. shown in the usage hint.
+const ProgramName = "prog"
+
+// Outcome is the outcome of the parsing run by the Run function.
+type Outcome struct {
+	Result *easyflag.Result // nil if the parsing failed
+	Err    error
+	Output string // the usage message, the errors and the warnings written while the flags were parsed
+}
+
+// Parse parses the command line arguments into the params structure and fails the test if the parsing fails.
+// The arguments are split at whitespace and they can be quoted by the double or single quotes to contain whitespace,
+// e.g. "-name 'a b'". The params structure must be a pointer to a structure just like in the case
+// of the easyflag.Parse function.
+func Parse(t testing.TB, params interface{}, args string, opts ...easyflag.Option) *easyflag.Result {
+	t.Helper()
+	o := Run(t, params, args, opts...)
+	if o.Err != nil {
+		t.Fatalf("parsing %q failed: %v\noutput:\n%s", args, o.Err, o.Output)
+	}
+	return o.Result
+}
+
+// Run parses the command line arguments into the params structure just like the Parse function, but it returns
+// the outcome of the parsing instead of failing the test, so that the help and the parsing errors can be tested.
+func Run(t testing.TB, params interface{}, args string, opts ...easyflag.Option) Outcome {
+	t.Helper()
+	argv, err := Split(args)
+	if err != nil {
+		t.Fatalf("invalid arguments %q: %v", args, err)
+	}
+	var out bytes.Buffer
+	opts = append([]easyflag.Option{
+		easyflag.WithArgsSource(easyflag.StaticArgs(append([]string{ProgramName}, argv...))),
+		easyflag.WithEnvSource(easyflag.MapEnv{}),
+		easyflag.WithOutput(&out),
+		easyflag.WithColor(easyflag.ColorNever),
+		easyflag.WithoutExit(),
+	}, opts...)
+	res, err := easyflag.Parse(params, opts...)
+	return Outcome{Result: res, Err: err, Output: out.String()}
+}
+
+// Usage returns the usage message of the params structure and fails the test if it cannot be rendered.
+func Usage(t testing.TB, params interface{}, opts ...easyflag.Option) string {
+	t.Helper()
+	opts = append([]easyflag.Option{
+		easyflag.WithProgramName(ProgramName),
+		easyflag.WithEnvSource(easyflag.MapEnv{}),
+		easyflag.WithColor(easyflag.ColorNever),
+	}, opts...)
+	usage, err := easyflag.UsageString(params, opts...)
+	if err != nil {
+		t.Fatalf("rendering the usage failed: %v", err)
+	}
+	return usage
+}
+
+// Split splits the command line into the arguments at whitespace. The arguments can be quoted by the double
+// or single quotes to contain whitespace, e.g. "-name 'a b'" is split into -name and a b. The quoting rules
+// are the same as in the response files, see the easyflag.WithResponseFiles option.
+func Split(s string) ([]string, error) {
+	return cmdline.Split(s)
+}
@@ -0,0 +1,52 @@
+package easyflagtest
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/matusvla/easyflag"
+	"github.com/stretchr/testify/assert"
+)
+
+type testParams struct {
+	Str  string `flag:"str|String value" env:"STR"`
+	Num  int    `flag:"num|Number|1"`
+	Name string `flag:"name|Name"`
+}
+
+func TestParse(t *testing.T) {
+	osArgs := append([]string(nil), os.Args...)
+	var p testParams
+	res := Parse(t, &p, `-str foo -num 3 -name "a b" rest`)
+	assert.Equal(t, testParams{Str: "foo", Num: 3, Name: "a b"}, p)
+	assert.Equal(t, []string{"rest"}, res.Args)
+	assert.Equal(t, osArgs, os.Args)
+
+	p = testParams{}
+	Parse(t, &p, "", easyflag.WithEnvSource(easyflag.MapEnv{"STR": "env"}))
+	assert.Equal(t, testParams{Str: "env", Num: 1}, p)
+}
+
+func TestRun(t *testing.T) {
+	var p testParams
+	o := Run(t, &p, "-h")
+	assert.True(t, errors.Is(o.Err, flag.ErrHelp))
+	assert.Equal(t, Usage(t, &testParams{}), o.Output)
+
+	o = Run(t, &p, "-num x")
+	assert.EqualError(t, o.Err, `invalid value "x" for flag -num: parse error`)
+	assert.Nil(t, o.Result)
+	assert.Contains(t, o.Output, "Usage:\n")
+}
+
+func TestUsage(t *testing.T) {
+	assert.Equal(t, "Usage:\n"+
+		"  -name string\n"+
+		"    \tName\n"+
+		"  -num int\n"+
+		"    \tNumber (default 1)\n"+
+		"  -str string\n"+
+		"    \tString value (env STR)\n", Usage(t, &testParams{}))
+}
@@ -0,0 +1,117 @@
+package easyflag
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrorUsageMode defines what is printed together with the error when the command line arguments are invalid.
+type ErrorUsageMode int
+
+const (
+	// FullUsageOnError prints the error followed by the whole usage message just like the native flag package.
+	// This is the default mode.
+	FullUsageOnError ErrorUsageMode = iota
+	// UsageHintOnError prints the error followed by a one-line hint pointing to the help flag,
+	// see the UsageHint message.
+	UsageHintOnError
+	// SilentOnError prints nothing, the error is only returned, so that the application owns its presentation.
+	// The MustParseAndLoad function prints just the error then.
+	SilentOnError
+)
+
+// WithErrorUsage sets what is printed together with the error when the command line arguments are invalid.
+// The usage message requested by the help flags is printed in all the modes.
+func WithErrorUsage(mode ErrorUsageMode) Option {
+	return func(o *options) {
+		o.errorUsage = mode
+	}
+}
+
+// WithErrorOutput sets the writer to which the errors of the command line arguments and the usage message
+// or the hint printed with them are written. The help and the warnings are still written to the output set
+// by the WithOutput option.
+func WithErrorOutput(w io.Writer) Option {
+	return func(o *options) {
+		o.errorOutput = w
+	}
+}
+
+// errorWriter returns the writer of the parsing errors.
+func (o options) errorWriter() io.Writer {
+	switch {
+	case o.errorOutput != nil:
+		return o.errorOutput
+	case o.output != nil:
+		return o.output
+	}
+	return os.Stderr
+}
+
+// capturesParseErrors reports whether the errors printed by the flag parser are captured to be printed according
+// to the WithErrorUsage and WithErrorOutput options, rather than printed by the parser as they are.
+func (o options) capturesParseErrors() bool {
+	return o.errorUsage != FullUsageOnError || o.errorOutput != nil
+}
+
+// parseArgsCapturingErrors parses the command line arguments while the output of the flag set is captured.
+// The error printed by the parser and the usage message which follows it are replaced by the ones set
+// by the WithErrorUsage and WithErrorOutput options. The rest of the output (e.g. the warnings) is passed through.
+func (fb *flagBuilder) parseArgsCapturingErrors(parse func() error) error {
+	out := fb.flagSet.Output()
+	var buf bytes.Buffer
+	fb.flagSet.SetOutput(&buf)
+	fb.usageDeferred = true
+	err := parse()
+	fb.flagSet.SetOutput(out)
+	fb.usageDeferred = false
+	usageRequested := fb.usageRequested
+	fb.usageRequested = false
+
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		_, _ = out.Write(buf.Bytes())
+		if usageRequested {
+			fb.printUsage()
+		}
+		return err
+	}
+	captured := buf.String()
+	rest := strings.TrimSuffix(captured, err.Error()+"\n")
+	_, _ = io.WriteString(out, rest)
+	if rest != captured || usageRequested {
+		fb.reportParseError(err)
+	}
+	return err
+}
+
+// reportParseError prints the error of the command line arguments followed by the usage message or the hint,
+// as set by the WithErrorUsage option.
+func (fb *flagBuilder) reportParseError(err error) {
+	if !fb.opts.capturesParseErrors() {
+		fmt.Fprintln(fb.flagSet.Output(), err)
+		fb.flagSet.Usage()
+		return
+	}
+	if fb.opts.errorUsage == SilentOnError {
+		return
+	}
+	out := fb.opts.errorWriter()
+	fmt.Fprintln(out, err)
+	fb.writeErrorUsage(out)
+	fb.usagePrinted = true
+}
+
+// writeErrorUsage writes the usage message or the hint printed after an error, as set by the WithErrorUsage option.
+func (fb *flagBuilder) writeErrorUsage(out io.Writer) {
+	switch fb.opts.errorUsage {
+	case FullUsageOnError:
+		fb.writeUsage(out)
+	case UsageHintOnError:
+		fmt.Fprintf(out, fb.opts.messages.UsageHint+"\n", fb.opts.programName())
+	}
+}
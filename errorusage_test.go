@@ -0,0 +1,139 @@
+package easyflag
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAndLoad_errorUsage(t *testing.T) {
+	type usageParams struct {
+		Port  int    `flag:"port|Server port|80"`
+		Token string `flag:"token|API token||secret"`
+	}
+	const usage = "Usage:\n  -port int\n    \tServer port (default 80)\n  -token string\n    \tAPI token\n"
+	tests := []struct {
+		name          string
+		args          []string
+		opts          []Option
+		wantErr       string
+		wantOutput    string
+		wantErrOutput string
+	}{
+		{
+			name:       "full usage by default",
+			args:       []string{"-prot=80"},
+			wantErr:    "flag provided but not defined: -prot",
+			wantOutput: "flag provided but not defined: -prot\n" + usage,
+		},
+		{
+			name:       "usage hint",
+			args:       []string{"-prot=80"},
+			opts:       []Option{WithErrorUsage(UsageHintOnError)},
+			wantErr:    "flag provided but not defined: -prot",
+			wantOutput: "flag provided but not defined: -prot\nrun 'prog -h' for the usage\n",
+		},
+		{
+			name:    "silent",
+			args:    []string{"-port=x"},
+			opts:    []Option{WithErrorUsage(SilentOnError)},
+			wantErr: `invalid value "x" for flag -port: parse error`,
+		},
+		{
+			name:    "silent missing argument",
+			args:    []string{"-token"},
+			opts:    []Option{WithErrorUsage(SilentOnError)},
+			wantErr: "flag needs an argument: -token",
+		},
+		{
+			name:          "redirected error",
+			args:          []string{"-port=80", "-port=81", "-prot=80"},
+			opts:          []Option{WithRepeatedFlagPolicy(WarnRepeated)},
+			wantErr:       "flag provided but not defined: -prot",
+			wantOutput:    "warning: flag -port used 2 times, the last value is used\n",
+			wantErrOutput: "flag provided but not defined: -prot\n" + usage,
+		},
+		{
+			name:       "warnings passed through",
+			args:       []string{"-port=80", "-port=81"},
+			opts:       []Option{WithErrorUsage(SilentOnError), WithRepeatedFlagPolicy(WarnRepeated)},
+			wantOutput: "warning: flag -port used 2 times, the last value is used\n",
+		},
+		{
+			name:       "help printed in full",
+			args:       []string{"-h"},
+			opts:       []Option{WithErrorUsage(SilentOnError), WithoutExit()},
+			wantErr:    flag.ErrHelp.Error(),
+			wantOutput: usage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			opts := append([]Option{
+				WithArgsSource(StaticArgs(append([]string{"prog"}, tt.args...))),
+				WithOutput(&out),
+				WithColor(ColorNever),
+			}, tt.opts...)
+			if tt.wantErrOutput != "" {
+				opts = append(opts, WithErrorOutput(&errOut))
+			}
+			var p usageParams
+			err := ParseAndLoad(&p, opts...)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantOutput, out.String())
+			assert.Equal(t, tt.wantErrOutput, errOut.String())
+		})
+	}
+}
+
+func TestMustParseAndLoad_errorUsage(t *testing.T) {
+	type mustParams struct {
+		In string `flag:"in|Input file||required"`
+	}
+	tests := []struct {
+		name       string
+		args       []string
+		mode       ErrorUsageMode
+		wantOutput string
+	}{
+		{
+			name:       "usage hint after a flag error",
+			args:       []string{"-out=x"},
+			mode:       UsageHintOnError,
+			wantOutput: "flag provided but not defined: -out\nrun 'prog -h' for the usage\n",
+		},
+		{
+			name:       "usage hint after a missing flag",
+			mode:       UsageHintOnError,
+			wantOutput: "prog: missing required flag \"in\" or its value\nrun 'prog -h' for the usage\n",
+		},
+		{
+			name:       "silent after a flag error",
+			args:       []string{"-out=x"},
+			mode:       SilentOnError,
+			wantOutput: "prog: flag provided but not defined: -out\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			exitCode := -1
+			var p mustParams
+			MustParseAndLoad(&p,
+				WithArgsSource(StaticArgs(append([]string{"prog"}, tt.args...))),
+				WithErrorUsage(tt.mode),
+				WithOutput(&out),
+				WithExitFunc(func(code int) { exitCode = code }),
+			)
+			assert.Equal(t, 2, exitCode)
+			assert.Equal(t, tt.wantOutput, out.String())
+		})
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Package etcdsource provides the easyflag remote source reading the flag values from the etcd key-value store
+and notifying about their changes:
+
+	client, err := clientv3.New(clientv3.Config{Endpoints: []string{"localhost:2379"}})
+	[...]
+	src := etcdsource.New(client, "/my-service/")
+	err = easyflag.ParseAndLoad(&p, easyflag.WithRemoteSource(src))
+	[...]
+	go easyflag.WatchSources(ctx, reload, easyflag.WithRemoteSource(src))
+
+The key of a flag value is the prefix followed by the flag name, e.g. /my-service/db-host for the db-host flag.
+*/
+package etcdsource
+
+import (
+	"context"
+
+	"github.com/matusvla/easyflag"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+type source struct {
+	client *clientv3.Client
+	prefix string
+}
+
+// New returns the easyflag remote source reading the keys named by the prefix followed by the flag name.
+func New(client *clientv3.Client, prefix string) easyflag.WatchableSource {
+	return source{client: client, prefix: prefix}
+}
+
+// Lookup returns the value of the key named by the prefix followed by the key and whether it exists.
+func (s source) Lookup(ctx context.Context, key string) (string, bool, error) {
+	resp, err := s.client.Get(ctx, s.prefix+key)
+	if err != nil {
+		return "", false, err
+	}
+	if len(resp.Kvs) == 0 {
+		return "", false, nil
+	}
+	return string(resp.Kvs[0].Value), true, nil
+}
+
+// Watch calls the changed function whenever a key with the prefix changes until the context is canceled.
+func (s source) Watch(ctx context.Context, changed func()) error {
+	for resp := range s.client.Watch(ctx, s.prefix, clientv3.WithPrefix()) {
+		if err := resp.Err(); err != nil {
+			return err
+		}
+		if len(resp.Events) > 0 {
+			changed()
+		}
+	}
+	return nil
+}
@@ -1,5 +1,5 @@
 /*
-This is a simple program reading n bytes from a file and copying them to the stdout
+This is a simple program reading n bytes from a file (or the stdin if the path is -) and copying them to the stdout
 to illustrate the most basic usage of the easyflag package.
 
 There are two basic flags defined in the params structure: input path (-in/--in) and output length (-n/--n).
@@ -19,8 +19,8 @@ import (
 )
 
 type params struct {
-	InputPath string `flag:"in|Path to the input file||required"`
-	OutputLen int64  `flag:"n|Maximum number of characters to read (-1 for all)|-1"`
+	Input     easyflag.File `flag:"in|Path to the input file||required"`
+	OutputLen int64         `flag:"n|Maximum number of characters to read (-1 for all)|-1"`
 }
 
 func main() {
@@ -30,25 +30,21 @@ func main() {
 		log.Fatalf("error while parsing the cli parameters: %s", err.Error())
 	}
 
-	// The program "logic"
-	f, err := os.Open(p.InputPath)
-	if err != nil {
-		log.Fatalf("error while opening the input file on path %s: %s", p.InputPath, err.Error())
-	}
+	// The program "logic", the input file is already opened by the easyflag package
 	defer func() {
-		if err := f.Close(); err != nil {
+		if err := p.Input.Close(); err != nil {
 			log.Fatalf("error closing the input file: %s", err.Error())
 		}
 	}()
 
 	if p.OutputLen == -1 {
-		if _, err := io.Copy(os.Stdout, f); err != nil {
+		if _, err := io.Copy(os.Stdout, p.Input); err != nil {
 			log.Fatalf("error writing to stdout: %s", err.Error())
 		}
 		return
 	}
 
-	if _, err := io.CopyN(os.Stdout, f, p.OutputLen); err != nil {
+	if _, err := io.CopyN(os.Stdout, p.Input, p.OutputLen); err != nil {
 		log.Fatalf("error writing to stdout: %s", err.Error())
 	}
 }
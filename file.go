@@ -0,0 +1,141 @@
+package easyflag
+
+import (
+	"fmt"
+	"os"
+)
+
+const (
+	readMode   = "read"
+	createMode = "create"
+	appendMode = "append"
+
+	stdioPath = "-" // the path standing for the standard input or output
+)
+
+// File is a flag field type holding a file opened while the flags are parsed. The value of the flag is the path
+// of the file and the mode flag option declares how the file is opened:
+//
+//	mode=read   - the file is opened for reading, this is the default
+//	mode=create - the file is created or truncated and opened for reading and writing
+//	mode=append - the file is created if needed and opened for appending
+//
+// The file is opened after the validations, extensions and the required flags check, so the Validate and Extend
+// methods see the path only and a failed parsing never creates or truncates the file. The errors opening the file
+// are reported as the flag errors. The file is not opened if the flag is not set, the embedded *os.File is nil then.
+// The caller is responsible for closing the file.
+//
+// Following the Unix convention, the path - stands for the standard input in the read mode and for the standard
+// output in the create and append modes. The Close method does not close them.
+type File struct {
+	*os.File
+	path string
+}
+
+// Path returns the path of the file passed to the flag.
+func (f *File) Path() string {
+	return f.path
+}
+
+// String returns the path of the file. It implements the flag.Value interface.
+func (f *File) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.path
+}
+
+// Set records the path of the file, which is opened once all the flag values are loaded and checked.
+// It implements the flag.Value interface.
+func (f *File) Set(path string) error {
+	f.path = path
+	return nil
+}
+
+// IsStdio reports whether the file is the standard input or output passed by the path -.
+func (f *File) IsStdio() bool {
+	return f.File == os.Stdin || f.File == os.Stdout
+}
+
+// Close closes the file if it was opened and it is not the standard input or output.
+func (f *File) Close() error {
+	if f == nil || f.File == nil || f.IsStdio() {
+		return nil
+	}
+	return f.File.Close()
+}
+
+func (f *File) valueName() string {
+	return "file"
+}
+
+// fileValue is the flag value of the File fields opening the file in the mode set by the mode flag option.
+type fileValue struct {
+	*File
+	name string // the name of the flag
+	mode string
+}
+
+// open opens the file at the recorded path, if any.
+func (v *fileValue) open() error {
+	if v.path == "" || v.File.File != nil {
+		return nil
+	}
+	if v.path == stdioPath {
+		v.File.File = os.Stdin
+		if v.mode == createMode || v.mode == appendMode {
+			v.File.File = os.Stdout
+		}
+		return nil
+	}
+	var (
+		f   *os.File
+		err error
+	)
+	switch v.mode {
+	case createMode:
+		f, err = os.Create(v.path)
+	case appendMode:
+		f, err = os.OpenFile(v.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o666)
+	default:
+		f, err = os.Open(v.path)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value %q for flag -%s: %w", v.path, v.name, err)
+	}
+	v.File.File = f
+	return nil
+}
+
+// openFiles opens the files of the File flags once all the flag values are loaded and checked.
+// If any of the files cannot be opened, the already opened files are closed. The files are not opened
+// by the reloads, the Reloadable keeps the files opened by the initial parsing instead.
+func (fb *flagBuilder) openFiles() error {
+	if fb.opts.reload {
+		return nil
+	}
+	for _, fv := range fb.files {
+		if err := fv.open(); err != nil {
+			fb.closeFiles()
+			return err
+		}
+	}
+	return nil
+}
+
+// closeFiles closes the files opened by the openFiles method, e.g. if the parsing fails afterwards.
+func (fb *flagBuilder) closeFiles() {
+	for _, fv := range fb.files {
+		_ = fv.File.Close()
+		fv.File.File = nil
+	}
+}
+
+// isFileMode reports whether the mode is a supported mode of the File flags.
+func isFileMode(mode string) bool {
+	switch mode {
+	case readMode, createMode, appendMode:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package easyflag
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAndLoad_file(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in.txt")
+	assert.NoError(t, os.WriteFile(in, []byte("input"), 0o600))
+	log := filepath.Join(dir, "log.txt")
+	assert.NoError(t, os.WriteFile(log, []byte("first\n"), 0o600))
+	out := filepath.Join(dir, "out.txt")
+
+	type fileParams struct {
+		In  File `flag:"in|Input file||required"`
+		Out File `flag:"out|Output file||mode=create"`
+		Log File `flag:"log|Log file||mode=append"`
+		Opt File `flag:"opt|Optional file"`
+	}
+	var p fileParams
+	err := ParseAndLoad(&p, WithArgsSource(StaticArgs{"program", "-in", in, "-out", out, "-log", log}),
+		WithOutput(io.Discard))
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, p.In.Close())
+		assert.NoError(t, p.Out.Close())
+		assert.NoError(t, p.Log.Close())
+		assert.NoError(t, p.Opt.Close())
+	}()
+
+	b, err := io.ReadAll(p.In)
+	assert.NoError(t, err)
+	assert.Equal(t, "input", string(b))
+	assert.Equal(t, in, p.In.Path())
+
+	_, err = p.Out.WriteString("output")
+	assert.NoError(t, err)
+	b, err = os.ReadFile(out)
+	assert.NoError(t, err)
+	assert.Equal(t, "output", string(b))
+
+	_, err = p.Log.WriteString("second\n")
+	assert.NoError(t, err)
+	b, err = os.ReadFile(log)
+	assert.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", string(b))
+
+	assert.Nil(t, p.Opt.File)
+}
+
+func TestParseAndLoad_fileErrors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	tests := []struct {
+		name    string
+		params  interface{}
+		args    []string
+		wantErr string
+	}{
+		{
+			name: "missing file",
+			params: &struct {
+				In File `flag:"in|Input file"`
+			}{},
+			args:    []string{"-in", missing},
+			wantErr: `invalid value "` + missing + `" for flag -in: open ` + missing + `: no such file or directory`,
+		},
+		{
+			name: "missing required file",
+			params: &struct {
+				In File `flag:"in|Input file||required"`
+			}{},
+			wantErr: `missing required flag "in" or its value`,
+		},
+		{
+			name: "unsupported mode",
+			params: &struct {
+				In File `flag:"in|Input file||mode=write"`
+			}{},
+			wantErr: `invalid field In: unsupported file mode "write" in the fourth metadata part`,
+		},
+		{
+			name: "mode of a non-file flag",
+			params: &struct {
+				In string `flag:"in|Input file||mode=read"`
+			}{},
+			wantErr: "invalid field In: file mode not supported for the flag -in",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ParseAndLoad(tt.params, WithArgsSource(StaticArgs(append([]string{"program"}, tt.args...))),
+				WithOutput(io.Discard))
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestParseAndLoad_fileClosedOnError(t *testing.T) {
+	in := filepath.Join(t.TempDir(), "in.txt")
+	assert.NoError(t, os.WriteFile(in, nil, 0o600))
+	var p struct {
+		In  File `flag:"in|Input file"`
+		Out File `flag:"out|Output file||mode=create"`
+	}
+	err := ParseAndLoad(&p, WithArgsSource(StaticArgs{"program", "-in", in, "-out", filepath.Join(in, "out.txt")}),
+		WithOutput(io.Discard))
+	assert.Error(t, err)
+	assert.Nil(t, p.In.File)
+	assert.Nil(t, p.Out.File)
+}
+
+type fileValidatedParams struct {
+	Out File `flag:"out|Output file||mode=create"`
+	N   int  `flag:"n|Number"`
+}
+
+func (p *fileValidatedParams) Validate() error {
+	if p.N < 0 {
+		return errors.New("negative number")
+	}
+	return nil
+}
+
+func TestParseAndLoad_fileUntouchedOnError(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.txt")
+	assert.NoError(t, os.WriteFile(out, []byte("content"), 0o600))
+
+	var p struct {
+		Out File   `flag:"out|Output file||mode=create"`
+		In  string `flag:"in|Input||required"`
+	}
+	err := ParseAndLoad(&p, WithArgsSource(StaticArgs{"program", "-out", out}), WithOutput(io.Discard))
+	assert.Error(t, err)
+	data, err := os.ReadFile(out)
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(data))
+
+	var vp fileValidatedParams
+	err = ParseAndLoad(&vp, WithArgsSource(StaticArgs{"program", "-out", out, "-n=-1"}), WithOutput(io.Discard))
+	assert.Error(t, err)
+	data, err = os.ReadFile(out)
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(data))
+}
+
+func TestParseAndLoad_fileStdio(t *testing.T) {
+	var p struct {
+		In  File `flag:"in|Input file"`
+		Out File `flag:"out|Output file||mode=create"`
+		Log File `flag:"log|Log file||mode=append"`
+	}
+	err := ParseAndLoad(&p, WithArgsSource(StaticArgs{"program", "-in=-", "-out=-", "-log=-"}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Same(t, os.Stdin, p.In.File)
+	assert.Same(t, os.Stdout, p.Out.File)
+	assert.Same(t, os.Stdout, p.Log.File)
+	assert.True(t, p.In.IsStdio())
+	assert.Equal(t, "-", p.In.Path())
+
+	// the standard input and output are left open
+	assert.NoError(t, p.In.Close())
+	assert.NoError(t, p.Out.Close())
+	_, err = os.Stdout.Stat()
+	assert.NoError(t, err)
+}
@@ -4,62 +4,274 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"os"
 	"reflect"
+	"strings"
 )
 
 const (
 	helpArg      = "-help"
 	helpArgShort = "-h"
 
-	requiredValue = "required"
+	versionArg      = "-version"
+	versionArgShort = "-V"
+
+	completionArg = "-easyflag-completion"
+	completeArg   = "-__complete"
+
+	requiredValue         = "required"
+	mandatoryValue        = "mandatory" // the alias of the required option used by the legacy cli package
+	secretValue           = "secret"
+	reloadableValue       = "reloadable"
+	promptValue           = "prompt"
+	hexValue              = "hex"
+	base64Value           = "base64"
+	jsonValue             = "json"
+	expandValue           = "expand"
+	keepWhitespaceValue   = "keepspace"
+	trimWhitespaceValue   = "trim"
+	rejectWhitespaceValue = "rejectspace"
+	choicesValuePrefix    = "choices="
+	priorityValuePrefix   = "priority="
+	placeholderPrefix     = "placeholder="
+	modeValuePrefix       = "mode="
+
+	skipValue = "-"
+
+	secretFileSuffix = "_FILE"
+
+	shortKey       = "short"
+	defaultPortKey = "defaultPort"
+	nameKey        = "name"
+	usageKey       = "usage"
+	defaultKey     = "default"
 )
 
 // Extender is an interface that can be implemented by the type passed to the ParseAndLoad function.
-// It can be used for additional validation or modification of the CLI arguments
+// It can be used for additional modification of the CLI arguments
 type Extender interface {
 	Extend() error
 }
 
+// ErrVersion is the error returned by the ParseAndLoad function if the version information was requested
+// by the -version or -V flag. The version information is already printed to the standard output at that point.
+var ErrVersion = errors.New("version requested")
+
+// ErrNoArgs is the error returned by the ParseAndLoad function if the program arguments (os.Args by default) are empty,
+// which can happen in some unusual runtimes (e.g. embedded or WebAssembly ones) that do not pass even the program name.
+var ErrNoArgs = errors.New("no program arguments available, not even the program name")
+
+// Versioner is an interface that can be implemented by the type passed to the ParseAndLoad function.
+// If it is implemented, the built-in -version and -V flags printing the returned version information are available.
+type Versioner interface {
+	Version() string
+}
+
+// Describer is an interface that can be implemented by the type passed to the ParseAndLoad function.
+// The returned program description and usage examples are shown in the usage message above the list of the flags,
+// as well as in the generated documentation.
+type Describer interface {
+	Description() string
+	Examples() []string
+}
+
+// Validator is an interface that can be implemented by the type passed to the ParseAndLoad function.
+// Its Validate method is called after all the CLI flag values are loaded, but before any Extend method is run,
+// so it always sees the values exactly as passed by the user.
+type Validator interface {
+	Validate() error
+}
+
 /*
 ParseAndLoad takes a pointer to a structure and fills it from the user defined CLI flags according to the flag metadata defined as structure field tags.
 
+If the params type or any of its fields implements the Validator interface then its Validate method will be called
+once all the flag values are loaded. This can be used for the validation of the field values.
+
+The post-processors passed by the WithPostProcessors option are applied to the params after the validation.
+
 If the params type or any of its fields implements the Extender interface then its Extend method will be called at the end of the setup.
-This can be used for the validation or modification of the field values.
+This can be used for the modification of the field values.
+
+If the version information is provided either by the WithVersion option or by the params type implementing
+the Versioner interface, the -version and -V flags are available. If one of them is used, the version information
+is printed and the ErrVersion error is returned.
+
+If the help is requested by the -h or -help flag, the usage information is printed and the program exits
+with the status code 0. The exit function can be replaced by the WithExitFunc option. If the replacement returns,
+the flag.ErrHelp error is returned.
 
-In case of an error during the flag parsing, the passed structure is set to its zero value and the error is returned.
+In case of an error during the flag parsing, the passed structure is set to its zero value (or restored to its
+pre-populated values if the WithPrepopulatedDefaults option is used) and the error is returned.
 */
-func ParseAndLoad(params interface{}) (retErr error) {
-	rv := reflect.ValueOf(params)
-	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
-		return &InvalidParamsError{reflect.TypeOf(params)}
+func ParseAndLoad(params interface{}, opts ...Option) error {
+	_, err := Parse(params, opts...)
+	return err
+}
+
+// ParseAndLoadFlags is the function of the legacy cli package. It works the same way as the ParseAndLoad function.
+//
+// Deprecated: Use the ParseAndLoad function instead.
+func ParseAndLoadFlags(params interface{}, opts ...Option) error {
+	return ParseAndLoad(params, opts...)
+}
+
+// Parse works the same way as the ParseAndLoad function, but it returns the Result holding the details
+// of the parsing, such as the remaining arguments and the sources of the flag values, as well.
+// If the parsing fails, the returned Result is nil.
+func Parse(params interface{}, opts ...Option) (*Result, error) {
+	res, _, err := parse(params, opts)
+	return res, err
+}
+
+// parse implements the Parse function. It returns the flag builder as well, if it was already created,
+// even if the parsing fails.
+func parse(params interface{}, opts []Option) (_ *Result, _ *flagBuilder, retErr error) {
+	if err := checkParams(params); err != nil {
+		return nil, nil, err
 	}
+	rv := reflect.ValueOf(params)
+	o := newParamsOptions(params, opts)
 
+	// in case of an error, the structure is zeroed or restored to its pre-populated values
+	restored := reflect.Zero(rv.Elem().Type())
+	if o.prepopulated {
+		restored = reflect.New(rv.Elem().Type()).Elem()
+		restored.Set(rv.Elem())
+	}
 	defer func() {
 		if retErr != nil {
-			pEl := rv.Elem()
-			pEl.Set(reflect.Zero(pEl.Type()))
+			rv.Elem().Set(restored)
 		}
 	}()
 
-	fb := newFlagBuilder()
+	fb, err := newFlagBuilder(o)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		if retErr != nil {
+			fb.closeFiles()
+		}
+	}()
 	if err := fb.setUpFlags(params); err != nil {
-		return err
+		return nil, fb, err
+	}
+	if err := fb.setUpNamespaces(false); err != nil {
+		return nil, fb, err
+	}
+	if err := fb.checkDefaultFuncs(); err != nil {
+		return nil, fb, err
 	}
 
-	passedArgs := os.Args[1:] // first argument is a command name - we skip it
+	args := o.args()
+	if len(args) == 0 {
+		return nil, fb, ErrNoArgs
+	}
+	passedArgs := args[1:] // first argument is a command name - we skip it
+	passedArgs, err = fb.preprocessArgs(passedArgs)
+	if err != nil {
+		return nil, fb, err
+	}
+	if o.bootstrap {
+		passedArgs, fb.rest = fb.knownArgs(passedArgs)
+	}
 	if err := fb.parseFlags(passedArgs); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
-			os.Exit(0)
+			o.exit(0)
 		}
-		return err
+		return nil, fb, err
+	}
+
+	// the reserved flags, e.g. -version, are handled before the other sources are loaded, so that they work
+	// even if an environment variable is invalid or a remote source is unreachable
+	if err := fb.runReservedHandlers(); err != nil {
+		return nil, fb, err
+	}
+
+	if err := fb.loadSources(); err != nil {
+		return nil, fb, err
+	}
+
+	if err := fb.check(params); err != nil {
+		return nil, fb, err
+	}
+	if err := fb.printConfigIfRequested(); err != nil {
+		return nil, fb, err
 	}
+	return newResult(params, fb), fb, nil
+}
 
-	if err := fb.runExtensionFunctions(); err != nil {
+// loadSources sets the flags not used on the command line from the other sources in the order of their precedence:
+// the environment variables (including the .env file), the secret provider, the remote sources,
+// the configuration file and the interactive prompts.
+func (fb *flagBuilder) loadSources() error {
+	if err := fb.loadDotEnv(); err != nil {
+		return err
+	}
+	if err := fb.loadEnv(); err != nil {
+		return err
+	}
+	if err := fb.loadSecrets(); err != nil {
+		return err
+	}
+	if err := fb.loadRemote(); err != nil {
 		return err
 	}
+	if err := fb.loadConfig(); err != nil {
+		return &configFileError{err: err}
+	}
+	return fb.loadPrompts()
+}
+
+// checkParams checks that the params argument is a non-nil pointer to a structure.
+func checkParams(params interface{}) error {
+	rv := reflect.ValueOf(params)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return &InvalidParamsError{reflect.TypeOf(params)}
+	}
+	return nil
+}
+
+// newParamsOptions creates the options taking into account the optional interfaces implemented by the params.
+func newParamsOptions(params interface{}, opts []Option) options {
+	o := newOptions(opts)
+	if v, ok := params.(Versioner); ok && o.version == "" {
+		o.version = v.Version()
+	}
+	if d, ok := params.(Describer); ok {
+		if o.description == "" {
+			o.description = d.Description()
+		}
+		if len(o.examples) == 0 {
+			o.examples = d.Examples()
+		}
+	}
+	return o
+}
 
-	return fb.validate()
+// newDetachedFlagBuilder creates a flagBuilder with the flags set up on a new instance of the params type,
+// so that the passed structure is not modified. It is used by the generators of the documentation and completions.
+// The flag values point to the fields of the detached instance, so the callers reading the values of a loaded
+// structure, e.g. the Dump function, copy the structure to the detached instance first.
+func newDetachedFlagBuilder(params interface{}, opts []Option) (*flagBuilder, error) {
+	if err := checkParams(params); err != nil {
+		return nil, err
+	}
+	fb, err := newFlagBuilder(newParamsOptions(params, opts))
+	if err != nil {
+		return nil, err
+	}
+	fb.detached = reflect.New(reflect.TypeOf(params).Elem())
+	if err := fb.setUpFlags(fb.detached.Interface()); err != nil {
+		return nil, err
+	}
+	if err := fb.setUpNamespaces(true); err != nil {
+		return nil, err
+	}
+	if err := fb.checkDefaultFuncs(); err != nil {
+		return nil, err
+	}
+	return fb, nil
 }
 
 // InvalidParamsError is an error returned in case that the params argument passed to the ParseAndLoad function is not a pointer to a structure.
@@ -79,3 +291,162 @@ func (e *InvalidParamsError) Error() string {
 	}
 	return fmt.Sprintf(outputFmt, e.Type.String())
 }
+
+// TagSyntaxError is an error returned in case that the flag tag of a params structure field is invalid,
+// e.g. it defines an empty flag name.
+type TagSyntaxError struct {
+	Field  string // the path of the field within the params structure, e.g. Server.Port, empty if not known
+	Tag    string // the value of the flag tag
+	Reason string
+}
+
+// Error prints the description of the TagSyntaxError.
+func (e *TagSyntaxError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("invalid flag tag %q: %s", e.Tag, e.Reason)
+	}
+	return fmt.Sprintf("invalid flag tag %q of the field %s: %s", e.Tag, e.Field, e.Reason)
+}
+
+// FieldError is an error returned in case that the flag of a params structure field cannot be set up,
+// e.g. because of an unsupported field type or an invalid default value.
+type FieldError struct {
+	Field string // the path of the field within the params structure, e.g. Server.Limits
+	Err   error
+}
+
+// Error prints the description of the FieldError.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("invalid field %s: %v", e.Field, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	// ErrUsage matches the errors of the command line arguments by the errors.Is function, e.g. the unknown flags,
+	// the invalid flag values or the missing required flags.
+	ErrUsage = errors.New("usage error")
+	// ErrMissingRequired matches the MissingRequiredError errors by the errors.Is function.
+	ErrMissingRequired = errors.New("missing required flags")
+	// ErrUnknownFlag matches the UnknownFlagError errors by the errors.Is function.
+	ErrUnknownFlag = errors.New("unknown flag")
+	// ErrBadDefault matches the BadDefaultError errors by the errors.Is function.
+	ErrBadDefault = errors.New("bad default value")
+	// ErrUnsupportedType matches the UnsupportedTypeError errors by the errors.Is function.
+	ErrUnsupportedType = errors.New("unsupported flag type")
+)
+
+// MissingRequiredError is an error returned in case that some required flags are not set by any source.
+type MissingRequiredError struct {
+	Flags  []string // the names of the missing flags, sorted
+	format string   // the MissingRequiredFlag or MissingRequiredFlags message
+}
+
+// Error prints the description of the MissingRequiredError.
+func (e *MissingRequiredError) Error() string {
+	format := e.format
+	if format == "" {
+		format = defaultMessages.MissingRequiredFlags
+		if len(e.Flags) == 1 {
+			format = defaultMessages.MissingRequiredFlag
+		}
+	}
+	return fmt.Sprintf(format, strings.Join(e.Flags, ", "))
+}
+
+// Is reports whether the target is the ErrMissingRequired or the ErrUsage error.
+func (e *MissingRequiredError) Is(target error) bool {
+	return target == ErrMissingRequired || target == ErrUsage
+}
+
+// UnknownFlagError is an error returned in case that the command line arguments contain a flag which is not defined.
+type UnknownFlagError struct {
+	Flag string // the name of the flag without the leading hyphens
+	Err  error  // the error of the flag parser
+}
+
+// Error prints the description of the UnknownFlagError.
+func (e *UnknownFlagError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("flag provided but not defined: -%s", e.Flag)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the error of the flag parser.
+func (e *UnknownFlagError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is the ErrUnknownFlag or the ErrUsage error.
+func (e *UnknownFlagError) Is(target error) bool {
+	return target == ErrUnknownFlag || target == ErrUsage
+}
+
+// usageError is an error of the command line arguments matching the ErrUsage error.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+func (e *usageError) Is(target error) bool {
+	return target == ErrUsage
+}
+
+// asUsageError makes the error of the command line arguments match the ErrUsage error.
+// The help requests are not the usage errors.
+func asUsageError(err error) error {
+	if errors.Is(err, flag.ErrHelp) || errors.Is(err, ErrUsage) {
+		return err
+	}
+	return &usageError{err: err}
+}
+
+// BadDefaultError is an error returned in case that the default value of a flag is not valid.
+// It is reported wrapped by the FieldError naming the field of the flag.
+type BadDefaultError struct {
+	Flag  string // the name of the flag
+	Value string // the default value
+	Err   error
+}
+
+// Error prints the description of the BadDefaultError.
+func (e *BadDefaultError) Error() string {
+	return fmt.Sprintf("invalid default value of the flag -%s: %v", e.Flag, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *BadDefaultError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is the ErrBadDefault error.
+func (e *BadDefaultError) Is(target error) bool {
+	return target == ErrBadDefault
+}
+
+// UnsupportedTypeError is an error returned in case that the type of a params structure field is not supported.
+// It is reported wrapped by the FieldError naming the field.
+type UnsupportedTypeError struct {
+	Type reflect.Type
+}
+
+// Error prints the description of the UnsupportedTypeError.
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("unsupported flag type: %s", e.Type)
+}
+
+// Is reports whether the target is the ErrUnsupportedType error.
+func (e *UnsupportedTypeError) Is(target error) bool {
+	return target == ErrUnsupportedType
+}
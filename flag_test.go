@@ -1,11 +1,14 @@
 package easyflag
 
 import (
+	"bytes"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
@@ -41,6 +44,7 @@ func TestParseFlags(t *testing.T) {
 		name      string
 		cliParams []string
 		arg       interface{}
+		opts      []Option
 		want      want
 	}{
 		{
@@ -130,12 +134,88 @@ func TestParseFlags(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:      "success - set with duplicates",
+			cliParams: []string{"-label=a", "-label", "b,a", "-label=c"},
+			arg: &struct {
+				Labels Set `flag:"label|Testing set|x,y|"`
+			}{},
+			want: want{
+				params: &struct {
+					Labels Set `flag:"label|Testing set|x,y|"`
+				}{
+					Labels: newTestSet("a", "b", "c"),
+				},
+			},
+		},
+		{
+			name:      "success - set default",
+			cliParams: []string{},
+			arg: &struct {
+				Labels Set `flag:"label|Testing set|x,y|"`
+			}{},
+			want: want{
+				params: &struct {
+					Labels Set `flag:"label|Testing set|x,y|"`
+				}{
+					Labels: newTestSet("x", "y"),
+				},
+			},
+		},
+		{
+			name:      "success boolean synonyms",
+			cliParams: []string{"-boo=YES", "-boo2=off", "-boo3=On"},
+			arg: &struct {
+				Boo  bool `flag:"boo"`
+				Boo2 bool `flag:"boo2|Testing boolean|true"`
+				Boo3 bool `flag:"boo3"`
+			}{},
+			opts: []Option{WithBoolSynonyms()},
+			want: want{
+				params: &struct {
+					Boo  bool `flag:"boo"`
+					Boo2 bool `flag:"boo2|Testing boolean|true"`
+					Boo3 bool `flag:"boo3"`
+				}{
+					Boo:  true,
+					Boo2: false,
+					Boo3: true,
+				},
+			},
+		},
+		{
+			name:      "fail - single letter boolean synonym",
+			cliParams: []string{"-boo=y"},
+			arg: &struct {
+				Boo bool `flag:"boo"`
+			}{},
+			opts: []Option{WithBoolSynonyms()},
+			want: want{
+				err: &usageError{err: errors.New("invalid boolean value \"y\" for -boo: parse error")},
+				params: &struct {
+					Boo bool `flag:"boo"`
+				}{},
+			},
+		},
+		{
+			name:      "fail - boolean synonyms not enabled",
+			cliParams: []string{"-boo=yes"},
+			arg: &struct {
+				Boo bool `flag:"boo"`
+			}{},
+			want: want{
+				err: &usageError{err: errors.New("invalid boolean value \"yes\" for -boo: parse error")},
+				params: &struct {
+					Boo bool `flag:"boo"`
+				}{},
+			},
+		},
 		{
 			name:      "fail - invalid flags",
 			cliParams: []string{"-str=asdf", "-str2", "fdsa", "-unum=10", "-random", "stuff"},
 			arg:       &Params{},
 			want: want{
-				err:    errors.New("flag provided but not defined: -random"),
+				err:    &UnknownFlagError{Flag: "random", Err: errors.New("flag provided but not defined: -random")},
 				params: &Params{},
 			},
 		},
@@ -144,7 +224,7 @@ func TestParseFlags(t *testing.T) {
 			cliParams: []string{"-str=asdf"},
 			arg:       &Params{},
 			want: want{
-				err:    errors.New("missing required flag \"unum\" or its value"),
+				err:    &MissingRequiredError{Flags: []string{"unum"}, format: defaultMessages.MissingRequiredFlag},
 				params: &Params{},
 			},
 		},
@@ -158,144 +238,1360 @@ func TestParseFlags(t *testing.T) {
 				params: &struct {
 					Boo bool `flag:"h"`
 				}{},
-				err: errors.New("reserved flag -h overwriting not allowed"),
+				err: &FieldError{Field: "Boo", Err: errors.New("reserved flag -h overwriting not allowed")},
+			},
+		},
+		{
+			name:      "fail - trying to overwrite the long help flag",
+			cliParams: []string{""},
+			arg: &struct {
+				Boo bool `flag:"help"`
+			}{},
+			want: want{
+				params: &struct {
+					Boo bool `flag:"help"`
+				}{},
+				err: &FieldError{Field: "Boo", Err: errors.New("reserved flag -help overwriting not allowed")},
+			},
+		},
+		{
+			name:      "success - nested params",
+			cliParams: []string{"-str=asdf", "-str2", "fdsa", "-boo", "-num=15", "-num64", "16", "-unum=17", "-unum64=18", "-dur=5m"},
+			arg:       &NestedParams{},
+			want: want{
+				params: &NestedParams{
+					Params: Params{
+						Str:       "asdf",
+						Str2:      "fdsa",
+						Boo:       true,
+						Number:    15,
+						ExtNumber: 9999999,
+						Number64:  16,
+						UNumber:   17,
+						UNumber64: 18,
+						Float64:   123.456,
+						Dur:       5 * time.Minute,
+					},
+					AnotherThing: "extended",
+				},
+			},
+		},
+		{
+			name:      "fail - params not a pointer",
+			cliParams: []string{"-str=asdf", "-str2", "fdsa", "-boo", "-num=15", "-num64", "16", "-unum=17", "-unum64=18", "-dur=5m"},
+			arg:       Params{},
+			want: want{
+				err: &InvalidParamsError{
+					Type: reflect.TypeOf(Params{}),
+				},
+				params: Params{},
+			},
+		},
+		{
+			name:      "fail - nil params",
+			cliParams: []string{"-str=asdf", "-str2", "fdsa", "-boo", "-num=15", "-num64", "16", "-unum=17", "-unum64=18", "-dur=5m"},
+			arg:       nil,
+			want: want{
+				err: &InvalidParamsError{
+					Type: reflect.TypeOf(nil),
+				},
+				params: nil,
+			},
+		},
+		{
+			name:      "fail - params pointer, but not a struct",
+			cliParams: []string{"-str=asdf", "-str2", "fdsa", "-boo", "-num=15", "-num64", "16", "-unum=17", "-unum64=18", "-dur=5m"},
+			arg:       strPointer,
+			want: want{
+				err: &InvalidParamsError{
+					Type: reflect.TypeOf(strPointer),
+				},
+				params: strPointer,
+			},
+		},
+		{
+			name:      "fail in extension",
+			cliParams: []string{},
+			arg:       &FailingParams{},
+			want: want{
+				err:    fmt.Errorf("extension running failed: %w", failingParamsErr),
+				params: &FailingParams{},
+			},
+		},
+		{
+			name:      "success - required flag filled by extension",
+			cliParams: []string{"-host=example.com"},
+			arg:       &ExtendedRequiredParams{},
+			want: want{
+				params: &ExtendedRequiredParams{
+					Host: "example.com",
+					Addr: "example.com:80",
+				},
+			},
+		},
+		{
+			name:      "fail - required flag not filled by extension",
+			cliParams: []string{},
+			arg:       &ExtendedRequiredParams{},
+			want: want{
+				err:    &MissingRequiredError{Flags: []string{"addr"}, format: defaultMessages.MissingRequiredFlag},
+				params: &ExtendedRequiredParams{},
+			},
+		},
+		{
+			name:      "success - validation before extension",
+			cliParams: []string{"-port=8080"},
+			arg:       &ValidatedParams{},
+			want: want{
+				params: &ValidatedParams{
+					Port:    8080,
+					Address: ":8080",
+				},
+			},
+		},
+		{
+			name:      "fail in validation",
+			cliParams: []string{"-port=100000"},
+			arg:       &ValidatedParams{},
+			want: want{
+				err:    fmt.Errorf("validation failed: %w", errors.New("port 100000 out of range")),
+				params: &ValidatedParams{},
+			},
+		},
+		{
+			name:      "fail in nested validation",
+			cliParams: []string{"-port=0"},
+			arg: &struct {
+				Server ValidatedParams
+			}{},
+			want: want{
+				err: fmt.Errorf("validation failed: %w", errors.New("port 0 out of range")),
+				params: &struct {
+					Server ValidatedParams
+				}{},
+			},
+		},
+		{
+			name:      "success - field type validator",
+			cliParams: []string{"-port=8080"},
+			arg: &struct {
+				Port Port `flag:"port|Testing port|80|"`
+			}{},
+			want: want{
+				params: &struct {
+					Port Port `flag:"port|Testing port|80|"`
+				}{
+					Port: 8080,
+				},
+			},
+		},
+		{
+			name:      "fail in field type validator",
+			cliParams: []string{"-port=0"},
+			arg: &struct {
+				Port Port `flag:"port|Testing port|80|"`
+			}{},
+			want: want{
+				err: fmt.Errorf("validation failed: %w", fmt.Errorf("flag -port: %w", errors.New("port 0 out of range"))),
+				params: &struct {
+					Port Port `flag:"port|Testing port|80|"`
+				}{},
+			},
+		},
+		{
+			name:      "fail - unsupported type",
+			cliParams: []string{},
+			arg: &struct {
+				Limits map[string]int `flag:"limits"`
+			}{},
+			want: want{
+				err: &FieldError{Field: "Limits", Err: &UnsupportedTypeError{Type: reflect.TypeOf(map[string]int{})}},
+				params: &struct {
+					Limits map[string]int `flag:"limits"`
+				}{},
+			},
+		},
+		{
+			name:      "version requested",
+			cliParams: []string{"-version"},
+			arg:       &Params{},
+			opts:      []Option{WithVersion("v1.2.3")},
+			want: want{
+				err:    ErrVersion,
+				params: &Params{},
+			},
+		},
+		{
+			name:      "version requested - versioner",
+			cliParams: []string{"-V"},
+			arg:       &VersionedParams{},
+			want: want{
+				err:    ErrVersion,
+				params: &VersionedParams{},
+			},
+		},
+		{
+			name:      "success - version not requested",
+			cliParams: []string{"-str=asdf"},
+			arg:       &VersionedParams{},
+			want: want{
+				params: &VersionedParams{Str: "asdf"},
+			},
+		},
+		{
+			name:      "fail - trying to overwrite the version flag",
+			cliParams: []string{},
+			arg: &struct {
+				Version bool `flag:"version"`
+			}{},
+			opts: []Option{WithVersion("v1.2.3")},
+			want: want{
+				params: &struct {
+					Version bool `flag:"version"`
+				}{},
+				err: &FieldError{Field: "Version", Err: errors.New("reserved flag -version overwriting not allowed")},
+			},
+		},
+		{
+			name:      "success - whitespace trimmed",
+			cliParams: []string{"-str= asdf ", "-num", "15 ", "-keep= x "},
+			arg: &struct {
+				Str  string `flag:"str|Testing string|"`
+				Num  int    `flag:"num|Testing number|"`
+				Keep string `flag:"keep|Testing string||keepspace"`
+			}{},
+			opts: []Option{WithWhitespacePolicy(TrimWhitespace)},
+			want: want{
+				params: &struct {
+					Str  string `flag:"str|Testing string|"`
+					Num  int    `flag:"num|Testing number|"`
+					Keep string `flag:"keep|Testing string||keepspace"`
+				}{
+					Str:  "asdf",
+					Num:  15,
+					Keep: " x ",
+				},
+			},
+		},
+		{
+			name:      "success - whitespace trimmed in a single field",
+			cliParams: []string{"-num", "15 "},
+			arg: &struct {
+				Num int `flag:"num|Testing number||trim"`
+			}{},
+			want: want{
+				params: &struct {
+					Num int `flag:"num|Testing number||trim"`
+				}{
+					Num: 15,
+				},
+			},
+		},
+		{
+			name:      "fail - whitespace rejected",
+			cliParams: []string{"-str=asdf ", "-num=1"},
+			arg: &struct {
+				Str string `flag:"str|Testing string||required,rejectspace"`
+			}{},
+			want: want{
+				err: &usageError{err: errors.New("invalid value \"asdf \" for flag -str: leading or trailing whitespace not allowed")},
+				params: &struct {
+					Str string `flag:"str|Testing string||required,rejectspace"`
+				}{},
+			},
+		},
+		{
+			name:      "fail - whitespace kept",
+			cliParams: []string{"-num", "15 "},
+			arg: &struct {
+				Num int `flag:"num|Testing number|"`
+			}{},
+			want: want{
+				err: &usageError{err: errors.New("invalid value \"15 \" for flag -num: parse error")},
+				params: &struct {
+					Num int `flag:"num|Testing number|"`
+				}{},
+			},
+		},
+		{
+			name:      "success - choices",
+			cliParams: []string{"-fmt=yaml", "-label=a,b"},
+			arg: &struct {
+				Format string `flag:"fmt|Testing choices|json|choices=json yaml"`
+				Labels Set    `flag:"label|Testing set choices||choices=a b c"`
+			}{},
+			want: want{
+				params: &struct {
+					Format string `flag:"fmt|Testing choices|json|choices=json yaml"`
+					Labels Set    `flag:"label|Testing set choices||choices=a b c"`
+				}{
+					Format: "yaml",
+					Labels: newTestSet("a", "b"),
+				},
+			},
+		},
+		{
+			name:      "fail - value not in choices",
+			cliParams: []string{"-fmt=xml"},
+			arg: &struct {
+				Format string `flag:"fmt|Testing choices|json|choices=json yaml"`
+			}{},
+			want: want{
+				err: &usageError{err: errors.New("invalid value \"xml\" for flag -fmt: value \"xml\" not allowed, the allowed values are json, yaml")},
+				params: &struct {
+					Format string `flag:"fmt|Testing choices|json|choices=json yaml"`
+				}{},
 			},
 		},
 		{
-			name:      "fail - trying to overwrite the long help flag",
-			cliParams: []string{""},
-			arg: &struct {
-				Boo bool `flag:"help"`
+			name:      "fail - default not in choices",
+			cliParams: []string{},
+			arg: &struct {
+				Format string `flag:"fmt|Testing choices|xml|choices=json yaml"`
+			}{},
+			want: want{
+				err: &FieldError{Field: "Format", Err: &BadDefaultError{
+					Flag:  "fmt",
+					Value: "xml",
+					Err:   errors.New("value \"xml\" not allowed, the allowed values are json, yaml"),
+				}},
+				params: &struct {
+					Format string `flag:"fmt|Testing choices|xml|choices=json yaml"`
+				}{},
+			},
+		},
+		{
+			name:      "success - scientific notation and digit separators",
+			cliParams: []string{"-num=1e6", "-num64", "1_000_000", "-unum=2.5e3"},
+			arg: &struct {
+				Num   int    `flag:"num|Testing number|"`
+				Num64 int64  `flag:"num64|Testing number|"`
+				UNum  uint64 `flag:"unum|Testing number|"`
+			}{},
+			want: want{
+				params: &struct {
+					Num   int    `flag:"num|Testing number|"`
+					Num64 int64  `flag:"num64|Testing number|"`
+					UNum  uint64 `flag:"unum|Testing number|"`
+				}{
+					Num:   1_000_000,
+					Num64: 1_000_000,
+					UNum:  2500,
+				},
+			},
+		},
+		{
+			name: "success - all integer and float widths",
+			cliParams: []string{"-i8=-128", "-i16=32767", "-i32=-2147483648", "-u8=255", "-u16=65535", "-u32=4294967295",
+				"-u=18446744073709551615", "-f32=1.5"},
+			arg: &struct {
+				I8  int8    `flag:"i8|Testing number|"`
+				I16 int16   `flag:"i16|Testing number|"`
+				I32 int32   `flag:"i32|Testing number|"`
+				U8  uint8   `flag:"u8|Testing number|"`
+				U16 uint16  `flag:"u16|Testing number|"`
+				U32 uint32  `flag:"u32|Testing number|"`
+				U   uint    `flag:"u|Testing number|"`
+				F32 float32 `flag:"f32|Testing number|"`
+			}{},
+			want: want{
+				params: &struct {
+					I8  int8    `flag:"i8|Testing number|"`
+					I16 int16   `flag:"i16|Testing number|"`
+					I32 int32   `flag:"i32|Testing number|"`
+					U8  uint8   `flag:"u8|Testing number|"`
+					U16 uint16  `flag:"u16|Testing number|"`
+					U32 uint32  `flag:"u32|Testing number|"`
+					U   uint    `flag:"u|Testing number|"`
+					F32 float32 `flag:"f32|Testing number|"`
+				}{
+					I8: -128, I16: 32767, I32: -2147483648, U8: 255, U16: 65535, U32: 4294967295,
+					U: 18446744073709551615, F32: 1.5,
+				},
+			},
+		},
+		{
+			name:      "fail - integer out of range",
+			cliParams: []string{"-u8=256"},
+			arg: &struct {
+				U8 uint8 `flag:"u8|Testing number|"`
+			}{},
+			want: want{
+				err: &usageError{err: errors.New("invalid value \"256\" for flag -u8: value out of range")},
+				params: &struct {
+					U8 uint8 `flag:"u8|Testing number|"`
+				}{},
+			},
+		},
+		{
+			name:      "fail - float out of range",
+			cliParams: []string{"-f32=1e39"},
+			arg: &struct {
+				F32 float32 `flag:"f32|Testing number|"`
+			}{},
+			want: want{
+				err: &usageError{err: errors.New("invalid value \"1e39\" for flag -f32: value out of range")},
+				params: &struct {
+					F32 float32 `flag:"f32|Testing number|"`
+				}{},
+			},
+		},
+		{
+			name:      "fail - scientific notation not an integer",
+			cliParams: []string{"-num=1.5e-1"},
+			arg: &struct {
+				Num int `flag:"num|Testing number|"`
+			}{},
+			want: want{
+				err: &usageError{err: errors.New("invalid value \"1.5e-1\" for flag -num: value is not an integer")},
+				params: &struct {
+					Num int `flag:"num|Testing number|"`
+				}{},
+			},
+		},
+		{
+			name:      "success - reserved flag handler",
+			cliParams: []string{"-str=asdf", "-trace=all"},
+			arg:       &VersionedParams{},
+			opts: []Option{WithReservedFlag(ReservedFlag{
+				Names:   []string{"trace"},
+				Handler: func(value string) error { return nil },
+			})},
+			want: want{
+				params: &VersionedParams{Str: "asdf"},
+			},
+		},
+		{
+			name:      "reserved flag handler stops parsing",
+			cliParams: []string{"-print-config"},
+			arg:       &VersionedParams{},
+			opts: []Option{WithReservedFlag(ReservedFlag{
+				Names:   []string{"print-config"},
+				IsBool:  true,
+				Handler: func(value string) error { return errReservedTest },
+			})},
+			want: want{
+				err:    errReservedTest,
+				params: &VersionedParams{},
+			},
+		},
+		{
+			name:      "fail - trying to overwrite a reserved flag",
+			cliParams: []string{},
+			arg:       &VersionedParams{},
+			opts:      []Option{WithReservedFlag(ReservedFlag{Names: []string{"str"}})},
+			want: want{
+				err:    &FieldError{Field: "Str", Err: errors.New("reserved flag -str overwriting not allowed")},
+				params: &VersionedParams{},
+			},
+		},
+		{
+			name:      "fail - reserved flag redefining a built-in one",
+			cliParams: []string{},
+			arg:       &VersionedParams{},
+			opts:      []Option{WithReservedFlag(ReservedFlag{Names: []string{"V"}})},
+			want: want{
+				err:    errors.New("reserved flag -V overwriting not allowed"),
+				params: &VersionedParams{},
+			},
+		},
+		{
+			name:      "success - args preprocessors",
+			cliParams: []string{"--wrapper-injected", "-s", "asdf"},
+			arg:       &VersionedParams{},
+			opts: []Option{WithArgsPreprocessors(
+				func(args []string) ([]string, error) {
+					return args[1:], nil
+				},
+				func(args []string) ([]string, error) {
+					for i, a := range args {
+						if a == "-s" {
+							args[i] = "-str"
+						}
+					}
+					return args, nil
+				},
+			)},
+			want: want{
+				params: &VersionedParams{Str: "asdf"},
+			},
+		},
+		{
+			name:      "fail in args preprocessor",
+			cliParams: []string{"-str", "asdf"},
+			arg:       &VersionedParams{},
+			opts: []Option{WithArgsPreprocessors(func(args []string) ([]string, error) {
+				return nil, errPreprocessorTest
+			})},
+			want: want{
+				err:    fmt.Errorf("args preprocessing failed: %w", errPreprocessorTest),
+				params: &VersionedParams{},
+			},
+		},
+		{
+			name:      "success - post-processors",
+			cliParams: []string{"-port=8080"},
+			arg:       &ValidatedParams{},
+			opts: []Option{WithPostProcessors(
+				func(params interface{}) error {
+					params.(*ValidatedParams).Port += 1
+					return nil
+				},
+				func(params interface{}) error {
+					params.(*ValidatedParams).Port *= 2
+					return nil
+				},
+			)},
+			want: want{
+				params: &ValidatedParams{
+					Port:    16162,
+					Address: ":16162",
+				},
+			},
+		},
+		{
+			name:      "fail in post-processor",
+			cliParams: []string{"-port=8080"},
+			arg:       &ValidatedParams{},
+			opts: []Option{WithPostProcessors(func(params interface{}) error {
+				return errPostProcessorTest
+			})},
+			want: want{
+				err:    fmt.Errorf("post-processing failed: %w", errPostProcessorTest),
+				params: &ValidatedParams{},
+			},
+		},
+		{
+			name:      "fail - nil",
+			cliParams: nil,
+			arg:       nil,
+			want: want{
+				err:    &InvalidParamsError{Type: nil},
+				params: nil,
+			},
+		},
+		{
+			name:      "fail - fourth segment invalid",
+			cliParams: []string{""},
+			arg: &struct {
+				Boo bool `flag:"str|Testing string||whatever"`
+			}{},
+			want: want{
+				params: &struct {
+					Boo bool `flag:"str|Testing string||whatever"`
+				}{},
+				err: &FieldError{Field: "Boo", Err: errors.New("unsupported value \"whatever\" in the fourth metadata part")},
+			},
+		},
+		{
+			name:      "fail - invalid priority",
+			cliParams: []string{""},
+			arg: &struct {
+				Boo bool `flag:"boo|Testing bool||priority=high"`
+			}{},
+			want: want{
+				params: &struct {
+					Boo bool `flag:"boo|Testing bool||priority=high"`
+				}{},
+				err: &FieldError{Field: "Boo", Err: errors.New("invalid priority \"high\" in the fourth metadata part")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = []string{"executable_name"}
+			os.Args = append(os.Args, tt.cliParams...)
+			err := ParseAndLoad(tt.arg, tt.opts...)
+			assert.Equal(t, tt.want.err, err)
+			assert.Equal(t, tt.want.params, tt.arg)
+		})
+	}
+}
+
+type NestedParams struct {
+	Params       Params
+	AnotherThing string `flag:"AnotherThing|Testing string|"`
+}
+
+func (np *NestedParams) Extend() error {
+	np.AnotherThing = "extended"
+	return nil
+}
+
+func newTestSet(values ...string) Set {
+	var s Set
+	for _, v := range values {
+		s.add(v)
+	}
+	return s
+}
+
+var strPointer = func() *string {
+	a := "wrong params"
+	return &a
+}()
+
+var failingParamsErr = errors.New("mock error in extension")
+
+var errReservedTest = errors.New("mock reserved flag handler error")
+
+var errPreprocessorTest = errors.New("mock args preprocessor error")
+
+var errPostProcessorTest = errors.New("mock post-processor error")
+
+type FailingParams struct {
+	NotImportant string `flag:"ni|Testing string|"`
+}
+
+func (np *FailingParams) Extend() error {
+	return failingParamsErr
+}
+
+type ExtendedRequiredParams struct {
+	Host string `flag:"host|Server host"`
+	Addr string `flag:"addr|Server address||required"`
+}
+
+func (ep *ExtendedRequiredParams) Extend() error {
+	if ep.Addr == "" && ep.Host != "" {
+		ep.Addr = ep.Host + ":80"
+	}
+	return nil
+}
+
+type ValidatedParams struct {
+	Port    int `flag:"port|Testing port|80|"`
+	Address string
+}
+
+func (vp *ValidatedParams) Validate() error {
+	if vp.Address != "" {
+		return errors.New("validation called after extension")
+	}
+	if vp.Port <= 0 || vp.Port > 65535 {
+		return fmt.Errorf("port %d out of range", vp.Port)
+	}
+	return nil
+}
+
+func (vp *ValidatedParams) Extend() error {
+	vp.Address = fmt.Sprintf(":%d", vp.Port)
+	return nil
+}
+
+type VersionedParams struct {
+	Str string `flag:"str|Testing string||required"`
+}
+
+func (vp *VersionedParams) Version() string {
+	return "v1.2.3"
+}
+
+type Port int
+
+func (p Port) Validate() error {
+	if p <= 0 || p > 65535 {
+		return fmt.Errorf("port %d out of range", p)
+	}
+	return nil
+}
+
+func TestParseAndLoad_exitFunc(t *testing.T) {
+	os.Args = []string{"executable_name", "-h"}
+	exitCode := -1
+	var p Params
+	err := ParseAndLoad(&p, WithExitFunc(func(code int) { exitCode = code }))
+	assert.Equal(t, flag.ErrHelp, err)
+	assert.Equal(t, 0, exitCode)
+	assert.Equal(t, Params{}, p)
+}
+
+func TestParseAndLoad_withoutExit(t *testing.T) {
+	os.Args = []string{"executable_name", "-help"}
+	var p Params
+	err := ParseAndLoad(&p, WithoutExit(), WithOutput(io.Discard))
+	assert.Equal(t, flag.ErrHelp, err)
+	assert.Equal(t, Params{}, p)
+}
+
+func TestParseAndLoad_output(t *testing.T) {
+	os.Args = []string{"executable_name", "-label=a", "-label=a", "-random"}
+	var buf bytes.Buffer
+	var p struct {
+		Labels Set `flag:"label|Testing set"`
+	}
+	err := ParseAndLoad(&p, WithOutput(&buf))
+	assert.Equal(t, &UnknownFlagError{Flag: "random", Err: errors.New("flag provided but not defined: -random")}, err)
+	want := `warning: duplicate value "a" of the flag -label ignored
+flag provided but not defined: -random
+Usage:
+  -label value
+    	Testing set
+`
+	assert.Equal(t, want, buf.String())
+}
+
+func TestParseAndLoad_usedFlagsHook(t *testing.T) {
+	os.Args = []string{"executable_name", "-unum=5", "-str", "secret", "-boo=false"}
+	var used []string
+	var p Params
+	err := ParseAndLoad(&p, WithUsedFlagsHook(func(names []string) { used = names }))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"boo", "str", "unum"}, used)
+}
+
+func TestParseAndLoad_noArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  error
+	}{
+		{name: "empty", args: []string{}, err: ErrNoArgs},
+		{name: "nil", args: nil, err: ErrNoArgs},
+		{name: "program name only", args: []string{"executable_name"}, err: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = tt.args
+			var p struct {
+				Str string `flag:"str|Testing string|default"`
+			}
+			assert.Equal(t, tt.err, ParseAndLoad(&p))
+		})
+	}
+}
+
+func TestParseAndLoad_argsSource(t *testing.T) {
+	os.Args = nil
+	var p struct {
+		Str string `flag:"str|Testing string|default"`
+		Num int    `flag:"num|Testing int"`
+	}
+	err := ParseAndLoad(&p, WithArgsSource(StaticArgs{"program", "-num=5"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "default", p.Str)
+	assert.Equal(t, 5, p.Num)
+
+	assert.Equal(t, ErrNoArgs, ParseAndLoad(&p, WithArgsSource(StaticArgs{})))
+}
+
+func TestParseAndLoad_lintWarnings(t *testing.T) {
+	os.Args = []string{"executable_name", "-in=file.txt"}
+	var p struct {
+		In      string `flag:"in|Path to the input file|in.txt|required"`
+		Len     int    `flag:"n|Maximum length|0"`
+		Verbose bool   `flag:"v"`
+		Labels  Set    `flag:"label|Labels|a,b"`
+	}
+	var buf bytes.Buffer
+	err := ParseAndLoad(&p, WithLintWarnings(&buf))
+	assert.NoError(t, err)
+	assert.Equal(t, `lint warning: flag -in: default value "in.txt" of the required flag is ignored
+lint warning: flag -n: default value "0" equals the zero value and can be omitted
+lint warning: flag -v: usage text missing
+`, buf.String())
+}
+
+func TestParseAndLoad_env(t *testing.T) {
+	type envParams struct {
+		Host   string `flag:"host|Server host|localhost" env:"APP_HOST"`
+		Port   int    `flag:"port|Server port||required" env:"APP_PORT"`
+		Labels Set    `flag:"label|Labels|a" env:"APP_LABELS"`
+		Debug  bool   `flag:"debug|Debug mode"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		env     MapEnv
+		want    envParams
+		wantErr error
+	}{
+		{
+			name: "env fallback",
+			args: []string{"-debug"},
+			env:  MapEnv{"APP_HOST": "example.com", "APP_PORT": "8080", "APP_LABELS": "b,c", "DEBUG": "false"},
+			want: envParams{Host: "example.com", Port: 8080, Labels: newTestSet("b", "c"), Debug: true},
+		},
+		{
+			name: "command line takes precedence",
+			args: []string{"-host=cli.com", "-port=1"},
+			env:  MapEnv{"APP_HOST": "example.com", "APP_PORT": "8080"},
+			want: envParams{Host: "cli.com", Port: 1, Labels: newTestSet("a")},
+		},
+		{
+			name:    "required flag missing",
+			env:     MapEnv{"APP_HOST": "example.com"},
+			wantErr: errors.New("missing required flag \"port\" or its value"),
+		},
+		{
+			name: "invalid env value",
+			env:  MapEnv{"APP_PORT": "eighty"},
+			wantErr: errors.New("invalid value \"eighty\" of the environment variable APP_PORT for the flag -port: " +
+				"parse error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = append([]string{"executable_name"}, tt.args...)
+			var p envParams
+			err := ParseAndLoad(&p, WithEnvSource(tt.env))
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
+
+func TestParseAndLoad_reservedBeforeSources(t *testing.T) {
+	var p struct {
+		N int `flag:"n|Number" env:"N"`
+	}
+	opts := []Option{
+		WithVersion("v1.2.3"),
+		WithConfigFlag("config"),
+		WithEnvSource(MapEnv{"N": "abc"}),
+		WithArgsSource(StaticArgs{"program", "-version", "-config=nonexistent.json"}),
+		WithOutput(io.Discard),
+	}
+	assert.ErrorIs(t, ParseAndLoad(&p, opts...), ErrVersion)
+}
+
+func TestParseAndLoad_secretFile(t *testing.T) {
+	type secretParams struct {
+		Password string `flag:"password|Database password||secret" env:"DB_PASSWORD"`
+		Port     int    `flag:"port|Database port|5432|secret" env:"DB_PORT"`
+		User     string `flag:"user|Database user" env:"DB_USER"`
+	}
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	password := writeFile("password", "hunter2\r\n")
+	port := writeFile("port", "eighty\n")
+	user := writeFile("user", "admin\n")
+	tests := []struct {
+		name    string
+		env     MapEnv
+		want    secretParams
+		wantErr string
+	}{
+		{
+			name: "secret file",
+			env:  MapEnv{"DB_PASSWORD_FILE": password, "DB_USER_FILE": user},
+			want: secretParams{Password: "hunter2", Port: 5432},
+		},
+		{
+			name: "variable takes precedence",
+			env:  MapEnv{"DB_PASSWORD": "env", "DB_PASSWORD_FILE": password},
+			want: secretParams{Password: "env", Port: 5432},
+		},
+		{
+			name:    "invalid value masked",
+			env:     MapEnv{"DB_PORT_FILE": port},
+			wantErr: `invalid value "***" of the environment variable DB_PORT_FILE for the flag -port: parse error`,
+		},
+		{
+			name: "missing file",
+			env:  MapEnv{"DB_PASSWORD_FILE": filepath.Join(dir, "missing")},
+			wantErr: "reading the file set by the environment variable DB_PASSWORD_FILE for the flag -password: open " +
+				filepath.Join(dir, "missing") + ": no such file or directory",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = []string{"executable_name"}
+			var p secretParams
+			err := ParseAndLoad(&p, WithEnvSource(tt.env), WithOutput(io.Discard))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
+
+func TestParseAndLoad_messages(t *testing.T) {
+	msgs := WithMessages(Messages{
+		MissingRequiredFlag: "chýba povinný prepínač %q alebo jeho hodnota",
+		ValueNotAllowed:     "hodnota %q nie je povolená, povolené hodnoty sú %s",
+	})
+	tests := []struct {
+		name string
+		args []string
+		opts []Option
+		err  string
+	}{
+		{name: "missing required flag", args: nil, err: "chýba povinný prepínač \"in\" alebo jeho hodnota"},
+		{
+			name: "value not allowed",
+			args: []string{"-in=a", "-fmt=xml"},
+			err:  "invalid value \"xml\" for flag -fmt: hodnota \"xml\" nie je povolená, povolené hodnoty sú json, yaml",
+		},
+		{
+			name: "default message kept",
+			args: []string{"-in=a"},
+			opts: []Option{WithPostProcessors(func(interface{}) error {
+				return errPostProcessorTest
+			})},
+			err: "post-processing failed: " + errPostProcessorTest.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = append([]string{"executable_name"}, tt.args...)
+			var p struct {
+				In     string `flag:"in|Input||required"`
+				Format string `flag:"fmt|Format|json|choices=json yaml"`
+			}
+			opts := append([]Option{msgs, WithOutput(io.Discard)}, tt.opts...)
+			err := ParseAndLoad(&p, opts...)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestParseAndLoad_requiredEnv(t *testing.T) {
+	type envParams struct {
+		Env      string `flag:"env|Environment|dev" env:"ENV"`
+		Password string `flag:"password|Database password" required_env:"prod,staging"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		env     MapEnv
+		opts    []Option
+		wantErr string
+	}{
+		{name: "dev", args: nil},
+		{name: "prod by flag", args: []string{"-env=prod"}, wantErr: "missing required flag \"password\" or its value"},
+		{name: "staging by env", env: MapEnv{"ENV": "staging"}, wantErr: "missing required flag \"password\" or its value"},
+		{name: "prod with value", args: []string{"-env=prod", "-password=secret"}},
+		{
+			name:    "undefined environment flag",
+			opts:    []Option{WithEnvironmentFlag("stage")},
+			wantErr: "environment flag -stage not defined",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = append([]string{"executable_name"}, tt.args...)
+			var p envParams
+			opts := append([]Option{WithEnvironmentFlag("env"), WithEnvSource(tt.env)}, tt.opts...)
+			err := ParseAndLoad(&p, opts...)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSplitEscaped(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  string
+		want []string
+	}{
+		{name: "name only", arg: "in", want: []string{"in"}},
+		{name: "all parts", arg: "in|Input file|a.txt|required", want: []string{"in", "Input file", "a.txt", "required"}},
+		{name: "empty parts", arg: "in|||required", want: []string{"in", "", "", "required"}},
+		{name: "escaped separator", arg: `fmt|Format: json\|yaml|json`, want: []string{"fmt", "Format: json|yaml", "json"}},
+		{name: "other backslashes kept", arg: `path|Windows path C:\dir`, want: []string{"path", `Windows path C:\dir`}},
+		{name: "trailing backslash", arg: `x|usage\`, want: []string{"x", `usage\`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitEscaped(tt.arg, '|'))
+		})
+	}
+}
+
+func TestParseFlagMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    flagMetadata
+		wantErr error
+	}{
+		{
+			name: "positional",
+			arg:  "in|Input file|/tmp/x|choices=/tmp/x /tmp/y,trim",
+			want: flagMetadata{
+				name: "in", usage: "Input file", defaultVal: "/tmp/x", choices: []string{"/tmp/x", "/tmp/y"},
+				whitespace: policyPtr(TrimWhitespace),
+			},
+		},
+		{
+			name: "key=value",
+			arg:  "name=in, usage=Input file, default=/tmp/x, choices=/tmp/x /tmp/y, trim",
+			want: flagMetadata{
+				name: "in", usage: "Input file", defaultVal: "/tmp/x", choices: []string{"/tmp/x", "/tmp/y"},
+				whitespace: policyPtr(TrimWhitespace),
+			},
+		},
+		{
+			name: "key=value in any order",
+			arg:  "required,usage=Input file,name=in,default=/tmp/x",
+			want: flagMetadata{name: "in", usage: "Input file", isRequired: true, ignoredDefault: "/tmp/x"},
+		},
+		{
+			name: "key=value with escaped commas and pipes",
+			arg:  `name=label,usage=Labels\, e.g. a|b,default=a\,b`,
+			want: flagMetadata{name: "label", usage: "Labels, e.g. a|b", defaultVal: "a,b"},
+		},
+		{
+			name:    "key=value unsupported option",
+			arg:     "name=in,optional",
+			wantErr: errors.New("unsupported value \"optional\" in the flag metadata"),
+		},
+		{
+			name: "legacy mandatory option",
+			arg:  "in|Input file||mandatory",
+			want: flagMetadata{name: "in", usage: "Input file", isRequired: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFlagMetadata(tt.arg)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	bundle := Options(WithEnvPrefix("APP_"), WithProgramName("bundled"), WithVersion("v1"))
+	o := newOptions([]Option{bundle, WithVersion("v2")})
+	assert.Equal(t, "APP_", o.envPrefix)
+	assert.Equal(t, "bundled", o.programName())
+	assert.Equal(t, "v2", o.version)
+
+	o = newOptions([]Option{ServerDefaults()})
+	assert.Equal(t, RejectWhitespace, o.whitespace)
+	assert.Equal(t, ColorNever, o.color)
+}
+
+func TestParseAndLoad_envPrefix(t *testing.T) {
+	os.Args = []string{"executable_name"}
+	var p struct {
+		Port int `flag:"port|Server port" env:"PORT"`
+	}
+	err := ParseAndLoad(&p, WithEnvPrefix("APP_"), WithEnvSource(MapEnv{"PORT": "1", "APP_PORT": "2"}))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, p.Port)
+}
+
+func TestParseAndLoad_separateTags(t *testing.T) {
+	type separateParams struct {
+		In   string `flag:"in" usage:"Input file" required:"true"`
+		Len  int    `flag:"n" usage:"Maximum length" default:"-1"`
+		Out  string `flag:"out" usage:"Output file, e.g. a|b" default:"out.txt" required:"false"`
+		Verb bool   `flag:"v|Verbose output"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		params  interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{
+			name:   "separate tags",
+			args:   []string{"-in=a.txt"},
+			params: &separateParams{},
+			want:   &separateParams{In: "a.txt", Len: -1, Out: "out.txt"},
+		},
+		{
+			name:    "required by separate tag",
+			params:  &separateParams{},
+			want:    &separateParams{},
+			wantErr: "missing required flag \"in\" or its value",
+		},
+		{
+			name: "usage defined twice",
+			params: &struct {
+				In string `flag:"in|Input file" usage:"Input file"`
 			}{},
-			want: want{
-				params: &struct {
-					Boo bool `flag:"help"`
-				}{},
-				err: errors.New("reserved flag -help overwriting not allowed"),
-			},
+			want: &struct {
+				In string `flag:"in|Input file" usage:"Input file"`
+			}{},
+			wantErr: "invalid field In: usage of the flag -in defined by both the flag and usage tags",
 		},
 		{
-			name:      "success - nested params",
-			cliParams: []string{"-str=asdf", "-str2", "fdsa", "-boo", "-num=15", "-num64", "16", "-unum=17", "-unum64=18", "-dur=5m"},
-			arg:       &NestedParams{},
-			want: want{
-				params: &NestedParams{
-					Params: Params{
-						Str:       "asdf",
-						Str2:      "fdsa",
-						Boo:       true,
-						Number:    15,
-						ExtNumber: 9999999,
-						Number64:  16,
-						UNumber:   17,
-						UNumber64: 18,
-						Float64:   123.456,
-						Dur:       5 * time.Minute,
-					},
-					AnotherThing: "extended",
-				},
-			},
+			name: "default defined twice",
+			params: &struct {
+				In string `flag:"in||a.txt|required" default:"b.txt"`
+			}{},
+			want: &struct {
+				In string `flag:"in||a.txt|required" default:"b.txt"`
+			}{},
+			wantErr: "invalid field In: default value of the flag -in defined by both the flag and default tags",
 		},
 		{
-			name:      "fail - params not a pointer",
-			cliParams: []string{"-str=asdf", "-str2", "fdsa", "-boo", "-num=15", "-num64", "16", "-unum=17", "-unum64=18", "-dur=5m"},
-			arg:       Params{},
-			want: want{
-				err: &InvalidParamsError{
-					Type: reflect.TypeOf(Params{}),
-				},
-				params: Params{},
-			},
+			name: "invalid required tag",
+			params: &struct {
+				In string `flag:"in" required:"yes please"`
+			}{},
+			want: &struct {
+				In string `flag:"in" required:"yes please"`
+			}{},
+			wantErr: "invalid field In: invalid required tag \"yes please\" of the flag -in",
 		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = append([]string{"executable_name"}, tt.args...)
+			err := ParseAndLoad(tt.params)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, tt.params)
+		})
+	}
+}
+
+func TestParseAndLoad_tagSyntaxError(t *testing.T) {
+	type server struct {
+		Port int `flag:"|Server port"`
+	}
+	tests := []struct {
+		name   string
+		params interface{}
+		want   error
+	}{
 		{
-			name:      "fail - nil params",
-			cliParams: []string{"-str=asdf", "-str2", "fdsa", "-boo", "-num=15", "-num64", "16", "-unum=17", "-unum64=18", "-dur=5m"},
-			arg:       nil,
-			want: want{
-				err: &InvalidParamsError{
-					Type: reflect.TypeOf(nil),
-				},
-				params: nil,
-			},
+			name: "empty name",
+			params: &struct {
+				In string `flag:"|Input file"`
+			}{},
+			want: &TagSyntaxError{Field: "In", Tag: "|Input file", Reason: "empty flag name"},
 		},
 		{
-			name:      "fail - params pointer, but not a struct",
-			cliParams: []string{"-str=asdf", "-str2", "fdsa", "-boo", "-num=15", "-num64", "16", "-unum=17", "-unum64=18", "-dur=5m"},
-			arg:       strPointer,
-			want: want{
-				err: &InvalidParamsError{
-					Type: reflect.TypeOf(strPointer),
-				},
-				params: strPointer,
-			},
+			name: "empty name in nested structure",
+			params: &struct {
+				Server server
+			}{},
+			want: &TagSyntaxError{Field: "Server.Port", Tag: "|Server port", Reason: "empty flag name"},
 		},
 		{
-			name:      "fail in extension",
-			cliParams: []string{},
-			arg:       &FailingParams{},
-			want: want{
-				err:    fmt.Errorf("extension running failed: %w", failingParamsErr),
-				params: &FailingParams{},
-			},
+			name: "leading hyphen",
+			params: &struct {
+				In string `flag:"-in|Input file"`
+			}{},
+			want: &TagSyntaxError{Field: "In", Tag: "-in|Input file", Reason: "flag name \"-in\" starts with a hyphen"},
 		},
 		{
-			name:      "fail - nil",
-			cliParams: nil,
-			arg:       nil,
-			want: want{
-				err:    &InvalidParamsError{Type: nil},
-				params: nil,
-			},
+			name: "space",
+			params: &struct {
+				In string `flag:"input file|Input file"`
+			}{},
+			want: &TagSyntaxError{Field: "In", Tag: "input file|Input file", Reason: "flag name \"input file\" contains a space or an equals sign"},
 		},
 		{
-			name:      "fail - fourth segment invalid",
-			cliParams: []string{""},
-			arg: &struct {
-				Boo bool `flag:"str|Testing string||whatever"`
+			name: "key=value without name",
+			params: &struct {
+				In string `flag:"usage=Input file"`
 			}{},
-			want: want{
-				params: &struct {
-					Boo bool `flag:"str|Testing string||whatever"`
-				}{},
-				err: errors.New("unsupported value \"whatever\" in the fourth metadata part"),
-			},
+			want: &TagSyntaxError{Field: "In", Tag: "usage=Input file", Reason: "empty flag name"},
 		},
 	}
-
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			os.Args = []string{"executable_name"}
-			os.Args = append(os.Args, tt.cliParams...)
-			err := ParseAndLoad(tt.arg)
-			assert.Equal(t, tt.want.err, err)
-			assert.Equal(t, tt.want.params, tt.arg)
+			assert.Equal(t, tt.want, ParseAndLoad(tt.params))
 		})
 	}
 }
 
-type NestedParams struct {
-	Params       Params
-	AnotherThing string `flag:"AnotherThing|Testing string|"`
+func TestParseAndLoad_duplicateName(t *testing.T) {
+	type server struct {
+		Port int `flag:"port|Server port"`
+	}
+	type metrics struct {
+		Port int `flag:"port|Metrics port"`
+	}
+	tests := []struct {
+		name    string
+		params  interface{}
+		wantErr string
+	}{
+		{
+			name: "top-level fields",
+			params: &struct {
+				In    string `flag:"in|Input file"`
+				Input string `flag:"in|Input file"`
+			}{},
+			wantErr: "invalid field Input: flag -in already defined by the field In",
+		},
+		{
+			name: "nested structures",
+			params: &struct {
+				Server  server
+				Metrics metrics
+			}{},
+			wantErr: "invalid field Metrics.Port: flag -port already defined by the field Server.Port",
+		},
+		{
+			name: "flag value field",
+			params: &struct {
+				Port    int `flag:"port|Server port"`
+				Labels  Set `flag:"label|Labels"`
+				Servers Set `flag:"port|Servers"`
+			}{},
+			wantErr: "invalid field Servers: flag -port already defined by the field Port",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ParseAndLoad(tt.params, WithArgsSource(StaticArgs{"program"}), WithOutput(io.Discard))
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
 }
 
-func (np *NestedParams) Extend() error {
-	np.AnotherThing = "extended"
-	return nil
+func TestParseAndLoad_autoNames(t *testing.T) {
+	type autoParams struct {
+		MaxRetryCount int
+		HTTPPort      int    `flag:",auto|HTTP port|80"`
+		Input         string `flag:",auto,usage=Input file,required"`
+		Ignored       string `flag:"-"`
+		Logging       struct {
+			Verbose bool `usage:"Verbose output"`
+		}
+		private string
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		opts    []Option
+		want    autoParams
+		wantErr string
+	}{
+		{
+			name: "auto names",
+			args: []string{"-max-retry-count=3", "-input=a.txt", "-verbose"},
+			opts: []Option{WithAutoNames()},
+			want: func() autoParams {
+				p := autoParams{MaxRetryCount: 3, HTTPPort: 80, Input: "a.txt"}
+				p.Logging.Verbose = true
+				return p
+			}(),
+		},
+		{
+			name:    "untagged fields ignored by default",
+			args:    []string{"-max-retry-count=3", "-input=a.txt"},
+			wantErr: "flag provided but not defined: -max-retry-count",
+		},
+		{
+			name:    "explicitly ignored field",
+			args:    []string{"-input=a.txt", "-ignored=x"},
+			opts:    []Option{WithAutoNames()},
+			wantErr: "flag provided but not defined: -ignored",
+		},
+		{
+			name: "naming strategy",
+			args: []string{"-max_retry_count=3", "-input=a.txt", "-http_port=8080"},
+			opts: []Option{WithAutoNames(), WithNamingStrategy(SnakeCase)},
+			want: autoParams{MaxRetryCount: 3, HTTPPort: 8080, Input: "a.txt"},
+		},
+		{
+			name: "auto tags without option",
+			args: []string{"-input=a.txt", "-http-port=8080"},
+			want: autoParams{HTTPPort: 8080, Input: "a.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = append([]string{"executable_name"}, tt.args...)
+			var p autoParams
+			err := ParseAndLoad(&p, append(tt.opts, WithOutput(io.Discard))...)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
 }
 
-var strPointer = func() *string {
-	a := "wrong params"
-	return &a
-}()
-
-var failingParamsErr = errors.New("mock error in extension")
+func TestParseAndLoad_autoNameSuffix(t *testing.T) {
+	os.Args = []string{"executable_name", "-max-retry-count=3"}
+	var p struct {
+		MaxRetryCount int `flag:",automatic"`
+	}
+	err := ParseAndLoad(&p, WithAutoNames(), WithOutput(io.Discard))
+	assert.EqualError(t, err, `invalid flag tag ",automatic" of the field MaxRetryCount: the ,auto name must be followed by a comma, a pipe or the end of the tag`)
+	var tse *TagSyntaxError
+	assert.True(t, errors.As(err, &tse))
+}
 
-type FailingParams struct {
-	NotImportant string `flag:"ni|Testing string|"`
+func TestParseAndLoad_prepopulatedDefaults(t *testing.T) {
+	type prepopulatedParams struct {
+		Host   string        `flag:"host|Server host|localhost"`
+		Port   int           `flag:"port|Server port|80"`
+		Labels Set           `flag:"label|Labels|a"`
+		Token  string        `flag:"token|Token||required"`
+		Wait   time.Duration `flag:"wait|Wait time"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		opts    []Option
+		want    prepopulatedParams
+		wantErr string
+	}{
+		{
+			name: "pre-populated values kept",
+			args: []string{"-token=t"},
+			opts: []Option{WithPrepopulatedDefaults()},
+			want: prepopulatedParams{Host: "config.host", Port: 80, Labels: newTestSet("x"), Token: "t", Wait: time.Second},
+		},
+		{
+			name: "command line overrides pre-populated values",
+			args: []string{"-token=t", "-host=cli.host", "-label=y", "-wait=2s"},
+			opts: []Option{WithPrepopulatedDefaults()},
+			want: prepopulatedParams{Host: "cli.host", Port: 80, Labels: newTestSet("y"), Token: "t", Wait: 2 * time.Second},
+		},
+		{
+			name:    "pre-populated values restored on error",
+			args:    []string{"-port=x"},
+			opts:    []Option{WithPrepopulatedDefaults()},
+			want:    prepopulatedParams{Host: "config.host", Labels: newTestSet("x"), Wait: time.Second},
+			wantErr: `invalid value "x" for flag -port: parse error`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = append([]string{"executable_name"}, tt.args...)
+			p := prepopulatedParams{Host: "config.host", Labels: newTestSet("x"), Wait: time.Second}
+			err := ParseAndLoad(&p, append(tt.opts, WithOutput(io.Discard))...)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, p)
+		})
+	}
 }
 
-func (np *FailingParams) Extend() error {
-	return failingParamsErr
+func TestTagSyntaxError_Error(t *testing.T) {
+	err := &TagSyntaxError{Field: "Server.Port", Tag: "|Server port", Reason: "empty flag name"}
+	assert.Equal(t, `invalid flag tag "|Server port" of the field Server.Port: empty flag name`, err.Error())
 }
 
 func TestInvalidParamsError_Error(t *testing.T) {
@@ -365,6 +1661,101 @@ func BenchmarkOrdinaryFlags(b *testing.B) {
 	}
 }
 
+// newBenchParams returns a pointer to a new structure with n flags of various types.
+func newBenchParams(n int) interface{} {
+	types := []reflect.Type{reflect.TypeOf(""), reflect.TypeOf(0), reflect.TypeOf(false), reflect.TypeOf(0.0)}
+	fields := make([]reflect.StructField, n)
+	for i := range fields {
+		tag := fmt.Sprintf(`flag:"flag%d|Usage of the flag %d"`, i, i)
+		if i%10 == 0 {
+			tag = fmt.Sprintf(`flag:"flag%d|Usage of the flag %d||required"`, i, i)
+		}
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("Field%d", i),
+			Type: types[i%len(types)],
+			Tag:  reflect.StructTag(tag),
+		}
+	}
+	return reflect.New(reflect.StructOf(fields)).Interface()
+}
+
+// newNestedBenchParams returns a pointer to a new structure with the flags nested in the given depth.
+func newNestedBenchParams(depth int) interface{} {
+	t := reflect.TypeOf(struct {
+		Leaf string `flag:"leaf|Leaf flag"`
+	}{})
+	for i := depth; i > 0; i-- {
+		t = reflect.StructOf([]reflect.StructField{
+			{Name: fmt.Sprintf("Flag%d", i), Type: reflect.TypeOf(0), Tag: reflect.StructTag(fmt.Sprintf(`flag:"flag%d|Level %d flag"`, i, i))},
+			{Name: fmt.Sprintf("Level%d", i), Type: t},
+		})
+	}
+	return reflect.New(t).Interface()
+}
+
+// benchArgs returns the arguments setting all the required flags of the structure returned by newBenchParams.
+func benchArgs(n int) []string {
+	args := []string{"bench"}
+	for i := 0; i < n; i += 10 {
+		switch i % 4 {
+		case 0:
+			args = append(args, fmt.Sprintf("-flag%d=value", i))
+		case 1:
+			args = append(args, fmt.Sprintf("-flag%d=1", i))
+		case 2:
+			args = append(args, fmt.Sprintf("-flag%d", i))
+		case 3:
+			args = append(args, fmt.Sprintf("-flag%d=1.5", i))
+		}
+	}
+	return args
+}
+
+func BenchmarkParseAndLoad(b *testing.B) {
+	for _, n := range []int{10, 100, 500} {
+		b.Run(fmt.Sprintf("flags=%d", n), func(b *testing.B) {
+			params := newBenchParams(n)
+			args := WithArgsSource(StaticArgs(benchArgs(n)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := ParseAndLoad(params, args); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+	for _, depth := range []int{5, 20} {
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			params := newNestedBenchParams(depth)
+			args := WithArgsSource(StaticArgs{"bench", "-leaf=value"})
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := ParseAndLoad(params, args); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkPrintUsage(b *testing.B) {
+	params := newBenchParams(100)
+	fb, err := newFlagBuilder(newOptions([]Option{WithOutput(io.Discard)}))
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := fb.setUpFlags(params); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fb.printUsage()
+	}
+}
+
 // Example_basic demonstrates the basic usage of the package.
 func Example_basic() {
 	var p struct {
@@ -398,3 +1789,117 @@ func Example_nested() {
 		log.Fatalf("error while parsing the cli parameters: %s", err.Error())
 	}
 }
+
+func TestParseAndLoad_fieldError(t *testing.T) {
+	type limits struct {
+		Rates map[string]int `flag:"rates|Rate limits"`
+	}
+	tests := []struct {
+		name      string
+		params    interface{}
+		wantField string
+		wantErr   string
+	}{
+		{
+			name: "unsupported type",
+			params: &struct {
+				Limits limits
+			}{},
+			wantField: "Limits.Rates",
+			wantErr:   "invalid field Limits.Rates: unsupported flag type: map[string]int",
+		},
+		{
+			name: "invalid default value",
+			params: &struct {
+				Workers int `flag:"workers|Number of workers|many"`
+			}{},
+			wantField: "Workers",
+			wantErr:   `invalid field Workers: invalid default value of the flag -workers: strconv.ParseInt: parsing "many": invalid syntax`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ParseAndLoad(tt.params, WithArgsSource(StaticArgs{"program"}), WithOutput(io.Discard))
+			assert.EqualError(t, err, tt.wantErr)
+			var fe *FieldError
+			if assert.ErrorAs(t, err, &fe) {
+				assert.Equal(t, tt.wantField, fe.Field)
+			}
+		})
+	}
+}
+
+func TestParseAndLoad_typedErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		params interface{}
+		args   []string
+		target error
+		check  func(t *testing.T, err error)
+	}{
+		{
+			name: "missing required flags",
+			params: &struct {
+				Port int    `flag:"port|Port||required"`
+				Host string `flag:"host|Host||required"`
+			}{},
+			target: ErrMissingRequired,
+			check: func(t *testing.T, err error) {
+				var mre *MissingRequiredError
+				if assert.ErrorAs(t, err, &mre) {
+					assert.Equal(t, []string{"host", "port"}, mre.Flags)
+				}
+				assert.EqualError(t, err, `missing required flags "host, port" or their values`)
+			},
+		},
+		{
+			name: "unknown flag",
+			params: &struct {
+				Port int `flag:"port|Port"`
+			}{},
+			args:   []string{"--prot=80"},
+			target: ErrUnknownFlag,
+			check: func(t *testing.T, err error) {
+				var ufe *UnknownFlagError
+				if assert.ErrorAs(t, err, &ufe) {
+					assert.Equal(t, "prot", ufe.Flag)
+				}
+			},
+		},
+		{
+			name: "bad default value",
+			params: &struct {
+				Timeout time.Duration `flag:"timeout|Timeout|soon"`
+			}{},
+			target: ErrBadDefault,
+			check: func(t *testing.T, err error) {
+				var bde *BadDefaultError
+				if assert.ErrorAs(t, err, &bde) {
+					assert.Equal(t, "timeout", bde.Flag)
+					assert.Equal(t, "soon", bde.Value)
+				}
+			},
+		},
+		{
+			name: "unsupported type",
+			params: &struct {
+				Ch chan int `flag:"ch|Channel"`
+			}{},
+			target: ErrUnsupportedType,
+			check: func(t *testing.T, err error) {
+				var ute *UnsupportedTypeError
+				if assert.ErrorAs(t, err, &ute) {
+					assert.Equal(t, reflect.TypeOf(make(chan int)), ute.Type)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ParseAndLoad(tt.params, WithArgsSource(StaticArgs(append([]string{"program"}, tt.args...))),
+				WithOutput(io.Discard))
+			assert.ErrorIs(t, err, tt.target)
+			tt.check(t, err)
+		})
+	}
+}
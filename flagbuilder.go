@@ -1,39 +1,174 @@
 package easyflag
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"os"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	byteSizeType = reflect.TypeOf(ByteSize(0))
+)
+
 type flagBuilder struct {
 	flagSet  *flag.FlagSet
 	required map[string]interface{} // map[flag name]pointers to the required fields to be able to check if they have been filled after the initialization
-	extFns   []func() error
+	// map[flag name]pointers to the fields required only in some environments, see the WithEnvironmentFlag option
+	requiredIn map[string]interface{}
+	valFns     []func() error
+	extFns     []func() error
+	opts       options
+	flags      []flagInfo // the attached flags in the order of their definition
+	reserved   []reservedFlag
+	group      string            // the group of the nested structure whose flags are being set up
+	fieldTag   reflect.StructTag // the tag of the field whose flag is being set up
+	// the index sequence of the field whose flag is being set up within the top-level params structure
+	fieldIndex  []int
+	fieldPath   string // the path of the field whose flag is being set up, e.g. Server.Port
+	fieldName   string
+	sources     map[string]Source // the sources of the values of the flags set on the command line or by the environment
+	namespace   string            // the namespace of the params structure being set up, see the WithNamespace option
+	external    bool              // the structure being set up was passed by the WithNamespace or WithParams option
+	remaining   []string          // the non-flag arguments remaining after the flags parsed by the backend
+	used        []string          // the names of the flags used on the command line reported by the backend
+	detached    reflect.Value     // the new instance of the params type on which the flags of a detached builder are set up
+	printConfig bool              // the configuration was requested by the -print-config flag
+	configPath  string            // the path of the loaded configuration file, see the WithConfigFlag option
+	// usagePrinted is set when the usage message was printed by the flag set, e.g. after a flag parsing error
+	usagePrinted bool
+	// usageDeferred makes the Usage function of the flag set only record the request in usageRequested,
+	// see the parseArgsCapturingErrors method
+	usageDeferred  bool
+	usageRequested bool
+	files          []*fileValue // the values of the File flags opened once all the flag values are loaded
+	unknown        []string     // the unknown flags with their values, see the WithUnknownFlags option
+	rest           []string     // the arguments left out by the bootstrap parsing, see the ParseKnown function
+	// dynamicDefaults caches the default values computed by the functions set by the WithDefaultFunc option
+	dynamicDefaults map[string]string
+}
+
+// flagInfo holds the metadata of an attached flag needed by the generators of the documentation and completions.
+type flagInfo struct {
+	flagMetadata
+	isBool    bool
+	valueName string
+	group     string // the flagGroup tag or the path of the nested structure defining the flag, empty for the top-level flags
+	fieldType reflect.Type
+	env       string // the environment variable setting the flag if it is not used on the command line
+	example   string // the example value of the flag shown in the usage message and the generated documentation
+	// the environments (e.g. prod) in which the flag is required, see the WithEnvironmentFlag option
+	requiredEnvs []string
+	fieldIndex   []int  // the index sequence of the field within the top-level params structure
+	fieldPath    string // the path of the field within the top-level params structure, e.g. Server.Port
+	// external flags are defined by the structures passed by the WithNamespace and WithParams options
+	external  bool
+	completer string // the name of the CompletionProvider of the flag values
+	short     string // the single-character short name of the flag used by the backends supporting it, see the short tag
+	secretRef string // the reference of the secret setting the flag, see the SecretProvider type
+}
+
+func newFlagBuilder(opts options) (*flagBuilder, error) {
+	fb := newBoundFlagBuilder(flag.NewFlagSet("", flag.ContinueOnError), opts)
+	fb.flagSet.Usage = fb.printUsage
+	if opts.output != nil {
+		fb.flagSet.SetOutput(opts.output)
+	}
+	if err := fb.setUpReservedFlags(); err != nil {
+		return nil, err
+	}
+	return fb, nil
 }
 
-func newFlagBuilder() *flagBuilder {
+// newBoundFlagBuilder creates a flagBuilder registering the flags into the passed flag set.
+// Unlike newFlagBuilder, it does not define any reserved flags and it does not change the flag set settings.
+func newBoundFlagBuilder(fs *flag.FlagSet, opts options) *flagBuilder {
 	return &flagBuilder{
-		required: make(map[string]interface{}),
-		flagSet:  flag.NewFlagSet("", flag.ContinueOnError),
+		required:   make(map[string]interface{}),
+		requiredIn: make(map[string]interface{}),
+		sources:    make(map[string]Source),
+		flagSet:    fs,
+		opts:       opts,
 	}
 }
 
 func (fb *flagBuilder) setUpFlags(params interface{}) error {
 	cliV := reflect.ValueOf(params).Elem()
 	cliT := reflect.TypeOf(params).Elem()
+	parentIndex, parentPath := fb.fieldIndex, fb.fieldPath
+	if fb.flags == nil {
+		// pre-sizing for the top-level structure, most of its fields are usually flags
+		fb.flags = make([]flagInfo, 0, cliV.NumField())
+		fb.required = make(map[string]interface{}, cliV.NumField())
+	}
 
 	for i := 0; i < cliV.NumField(); i++ {
 		fld := cliV.Field(i)
 		fldT := cliT.Field(i)
 		flagMetadataStr := fldT.Tag.Get("flag")
+		fb.fieldTag = fldT.Tag
+		fb.fieldIndex = append(append([]int(nil), parentIndex...), i)
+		fb.fieldPath = joinGroup(parentPath, fldT.Name)
+		fb.fieldName = fldT.Name
+
+		// the fields tagged by `flag:"-"` are always ignored
+		if flagMetadataStr == skipValue {
+			continue
+		}
+		if flagMetadataStr == "" && fb.opts.autoNames && fldT.IsExported() {
+			flagMetadataStr = autoNameValue
+		}
+
+		// fields implementing the flag.Value interface are attached as they are
+		if val, ok := asFlagValue(fld); ok {
+			if flagMetadataStr == "" {
+				continue
+			}
+			if err := attachFlagValue(fb, fld, val, flagMetadataStr); err != nil {
+				return fb.fieldError(err)
+			}
+			continue
+		}
+
+		// the fields with the json option are decoded from the JSON values instead of being recursed into
+		if fld.CanSet() && fb.isJSONField(flagMetadataStr) {
+			if err := attachFlagValue(fb, fld, &jsonFlagValue{ptr: fld.Addr()}, flagMetadataStr); err != nil {
+				return fb.fieldError(err)
+			}
+			continue
+		}
+
+		// the field types with a built-in flag value (e.g. net.IP) are attached by it instead of being recursed into
+		if val, ok := builtinFlagValue(fld); ok {
+			if flagMetadataStr == "" {
+				continue
+			}
+			if err := attachFlagValue(fb, fld, val, flagMetadataStr); err != nil {
+				return fb.fieldError(err)
+			}
+			continue
+		}
 
 		// recursion for the underlying structures
 		if fld.Kind() == reflect.Struct {
-			if err := fb.setUpFlags(fld.Addr().Interface()); err != nil {
+			parentGroup := fb.group
+			fb.group = joinGroup(parentGroup, fldT.Name)
+			if title, ok := fldT.Tag.Lookup("flagGroup"); ok {
+				fb.group = title
+			}
+			err := fb.setUpFlags(fld.Addr().Interface())
+			fb.group = parentGroup
+			if err != nil {
 				return err
 			}
 			continue
@@ -45,58 +180,288 @@ func (fb *flagBuilder) setUpFlags(params interface{}) error {
 		}
 
 		var err error
-		switch tpe := fld.Interface().(type) {
-		case string:
-			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, func(s string) (string, error) { return s, nil }, fb.flagSet.StringVar)
+		switch fld.Kind() {
+		case reflect.String:
+			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, func(s string) (string, error) { return s, nil }, "string")
 
-		case bool:
-			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, strconv.ParseBool, fb.flagSet.BoolVar)
+		case reflect.Bool:
+			parseFn := strconv.ParseBool
+			if fb.opts.boolSynonyms {
+				parseFn = parseBoolSynonym
+			}
+			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, parseFn, "")
+
+		case reflect.Int:
+			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, func(s string) (int, error) {
+				result, err := parseInt(s, strconv.IntSize)
+				return int(result), err
+			}, "int")
+
+		case reflect.Int8:
+			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, func(s string) (int8, error) {
+				result, err := parseInt(s, 8)
+				return int8(result), err
+			}, "int")
 
-		case int:
-			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, strconv.Atoi, fb.flagSet.IntVar)
+		case reflect.Int16:
+			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, func(s string) (int16, error) {
+				result, err := parseInt(s, 16)
+				return int16(result), err
+			}, "int")
 
-		case int64:
+		case reflect.Int32:
+			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, func(s string) (int32, error) {
+				result, err := parseInt(s, 32)
+				return int32(result), err
+			}, "int")
+
+		case reflect.Int64:
+			if fld.Type() == durationType {
+				err = parseAndAttachFlagData(fb, fld, flagMetadataStr, parseDuration, "duration")
+				break
+			}
 			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, func(s string) (int64, error) {
-				return strconv.ParseInt(s, 10, 64)
-			}, fb.flagSet.Int64Var)
+				return parseInt(s, 64)
+			}, "int")
 
-		case uint:
+		case reflect.Uint:
 			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, func(s string) (uint, error) {
-				result, err := strconv.ParseUint(s, 10, 32)
+				result, err := parseUint(s, strconv.IntSize)
 				return uint(result), err
-			}, fb.flagSet.UintVar)
+			}, "uint")
 
-		case uint64:
+		case reflect.Uint8:
+			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, func(s string) (uint8, error) {
+				result, err := parseUint(s, 8)
+				return uint8(result), err
+			}, "uint")
+
+		case reflect.Uint16:
+			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, func(s string) (uint16, error) {
+				result, err := parseUint(s, 16)
+				return uint16(result), err
+			}, "uint")
+
+		case reflect.Uint32:
+			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, func(s string) (uint32, error) {
+				result, err := parseUint(s, 32)
+				return uint32(result), err
+			}, "uint")
+
+		case reflect.Uint64:
+			if fld.Type() == byteSizeType {
+				err = parseAndAttachFlagData(fb, fld, flagMetadataStr, ParseByteSize, "size")
+				break
+			}
 			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, func(s string) (uint64, error) {
-				return strconv.ParseUint(s, 10, 64)
-			}, fb.flagSet.Uint64Var)
+				return parseUint(s, 64)
+			}, "uint")
 
-		case float64:
+		case reflect.Float32:
+			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, func(s string) (float32, error) {
+				result, err := strconv.ParseFloat(s, 32)
+				return float32(result), err
+			}, "float")
+
+		case reflect.Float64:
 			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, func(s string) (float64, error) {
 				return strconv.ParseFloat(s, 64)
-			}, fb.flagSet.Float64Var)
-
-		case time.Duration:
-			err = parseAndAttachFlagData(fb, fld, flagMetadataStr, time.ParseDuration, fb.flagSet.DurationVar)
+			}, "float")
 
 		default:
-			return fmt.Errorf("unsupported flag type: %T", tpe)
+			err = &UnsupportedTypeError{Type: fld.Type()}
 		}
 		if err != nil {
-			return err
+			return fb.fieldError(err)
 		}
 	}
+	if v, ok := params.(Validator); ok {
+		fb.valFns = append(fb.valFns, v.Validate)
+	}
 	if e, ok := params.(Extender); ok {
 		fb.extFns = append(fb.extFns, e.Extend)
 	}
 	return nil
 }
 
+// fieldError wraps the error of setting up the flag of the current field by the FieldError, unless it is
+// a TagSyntaxError naming the field already.
+func (fb *flagBuilder) fieldError(err error) error {
+	var tse *TagSyntaxError
+	if errors.As(err, &tse) {
+		return err
+	}
+	return &FieldError{Field: fb.fieldPath, Err: err}
+}
+
+// preprocessArgs expands the response files, translates the Windows-style flags and expands the groups of the short
+// flags, if enabled, and applies the argument pre-processors passed in the options.
+func (fb *flagBuilder) preprocessArgs(args []string) ([]string, error) {
+	if fb.opts.responseFiles {
+		var err error
+		if args, _, err = fb.expandResponseFiles(args, nil); err != nil {
+			return nil, err
+		}
+	}
+	if fb.opts.windowsSyntax {
+		args = fb.translateWindowsArgs(args)
+	}
+	if fb.opts.shortGrouping && fb.opts.backend == nil {
+		args = fb.expandShortFlagGroups(args)
+	}
+	for _, preprocess := range fb.opts.preprocessors {
+		var err error
+		if args, err = preprocess(args); err != nil {
+			return nil, fmt.Errorf(fb.opts.messages.ArgsPreprocessingFailed, err)
+		}
+	}
+	return args, nil
+}
+
 func (fb *flagBuilder) parseFlags(args []string) error {
-	return fb.flagSet.Parse(args)
+	if fb.opts.unknownFlags {
+		args, fb.unknown = fb.splitUnknownFlags(args)
+	}
+	defer fb.wrapSecretValues()()
+	checkRepeated := fb.wrapCountingValues()
+	err := fb.parseArgs(args)
+	// the counting values wrapping the secret ones are restored even if the parsing fails
+	repeatedErr := checkRepeated()
+	if err != nil {
+		return asUsageError(err)
+	}
+	if err := fb.secretValueError(); err != nil {
+		return asUsageError(err)
+	}
+	if repeatedErr != nil {
+		return asUsageError(repeatedErr)
+	}
+	fb.recordUsedFlags(fb.usedFlags())
+	return nil
+}
+
+// parseArgs parses the command line arguments by the backend, if set, or by the native flag package.
+func (fb *flagBuilder) parseArgs(args []string) error {
+	if fb.opts.capturesParseErrors() {
+		return fb.parseArgsCapturingErrors(func() error { return fb.runParser(args) })
+	}
+	return fb.runParser(args)
+}
+
+// runParser runs the backend, if set, or the native flag package parsing the command line arguments.
+func (fb *flagBuilder) runParser(args []string) error {
+	if b := fb.opts.backend; b != nil {
+		var err error
+		if fb.used, fb.remaining, err = b.Parse(fb.flagSet, args, fb.shortNames()); err != nil {
+			return err
+		}
+		sort.Strings(fb.used)
+		return nil
+	}
+	return unknownFlagError(fb.flagSet.Parse(args))
+}
+
+// unknownFlagError converts the error of the native flag package reporting an undefined flag to the UnknownFlagError.
+func unknownFlagError(err error) error {
+	const prefix = "flag provided but not defined: -"
+	if err == nil || !strings.HasPrefix(err.Error(), prefix) {
+		return err
+	}
+	return &UnknownFlagError{Flag: strings.TrimPrefix(err.Error(), prefix), Err: err}
+}
+
+// recordUsedFlags records the flags used on the command line as the sources of their values
+// and reports them to the hook set by the WithUsedFlagsHook option.
+func (fb *flagBuilder) recordUsedFlags(used []string) {
+	for _, name := range used {
+		fb.sources[name] = SourceCommandLine
+	}
+	if fb.opts.usedFlagsHook != nil {
+		fb.opts.usedFlagsHook(used)
+	}
 }
 
-func (fb *flagBuilder) validate() error {
+// loadEnv sets the flags not used on the command line from their environment variables if they are present.
+func (fb *flagBuilder) loadEnv() error {
+	for _, f := range fb.flags {
+		if _, used := fb.sources[f.name]; f.env == "" || used {
+			continue
+		}
+		val, ok := fb.opts.lookupEnv(f.env)
+		if !ok {
+			if f.isSecret {
+				if err := fb.loadSecretFile(f); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := fb.flagSet.Set(f.name, val); err != nil {
+			return fmt.Errorf(fb.opts.messages.InvalidEnvValue, f.displayed(val), f.env, f.name, err)
+		}
+		fb.sources[f.name] = SourceEnv
+	}
+	return nil
+}
+
+// loadSecretFile sets the secret flag from the file whose path is set by the environment variable named by the env
+// field tag with the _FILE suffix, following the convention of the secrets mounted by Docker and Kubernetes.
+// The trailing newlines of the file are trimmed.
+func (fb *flagBuilder) loadSecretFile(f flagInfo) error {
+	fileEnv := f.env + secretFileSuffix
+	path, ok := fb.opts.lookupEnv(fileEnv)
+	if !ok {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading the file set by the environment variable %s for the flag -%s: %w", fileEnv, f.name, err)
+	}
+	if err := fb.flagSet.Set(f.name, strings.TrimRight(string(data), "\r\n")); err != nil {
+		// the value is never shown, because it is a secret
+		return fmt.Errorf(fb.opts.messages.InvalidEnvValue, maskedValue, fileEnv, f.name, err)
+	}
+	fb.sources[f.name] = SourceEnv
+	return nil
+}
+
+// usedFlags returns the names of the flags explicitly used on the command line in the lexical order.
+func (fb *flagBuilder) usedFlags() []string {
+	if fb.opts.backend != nil {
+		return fb.used
+	}
+	var names []string
+	fb.flagSet.Visit(func(f *flag.Flag) {
+		names = append(names, f.Name)
+	})
+	return names
+}
+
+// addRequiredIn registers the field of the flag being set up as required in the environments
+// listed in its required_env field tag.
+func (fb *flagBuilder) addRequiredIn(name string, ptr interface{}) {
+	if _, ok := fb.fieldTag.Lookup("required_env"); ok {
+		fb.requiredIn[name] = ptr
+	}
+}
+
+// environment returns the current environment, i.e. the value of the flag set by the WithEnvironmentFlag option.
+func (fb *flagBuilder) environment() (string, error) {
+	if fb.opts.envFlag == "" {
+		return "", nil
+	}
+	f := fb.flagSet.Lookup(fb.opts.envFlag)
+	if f == nil {
+		return "", fmt.Errorf("environment flag -%s not defined", fb.opts.envFlag)
+	}
+	return f.Value.String(), nil
+}
+
+func (fb *flagBuilder) checkRequired() error {
+	env, err := fb.environment()
+	if err != nil {
+		return err
+	}
 	var missing []string
 	for key, val := range fb.required {
 		fld := reflect.ValueOf(val).Elem()
@@ -104,21 +469,73 @@ func (fb *flagBuilder) validate() error {
 			missing = append(missing, key)
 		}
 	}
+	for _, f := range fb.flags {
+		val, ok := fb.requiredIn[f.name]
+		if !ok || f.isRequired || env == "" || !contains(f.requiredEnvs, env) {
+			continue
+		}
+		if reflect.ValueOf(val).Elem().IsZero() {
+			missing = append(missing, f.name)
+		}
+	}
 	switch len(missing) {
 	case 0:
 		return nil
 	case 1:
-		return fmt.Errorf("missing required flag %q or its value", strings.Join(missing, ", "))
+		return &MissingRequiredError{Flags: missing, format: fb.opts.messages.MissingRequiredFlag}
 	default:
-		return fmt.Errorf("missing required flags %q or their values", strings.Join(missing, ", "))
+		sort.Strings(missing)
+		return &MissingRequiredError{Flags: missing, format: fb.opts.messages.MissingRequiredFlags}
+	}
+}
+
+// check resolves the inherited defaults, runs the validations, post-processors and extensions of the loaded params
+// and checks the required flags. The required flags are checked after the Extend methods, so that they can fill them.
+// The files of the File flags are opened last, so that a failed check does not create or truncate any file.
+func (fb *flagBuilder) check(params interface{}) error {
+	if err := fb.loadInheritedDefaults(); err != nil {
+		return err
+	}
+	if err := fb.runValidationFunctions(); err != nil {
+		return err
+	}
+	if err := fb.runPostProcessors(params); err != nil {
+		return err
+	}
+	if err := fb.runExtensionFunctions(); err != nil {
+		return err
+	}
+	if err := fb.checkRequired(); err != nil {
+		return err
+	}
+	return fb.openFiles()
+}
+
+// runValidationFunctions runs all the relevant validation functions found during the flag collection process
+func (fb *flagBuilder) runValidationFunctions() error {
+	for _, valFn := range fb.valFns {
+		if err := valFn(); err != nil {
+			return fmt.Errorf(fb.opts.messages.ValidationFailed, err)
+		}
 	}
+	return nil
+}
+
+// runPostProcessors applies the post-processors passed in the options to the params
+func (fb *flagBuilder) runPostProcessors(params interface{}) error {
+	for _, postProcess := range fb.opts.postProcessors {
+		if err := postProcess(params); err != nil {
+			return fmt.Errorf(fb.opts.messages.PostProcessingFailed, err)
+		}
+	}
+	return nil
 }
 
 // runExtensionFunctions recursively runs all the relevant extension functions found during the flag collection process
 func (fb *flagBuilder) runExtensionFunctions() error {
 	for _, extFn := range fb.extFns {
 		if err := extFn(); err != nil {
-			return fmt.Errorf("extension running failed: %w", err)
+			return fmt.Errorf(fb.opts.messages.ExtensionFailed, err)
 		}
 	}
 	return nil
@@ -129,61 +546,567 @@ func parseAndAttachFlagData[T any](
 	fld reflect.Value,
 	flagMetadata string,
 	parseFn func(string) (T, error),
-	attachFn func(p *T, name string, value T, usage string),
+	valueName string,
 ) error {
-	fm, err := parseFlagMetadata(flagMetadata)
+	fm, err := fb.parseFieldMetadata(flagMetadata)
 	if err != nil {
 		return err
 	}
+	if fm.encoding != "" {
+		return fmt.Errorf("encoding %s not supported for the flag -%s", fm.encoding, fm.name)
+	}
+	if fm.fileMode != "" {
+		return fmt.Errorf("file mode not supported for the flag -%s", fm.name)
+	}
+	if fm.expand {
+		stringParseFn, ok := interface{}(parseFn).(func(string) (string, error))
+		if !ok {
+			return fmt.Errorf("expand not supported for the flag -%s", fm.name)
+		}
+		parseFn = interface{}(fb.expandParseFn(stringParseFn)).(func(string) (T, error))
+	}
+	addr, ok := fld.Addr().Interface().(*T)
+	if !ok {
+		// the conversion allows for the named types, e.g. type Port int
+		addr = fld.Addr().Convert(reflect.TypeOf((*T)(nil))).Interface().(*T)
+	}
 	var defaultVal T
-	if fm.defaultVal != "" {
+	switch {
+	case fb.isPrepopulated(fld):
+		defaultVal = *addr
+		fm.defaultVal = fmt.Sprint(defaultVal)
+	case fm.defaultVal != "":
+		if err := checkChoice(fm.choices, fm.defaultVal, fb.opts.messages.ValueNotAllowed); err != nil {
+			return &BadDefaultError{Flag: fm.name, Value: fm.defaultVal, Err: err}
+		}
 		var err error
 		defaultVal, err = parseFn(fm.defaultVal)
 		if err != nil {
-			return err
+			return &BadDefaultError{Flag: fm.name, Value: fm.defaultVal, Err: err}
 		}
 	}
-	if n := fmt.Sprintf("-%s", fm.name); n == helpArg || n == helpArgShort {
-		return fmt.Errorf("reserved flag %s overwriting not allowed", n)
+	if err := fb.checkReserved(fm.name); err != nil {
+		return err
+	}
+	if err := fb.checkDuplicate(fm.name); err != nil {
+		return err
+	}
+	if err := fb.checkDuplicateShort(fm.name, fb.fieldTag.Get(shortKey)); err != nil {
+		return err
 	}
-	addr := fld.Addr().Interface().(*T)
 
-	attachFn(addr, fm.name, defaultVal, fm.usage)
+	*addr = defaultVal
+	_, isBool := interface{}(defaultVal).(bool)
+	fb.flagSet.Var(&value[T]{
+		ptr:        addr,
+		parseFn:    parseFn,
+		typeName:   valueName,
+		isBool:     isBool,
+		whitespace: fm.whitespacePolicy(fb.opts.whitespace),
+		choices:    fm.choices,
+		msgs:       &fb.opts.messages,
+	}, fm.name, fm.usage)
+	fb.addFlagInfo(fm, fld.Type())
 	if fm.isRequired {
 		fb.required[fm.name] = addr
 	}
-	return nil
+	fb.addRequiredIn(fm.name, addr)
+	fb.addFieldValidator(fld, fm.name)
+	return fb.addOptionValidators(fm, addr)
+}
+
+// isPrepopulated reports whether the field value set by the caller is used as the default value of the flag,
+// see the WithPrepopulatedDefaults option.
+func (fb *flagBuilder) isPrepopulated(fld reflect.Value) bool {
+	return fb.opts.prepopulated && !fld.IsZero()
+}
+
+// builtinFlagValue returns the flag value of the field types supported by the package which are neither the basic types
+// nor they implement the flag.Value interface.
+func builtinFlagValue(fld reflect.Value) (flag.Value, bool) {
+	if !fld.CanAddr() || !fld.Addr().CanInterface() {
+		return nil, false
+	}
+	switch ptr := fld.Addr().Interface().(type) {
+	case *net.IP:
+		return &ipValue{ip: ptr}, true
+	case *net.IPNet:
+		return &ipNetValue{ipNet: ptr}, true
+	case *netip.Addr:
+		return &addrValue{addr: ptr}, true
+	case *netip.Prefix:
+		return &prefixValue{prefix: ptr}, true
+	case **regexp.Regexp:
+		return &regexpValue{re: ptr}, true
+	case *[]byte:
+		return &bytesValue{bytes: ptr}, true
+	case **big.Int:
+		return &bigIntValue{i: ptr}, true
+	case **big.Rat:
+		return &bigRatValue{r: ptr}, true
+	default:
+		return versionedFlagValue(ptr)
+	}
+}
+
+func asFlagValue(fld reflect.Value) (flag.Value, bool) {
+	if !fld.CanAddr() || !fld.Addr().CanInterface() {
+		return nil, false
+	}
+	val, ok := fld.Addr().Interface().(flag.Value)
+	return val, ok
+}
+
+func attachFlagValue(fb *flagBuilder, fld reflect.Value, val flag.Value, flagMetadata string) error {
+	fm, err := fb.parseFieldMetadata(flagMetadata)
+	if err != nil {
+		return err
+	}
+	if err := fb.checkReserved(fm.name); err != nil {
+		return err
+	}
+	if err := fb.checkDuplicate(fm.name); err != nil {
+		return err
+	}
+	if err := fb.checkDuplicateShort(fm.name, fb.fieldTag.Get(shortKey)); err != nil {
+		return err
+	}
+	if s, ok := val.(*Set); ok {
+		val = &setValue{
+			set: s,
+			onDuplicate: func(value string) {
+				if fm.isSecret {
+					value = maskedValue
+				}
+				fmt.Fprintf(fb.flagSet.Output(), fb.opts.messages.DuplicateValue+"\n", value, fm.name)
+			},
+			choices: fm.choices,
+			msgs:    &fb.opts.messages,
+		}
+	} else if len(fm.choices) > 0 {
+		return fmt.Errorf("choices not supported for the flag -%s", fm.name)
+	}
+	if hp, ok := val.(*HostPort); ok {
+		val = &hostPortValue{HostPort: hp, defaultPort: fb.fieldTag.Get(defaultPortKey)}
+	}
+	if cl, ok := val.(choicesLister); ok {
+		fm.choices = cl.choices()
+	}
+	if bv, ok := val.(*bytesValue); ok {
+		bv.encoding = fm.encoding
+	} else if fm.encoding != "" {
+		return fmt.Errorf("encoding %s not supported for the flag -%s", fm.encoding, fm.name)
+	}
+	if f, ok := val.(*File); ok {
+		fv := &fileValue{File: f, name: fm.name, mode: fm.fileMode}
+		fb.files = append(fb.files, fv)
+		val = fv
+	} else if fm.fileMode != "" {
+		return fmt.Errorf("file mode not supported for the flag -%s", fm.name)
+	}
+	if fm.expand {
+		return fmt.Errorf("expand not supported for the flag -%s", fm.name)
+	}
+	switch {
+	case fb.isPrepopulated(fld):
+		fm.defaultVal = val.String()
+		if sv, ok := val.(*setValue); ok {
+			sv.isDefault = true
+		}
+	case fm.defaultVal != "":
+		if err := val.Set(fm.defaultVal); err != nil {
+			return &BadDefaultError{Flag: fm.name, Value: fm.defaultVal, Err: err}
+		}
+		if sv, ok := val.(*setValue); ok {
+			sv.isDefault = true
+		}
+	}
+
+	fb.flagSet.Var(val, fm.name, fm.usage)
+	fb.addFlagInfo(fm, fld.Type())
+	if fm.isRequired {
+		fb.required[fm.name] = fld.Addr().Interface()
+	}
+	fb.addRequiredIn(fm.name, fld.Addr().Interface())
+	fb.addFieldValidator(fld, fm.name)
+	return fb.addOptionValidators(fm, val)
+}
+
+// addFlagInfo stores the metadata of the flag attached to the flag set.
+// The usage stored is stripped of the back-quoted value name the same way as in the usage message.
+func (fb *flagBuilder) addFlagInfo(fm flagMetadata, fieldType reflect.Type) {
+	f := fb.flagSet.Lookup(fm.name)
+	bf, isBool := f.Value.(interface{ IsBoolFlag() bool })
+	fi := flagInfo{
+		flagMetadata: fm,
+		isBool:       isBool && bf.IsBoolFlag(),
+		group:        fb.group,
+		fieldType:    fieldType,
+		env:          fb.envName(),
+		example:      fb.fieldTag.Get("example"),
+		requiredEnvs: splitSetValues(fb.fieldTag.Get("required_env")),
+		fieldIndex:   fb.fieldIndex,
+		fieldPath:    fb.fieldPath,
+		external:     fb.external,
+		completer:    fb.fieldTag.Get("complete"),
+		short:        fb.fieldTag.Get(shortKey),
+		secretRef:    fb.fieldTag.Get("secretRef"),
+	}
+	fi.valueName, fi.usage = unquoteUsage(f)
+	fb.flags = append(fb.flags, fi)
+	fb.lintFlag(fm, f)
+}
+
+// envName returns the environment variable named by the env tag of the field being set up
+// prefixed by the WithEnvPrefix option.
+func (fb *flagBuilder) envName() string {
+	env := fb.fieldTag.Get("env")
+	if env == "" {
+		return ""
+	}
+	return fb.opts.envPrefix + env
+}
+
+// displayName returns the name of the flag value shown in the usage message and the generated documentation.
+func (fi flagInfo) displayName() string {
+	if fi.isBool {
+		return ""
+	}
+	if fi.placeholder != "" {
+		return fi.placeholder
+	}
+	return fi.valueName
+}
+
+// addFieldValidator registers the Validate method of the field's type if the type implements the Validator interface.
+func (fb *flagBuilder) addFieldValidator(fld reflect.Value, name string) {
+	v, ok := fld.Addr().Interface().(Validator)
+	if !ok {
+		return
+	}
+	fb.valFns = append(fb.valFns, func() error {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("flag -%s: %w", name, err)
+		}
+		return nil
+	})
 }
 
 type flagMetadata struct {
-	name       string
-	usage      string
-	defaultVal string
-	isRequired bool
+	name         string
+	usage        string
+	defaultVal   string
+	isRequired   bool
+	whitespace   *WhitespacePolicy // overrides the global whitespace policy if set
+	choices      []string
+	priority     int    // the flags with a higher priority are listed first in the usage message
+	placeholder  string // the name of the flag value shown in the usage message instead of the value type
+	isSecret     bool   // the value of the flag is sensitive, e.g. a password
+	isReloadable bool   // the flag can be changed without restarting the program
+	isPrompted   bool   // the value is read interactively if the flag is not set, see the WithPrompt option
+	encoding     string // the encoding of the []byte values, hex or base64
+	isJSON       bool   // the value is decoded from JSON, e.g. into a structure, map or slice
+	fileMode     string // the mode in which the File values are opened, read, create or append
+	expand       bool   // the ~ and $VAR references of the string values are expanded, e.g. in the paths
+	pathCheck    string // the check of the path passed to the flag, mustExist, mustBeDir or mustBeFile
+	isURL        bool   // the value must be an absolute URL
+	urlSchemes   string // the space separated schemes allowed by the url option, e.g. url=https, any if empty
+	isNotBlank   bool   // the value set by any source must not be empty or whitespace-only
+	defaultFrom  string // the flag whose resolved value is used if the flag is not set by any source
+
+	ignoredDefault string // the default value ignored because the flag is required
+}
+
+func (fm flagMetadata) whitespacePolicy(global WhitespacePolicy) WhitespacePolicy {
+	if fm.whitespace != nil {
+		return *fm.whitespace
+	}
+	return global
 }
 
 func parseFlagMetadata(flagMetadataStr string) (flagMetadata, error) {
-	metadataParts := strings.Split(flagMetadataStr, "|")
-	name := strings.TrimSpace(metadataParts[0])
-	var (
-		usage, defaultVal string
-		isRequired        bool
-	)
+	if isKeyValueMetadata(flagMetadataStr) {
+		return parseKeyValueMetadata(flagMetadataStr)
+	}
+	metadataParts := splitEscaped(flagMetadataStr, '|')
+	fm := flagMetadata{name: strings.TrimSpace(metadataParts[0])}
 	if len(metadataParts) > 1 {
-		usage = strings.TrimSpace(metadataParts[1])
+		fm.usage = strings.TrimSpace(metadataParts[1])
 	}
 	if len(metadataParts) > 2 {
-		defaultVal = strings.TrimSpace(metadataParts[2])
+		fm.defaultVal = strings.TrimSpace(metadataParts[2])
+	}
+	if len(metadataParts) > 4 {
+		return flagMetadata{}, errors.New(`too many metadata parts, the | characters of the usage must be escaped as \|`)
 	}
 	if len(metadataParts) > 3 {
-		switch val := metadataParts[3]; val {
-		case requiredValue:
-			defaultVal = "" // if it is required, we ignore default value
-			isRequired = true
-		case "":
+		// the fourth part is a comma separated list of the flag options
+		for _, val := range strings.Split(metadataParts[3], ",") {
+			if err := fm.setOption(strings.TrimSpace(val)); err != nil {
+				return flagMetadata{}, fmt.Errorf("%v in the fourth metadata part", err)
+			}
+		}
+	}
+	fm.ignoreRequiredDefault()
+	return fm, nil
+}
+
+// parseFieldMetadata parses the flag metadata of the field being set up. Besides the flag tag, the usage,
+// default value and required marker can be defined by the separate usage, default and required field tags.
+func (fb *flagBuilder) parseFieldMetadata(flagMetadataStr string) (flagMetadata, error) {
+	rest, autoName, reason := cutAutoName(flagMetadataStr)
+	if reason != "" {
+		return flagMetadata{}, &TagSyntaxError{Field: fb.fieldPath, Tag: flagMetadataStr, Reason: reason}
+	}
+	if autoName {
+		name := fb.opts.namingStrategy()(fb.fieldName)
+		if strings.HasPrefix(rest, ",") {
+			flagMetadataStr = nameKey + "=" + name + rest // the key=value dialect
+		} else {
+			flagMetadataStr = name + rest
+		}
+	}
+	fm, err := parseFlagMetadata(flagMetadataStr)
+	if err != nil {
+		return flagMetadata{}, err
+	}
+	if reason := checkFlagName(fm.name); reason != "" {
+		return flagMetadata{}, &TagSyntaxError{Field: fb.fieldPath, Tag: flagMetadataStr, Reason: reason}
+	}
+	if fb.namespace != "" {
+		fm.name = fb.namespace + namespaceSeparator + fm.name
+	}
+	if usage, ok := fb.fieldTag.Lookup(usageKey); ok {
+		if fm.usage != "" {
+			return flagMetadata{}, fmt.Errorf("usage of the flag -%s defined by both the flag and usage tags", fm.name)
+		}
+		fm.usage = strings.TrimSpace(usage)
+	}
+	if defaultVal, ok := fb.fieldTag.Lookup(defaultKey); ok {
+		if fm.defaultVal != "" || fm.ignoredDefault != "" {
+			return flagMetadata{}, fmt.Errorf("default value of the flag -%s defined by both the flag and default tags", fm.name)
+		}
+		fm.defaultVal = strings.TrimSpace(defaultVal)
+	}
+	if defaultVal, ok := fb.dynamicDefault(fm.name); ok {
+		if fm.defaultVal != "" || fm.ignoredDefault != "" {
+			return flagMetadata{}, fmt.Errorf("default value of the flag -%s defined by both the tag and the default function", fm.name)
+		}
+		fm.defaultVal = defaultVal
+	}
+	if required, ok := fb.fieldTag.Lookup(requiredValue); ok {
+		isRequired, err := strconv.ParseBool(required)
+		if err != nil {
+			return flagMetadata{}, fmt.Errorf("invalid required tag %q of the flag -%s", required, fm.name)
+		}
+		fm.isRequired = fm.isRequired || isRequired
+	}
+	if short, ok := fb.fieldTag.Lookup(shortKey); ok && !isShortName(short) {
+		return flagMetadata{}, fmt.Errorf("invalid short name %q of the flag -%s", short, fm.name)
+	}
+	fm.ignoreRequiredDefault()
+	return fm, nil
+}
+
+// checkFlagName returns the reason why the flag name is invalid or an empty string if it is valid.
+func checkFlagName(name string) string {
+	switch {
+	case name == "":
+		return "empty flag name"
+	case strings.HasPrefix(name, "-"):
+		return fmt.Sprintf("flag name %q starts with a hyphen", name)
+	case strings.ContainsAny(name, " \t\n="):
+		return fmt.Sprintf("flag name %q contains a space or an equals sign", name)
+	}
+	return ""
+}
+
+// isKeyValueMetadata reports whether the flag metadata uses the key=value dialect,
+// e.g. "name=in,usage=Input file,required". The flag names cannot contain the '=' character,
+// so the positional dialect never contains it in its first part.
+func isKeyValueMetadata(flagMetadataStr string) bool {
+	name := flagMetadataStr
+	if i := strings.IndexByte(name, '|'); i >= 0 {
+		name = name[:i]
+	}
+	return strings.Contains(name, "=")
+}
+
+// parseKeyValueMetadata parses the flag metadata in the key=value dialect. The name, usage and default keys
+// correspond to the first three parts of the positional dialect and the other items are the flag options.
+func parseKeyValueMetadata(flagMetadataStr string) (flagMetadata, error) {
+	var fm flagMetadata
+	seen := make(map[string]bool)
+	for _, item := range splitEscaped(flagMetadataStr, ',') {
+		item = strings.TrimSpace(item)
+		key, val, _ := strings.Cut(item, "=")
+		switch key {
+		case nameKey, usageKey, defaultKey:
+			if seen[key] {
+				return flagMetadata{}, fmt.Errorf("duplicate key %q in the flag metadata", key)
+			}
+			seen[key] = true
+		}
+		switch key {
+		case nameKey:
+			fm.name = strings.TrimSpace(val)
+		case usageKey:
+			fm.usage = strings.TrimSpace(val)
+		case defaultKey:
+			fm.defaultVal = strings.TrimSpace(val)
 		default:
-			return flagMetadata{}, fmt.Errorf("unsupported value %q in the fourth metadata part", val)
+			if err := fm.setOption(item); err != nil {
+				return flagMetadata{}, fmt.Errorf("%v in the flag metadata", err)
+			}
+		}
+	}
+	fm.ignoreRequiredDefault()
+	return fm, nil
+}
+
+// setOption sets the flag option, e.g. required or choices=a b c.
+func (fm *flagMetadata) setOption(val string) error {
+	if v, ok := cutPrefix(val, choicesValuePrefix); ok {
+		fm.choices = strings.Fields(v)
+		return nil
+	}
+	if v, ok := cutPrefix(val, priorityValuePrefix); ok {
+		p, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid priority %q", v)
+		}
+		fm.priority = p
+		return nil
+	}
+	if v, ok := cutPrefix(val, placeholderPrefix); ok {
+		fm.placeholder = v
+		return nil
+	}
+	if v, ok := cutPrefix(val, defaultFromPrefix); ok {
+		fm.defaultFrom = v
+		return nil
+	}
+	if v, ok := cutPrefix(val, urlValuePrefix); ok {
+		fm.isURL, fm.urlSchemes = true, v
+		return nil
+	}
+	if v, ok := cutPrefix(val, modeValuePrefix); ok {
+		if !isFileMode(v) {
+			return fmt.Errorf("unsupported file mode %q", v)
+		}
+		fm.fileMode = v
+		return nil
+	}
+	switch val {
+	case requiredValue, mandatoryValue:
+		fm.isRequired = true
+	case secretValue:
+		fm.isSecret = true
+	case reloadableValue:
+		fm.isReloadable = true
+	case promptValue:
+		fm.isPrompted = true
+	case hexValue, base64Value:
+		fm.encoding = val
+	case jsonValue:
+		fm.isJSON = true
+	case expandValue:
+		fm.expand = true
+	case mustExistValue, mustBeDirValue, mustBeFileValue:
+		fm.pathCheck = val
+	case urlValue:
+		fm.isURL = true
+	case notBlankValue:
+		fm.isNotBlank = true
+	case keepWhitespaceValue:
+		fm.whitespace = policyPtr(KeepWhitespace)
+	case trimWhitespaceValue:
+		fm.whitespace = policyPtr(TrimWhitespace)
+	case rejectWhitespaceValue:
+		fm.whitespace = policyPtr(RejectWhitespace)
+	case "":
+	default:
+		return fmt.Errorf("unsupported value %q", val)
+	}
+	return nil
+}
+
+// ignoreRequiredDefault drops the default value of a required flag.
+func (fm *flagMetadata) ignoreRequiredDefault() {
+	if fm.isRequired && fm.defaultVal != "" {
+		fm.ignoredDefault, fm.defaultVal = fm.defaultVal, "" // if it is required, we ignore default value
+	}
+}
+
+// splitEscaped splits the string into the parts separated by the sep characters. The sep characters escaped
+// by a backslash are not separators, they are unescaped instead, e.g. \| in the positional flag metadata.
+func splitEscaped(s string, sep byte) []string {
+	if strings.IndexByte(s, '\\') < 0 {
+		return strings.Split(s, string(sep))
+	}
+	var (
+		parts []string
+		b     strings.Builder
+	)
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == sep:
+			b.WriteByte(sep)
+			i++
+		case s[i] == sep:
+			parts = append(parts, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return append(parts, b.String())
+}
+
+func policyPtr(p WhitespacePolicy) *WhitespacePolicy {
+	return &p
+}
+
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+func cutSuffix(s, suffix string) (string, bool) {
+	if !strings.HasSuffix(s, suffix) {
+		return s, false
+	}
+	return s[:len(s)-len(suffix)], true
+}
+
+// checkChoice checks that the value is one of the choices if there are any.
+// The format is the ValueNotAllowed message taking the value and the list of the choices.
+func checkChoice(choices []string, val string, format string) error {
+	if len(choices) == 0 {
+		return nil
+	}
+	for _, c := range choices {
+		if c == val {
+			return nil
+		}
+	}
+	return fmt.Errorf(format, val, strings.Join(choices, ", "))
+}
+
+func joinGroup(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
 		}
 	}
-	return flagMetadata{name, usage, defaultVal, isRequired}, nil
+	return false
 }
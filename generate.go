@@ -0,0 +1,106 @@
+package easyflag
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	generateDocsArg       = "-generate-docs"
+	generateCompletionArg = "-generate-completion"
+)
+
+// DocFormat is a format of the documentation which can be generated by the -generate-docs flag.
+type DocFormat string
+
+// The documentation formats supported by the -generate-docs flag.
+const (
+	DocManPage  DocFormat = "man"
+	DocMarkdown DocFormat = "markdown"
+	DocJSON     DocFormat = "json"
+	DocCatalog  DocFormat = "catalog"
+)
+
+// ErrGenerated is the error returned by the ParseAndLoad function if the documentation or the shell completion script
+// was requested by the -generate-docs or -generate-completion flag. The output is already printed
+// to the standard output at that point.
+var ErrGenerated = errors.New("generation requested")
+
+// UnsupportedDocFormatError is an error returned in case that the documentation is requested in an unsupported format.
+type UnsupportedDocFormatError struct {
+	Format DocFormat
+}
+
+// Error prints the description of the UnsupportedDocFormatError.
+func (e *UnsupportedDocFormatError) Error() string {
+	return fmt.Sprintf("unsupported documentation format %q, the supported formats are %s, %s, %s and %s",
+		e.Format, DocManPage, DocMarkdown, DocJSON, DocCatalog)
+}
+
+/*
+WithGeneratorFlags adds the reserved -generate-docs and -generate-completion flags, so that the documentation
+and the shell completion scripts can be generated directly by the program binary, e.g. in a packaging pipeline:
+
+	program -generate-docs=man > program.1
+	program -generate-completion=bash > program.bash
+
+The -generate-docs flag accepts the man, markdown, json and catalog formats corresponding to the WriteManPage,
+WriteMarkdown, WriteJSON and WriteCatalog functions. The -generate-completion flag accepts the shells supported
+by the WriteCompletion function. If one of the flags is used, the output is printed to the standard output
+and the ErrGenerated error is returned without checking the required flags.
+*/
+func WithGeneratorFlags() Option {
+	return func(o *options) {
+		o.generatorFlags = true
+	}
+}
+
+// generatorFlags returns the reserved flags of the documentation and completion generators.
+func (fb *flagBuilder) generatorFlags() []ReservedFlag {
+	return []ReservedFlag{
+		{
+			Names: []string{generateDocsArg[1:]},
+			Usage: fmt.Sprintf("Prints the documentation in the given format (%s, %s, %s or %s)", DocManPage, DocMarkdown, DocJSON, DocCatalog),
+			Handler: func(format string) error {
+				if err := fb.generateDocs(os.Stdout, DocFormat(format)); err != nil {
+					return err
+				}
+				return ErrGenerated
+			},
+		},
+		{
+			Names: []string{generateCompletionArg[1:]},
+			Usage: fmt.Sprintf("Prints the completion script for the given shell (%s, %s or %s)", Bash, Zsh, Fish),
+			Handler: func(shell string) error {
+				if err := fb.writeCompletion(os.Stdout, Shell(shell)); err != nil {
+					return err
+				}
+				return ErrGenerated
+			},
+		},
+	}
+}
+
+func (fb *flagBuilder) generateDocs(w io.Writer, format DocFormat) error {
+	switch format {
+	case DocManPage:
+		return fb.writeManPage(w)
+	case DocMarkdown:
+		return fb.writeMarkdown(w)
+	case DocJSON:
+		return writeIndentedJSON(w, fb.describe())
+	case DocCatalog:
+		return writeIndentedJSON(w, fb.catalog())
+	default:
+		return &UnsupportedDocFormatError{format}
+	}
+}
+
+func writeIndentedJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
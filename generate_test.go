@@ -0,0 +1,76 @@
+package easyflag
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateDocs(t *testing.T) {
+	params := &struct {
+		In string `flag:"in|Input file path||required"`
+	}{}
+	opts := []Option{WithProgramName("my-tool"), WithGeneratorFlags()}
+	writers := map[DocFormat]func(w *bytes.Buffer) error{
+		DocManPage:  func(w *bytes.Buffer) error { return WriteManPage(w, params, opts...) },
+		DocMarkdown: func(w *bytes.Buffer) error { return WriteMarkdown(w, params, opts...) },
+		DocJSON:     func(w *bytes.Buffer) error { return WriteJSON(w, params, opts...) },
+		DocCatalog:  func(w *bytes.Buffer) error { return WriteCatalog(w, params, opts...) },
+	}
+	for format, write := range writers {
+		t.Run(string(format), func(t *testing.T) {
+			fb, err := newDetachedFlagBuilder(params, opts)
+			assert.NoError(t, err)
+			var got, want bytes.Buffer
+			assert.NoError(t, fb.generateDocs(&got, format))
+			assert.NoError(t, write(&want))
+			assert.Equal(t, want.String(), got.String())
+		})
+	}
+
+	fb, err := newDetachedFlagBuilder(params, opts)
+	assert.NoError(t, err)
+	var buf bytes.Buffer
+	err = fb.generateDocs(&buf, "html")
+	assert.EqualError(t, err, `unsupported documentation format "html", the supported formats are man, markdown, json and catalog`)
+}
+
+func TestParseAndLoad_generatorFlags(t *testing.T) {
+	type generatorParams struct {
+		In string `flag:"in|Input file path||required"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		opts    []Option
+		wantErr string
+	}{
+		{
+			name:    "unsupported format",
+			args:    []string{"-generate-docs=html"},
+			opts:    []Option{WithGeneratorFlags()},
+			wantErr: `unsupported documentation format "html", the supported formats are man, markdown, json and catalog`,
+		},
+		{
+			name:    "unsupported shell",
+			args:    []string{"-generate-completion=tcsh"},
+			opts:    []Option{WithGeneratorFlags()},
+			wantErr: `unsupported shell "tcsh", the supported shells are bash, zsh and fish`,
+		},
+		{
+			name:    "generators disabled by default",
+			args:    []string{"-generate-docs=man"},
+			wantErr: "flag provided but not defined: -generate-docs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = append([]string{"executable_name"}, tt.args...)
+			var p generatorParams
+			err := ParseAndLoad(&p, append(tt.opts, WithOutput(&bytes.Buffer{}))...)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package easyflag
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// WithShortFlagGrouping enables the POSIX-style grouping of the single-character flags for the native parser:
+// the -xvf argument is expanded to -x -v -f, if none of the flags is named xvf. The single-character flags
+// are the flags with a single-character name or a short name defined by the short field tag, so the short names
+// can be used by the native parser too, e.g. -v for the -verbose flag with the short:"v" tag. All the grouped flags
+// but the last one must be boolean. The last one can take a value, either the rest of the argument (-xvfarchive.tar)
+// or the next argument (-xvf archive.tar). The backends set by the WithBackend option handle the short flags
+// themselves, so the option has no effect with them.
+func WithShortFlagGrouping() Option {
+	return func(o *options) {
+		o.shortGrouping = true
+	}
+}
+
+// expandShortFlagGroups expands the groups of the single-character flags to the separate flags named by their
+// full names. The values of the flags passed as separate arguments and the arguments after the -- terminator
+// are not expanded.
+func (fb *flagBuilder) expandShortFlagGroups(args []string) []string {
+	// the short names are unique and they differ from the single-character names of the other flags,
+	// see the checkDuplicateShort method, so no flag overwrites another one
+	byShortName := make(map[string]string)
+	for _, f := range fb.flags {
+		if utf8.RuneCountInString(f.name) == 1 {
+			byShortName[f.name] = f.name
+		}
+	}
+	for _, f := range fb.flags {
+		if f.short != "" {
+			byShortName[f.short] = f.name
+		}
+	}
+
+	expanded := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			return append(expanded, args[i:]...)
+		}
+		group := fb.expandShortFlagGroup(args[i], byShortName)
+		expanded = append(expanded, group...)
+		// the value of the last flag passed as a separate argument is kept as it is, even if it starts with -
+		if name, hasValue, ok := flagArgName(group[len(group)-1]); ok && !hasValue && i+1 < len(args) {
+			if f := fb.flagSet.Lookup(name); f != nil && !isBoolFlag(f) {
+				i++
+				expanded = append(expanded, args[i])
+			}
+		}
+	}
+	return expanded
+}
+
+// expandShortFlagGroup expands the argument to the separate flags if it is a group of the single-character flags.
+// Otherwise, it returns the argument as it is.
+func (fb *flagBuilder) expandShortFlagGroup(arg string, byShortName map[string]string) []string {
+	group, ok := cutPrefix(arg, "-")
+	if !ok || group == "" || strings.HasPrefix(group, "-") {
+		return []string{arg}
+	}
+	if name, _, _ := strings.Cut(group, "="); fb.flagSet.Lookup(name) != nil {
+		return []string{arg}
+	}
+	var expanded []string
+	for i, r := range group {
+		name, ok := byShortName[string(r)]
+		if !ok {
+			return []string{arg}
+		}
+		rest := group[i+utf8.RuneLen(r):]
+		if f := fb.flagSet.Lookup(name); isBoolFlag(f) && !strings.HasPrefix(rest, "=") {
+			expanded = append(expanded, "-"+name)
+			continue
+		}
+		if rest == "" {
+			return append(expanded, "-"+name)
+		}
+		return append(expanded, "-"+name+"="+strings.TrimPrefix(rest, "="))
+	}
+	return expanded
+}
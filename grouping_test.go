@@ -0,0 +1,83 @@
+package easyflag
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_shortFlagGrouping(t *testing.T) {
+	type tarParams struct {
+		Extract bool   `flag:"extract|Extract files" short:"x"`
+		Verbose bool   `flag:"v|Verbose output"`
+		Gzip    bool   `flag:"gzip|Gzip compression" short:"z"`
+		File    string `flag:"file|Archive file" short:"f"`
+		Exclude string `flag:"exclude|Excluded pattern"`
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		want     tarParams
+		wantArgs []string
+	}{
+		{
+			name: "value in the next argument",
+			args: []string{"-xvf", "a.tar", "dir"},
+			want: tarParams{Extract: true, Verbose: true, File: "a.tar"}, wantArgs: []string{"dir"},
+		},
+		{
+			name: "value in the same argument",
+			args: []string{"-xzfa.tar"},
+			want: tarParams{Extract: true, Gzip: true, File: "a.tar"},
+		},
+		{
+			name: "value after the equal sign",
+			args: []string{"-zf=a.tgz"},
+			want: tarParams{Gzip: true, File: "a.tgz"},
+		},
+		{
+			name: "boolean value",
+			args: []string{"-xv=false"},
+			want: tarParams{Extract: true},
+		},
+		{
+			name: "single short flag",
+			args: []string{"-x", "-f", "-a.tar"},
+			want: tarParams{Extract: true, File: "-a.tar"},
+		},
+		{
+			name: "full names",
+			args: []string{"-extract", "-exclude", "-xv", "-v"},
+			want: tarParams{Extract: true, Exclude: "-xv", Verbose: true},
+		},
+		{
+			name: "terminator",
+			args: []string{"-x", "--", "-zv"},
+			want: tarParams{Extract: true}, wantArgs: []string{"-zv"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p tarParams
+			res, err := Parse(&p, WithShortFlagGrouping(),
+				WithArgsSource(StaticArgs(append([]string{"program"}, tt.args...))), WithOutput(io.Discard))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+			assert.ElementsMatch(t, tt.wantArgs, res.Args)
+		})
+	}
+
+	var p tarParams
+	_, err := Parse(&p, WithShortFlagGrouping(), WithArgsSource(StaticArgs{"program", "-xqf"}), WithOutput(io.Discard))
+	assert.EqualError(t, err, "flag provided but not defined: -xqf")
+}
+
+func TestParse_shortFlagGroupingDuplicateShortName(t *testing.T) {
+	var p struct {
+		Extract bool `flag:"extract|Extract files" short:"x"`
+		Exclude bool `flag:"exclude|Exclude files" short:"x"`
+	}
+	_, err := Parse(&p, WithShortFlagGrouping(), WithArgsSource(StaticArgs{"executable_name", "-x"}), WithOutput(io.Discard))
+	assert.EqualError(t, err, "invalid field Exclude: short name -x of the flag -exclude already defined by the field Extract")
+}
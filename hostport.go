@@ -0,0 +1,86 @@
+package easyflag
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+)
+
+var errInvalidPort = errors.New("invalid port")
+
+// HostPort is a flag field type holding a network address in the host:port form, e.g. example.com:8080,
+// [::1]:8080 or :8080. The port must be a number. The host and the port are validated by the net.SplitHostPort
+// function while the flags are parsed.
+//
+// The defaultPort field tag (e.g. defaultPort:"443") sets the port used when the value contains the host only,
+// e.g. example.com is then accepted as example.com:443.
+type HostPort struct {
+	host string
+	port int
+	set  bool
+}
+
+// Host returns the host of the address, which can be empty, e.g. in case of :8080.
+func (hp *HostPort) Host() string {
+	return hp.host
+}
+
+// Port returns the port of the address.
+func (hp *HostPort) Port() int {
+	return hp.port
+}
+
+// String returns the address in the host:port form or an empty string if the address is not set.
+func (hp *HostPort) String() string {
+	if hp == nil || !hp.set {
+		return ""
+	}
+	return net.JoinHostPort(hp.host, strconv.Itoa(hp.port))
+}
+
+// Set parses the address. It implements the flag.Value interface.
+func (hp *HostPort) Set(value string) error {
+	return hp.parse(value, "")
+}
+
+// parse parses the address using the default port, if not empty, when the value contains the host only.
+func (hp *HostPort) parse(value, defaultPort string) error {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		if defaultPort == "" || !isMissingPort(value, err) {
+			return err
+		}
+		host, port = strings.Trim(value, "[]"), defaultPort
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return errInvalidPort
+	}
+	hp.host, hp.port, hp.set = host, int(p), true
+	return nil
+}
+
+func (hp *HostPort) valueName() string {
+	return "host:port"
+}
+
+// hostPortValue is the flag value of the HostPort fields applying the port set by the defaultPort field tag.
+type hostPortValue struct {
+	*HostPort
+	defaultPort string
+}
+
+func (v *hostPortValue) Set(value string) error {
+	return v.parse(value, v.defaultPort)
+}
+
+// isMissingPort reports whether the address failed to be split only because it is a host without a port,
+// including a bare IPv6 address, e.g. ::1 or [::1].
+func isMissingPort(value string, err error) bool {
+	var addrErr *net.AddrError
+	if errors.As(err, &addrErr) && addrErr.Err == "missing port in address" {
+		return true
+	}
+	return net.ParseIP(strings.Trim(value, "[]")) != nil
+}
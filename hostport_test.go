@@ -0,0 +1,62 @@
+package easyflag
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostPort_Set(t *testing.T) {
+	tests := []struct {
+		in          string
+		defaultPort string
+		wantHost    string
+		wantPort    int
+		wantString  string
+		wantErr     string
+	}{
+		{in: "example.com:8080", wantHost: "example.com", wantPort: 8080, wantString: "example.com:8080"},
+		{in: "[::1]:443", wantHost: "::1", wantPort: 443, wantString: "[::1]:443"},
+		{in: ":8080", wantPort: 8080, wantString: ":8080"},
+		{in: "example.com", defaultPort: "443", wantHost: "example.com", wantPort: 443, wantString: "example.com:443"},
+		{in: "::1", defaultPort: "443", wantHost: "::1", wantPort: 443, wantString: "[::1]:443"},
+		{in: "[::1]", defaultPort: "443", wantHost: "::1", wantPort: 443, wantString: "[::1]:443"},
+		{in: "example.com", wantErr: "address example.com: missing port in address"},
+		{in: "a:b:c", defaultPort: "443", wantErr: "address a:b:c: too many colons in address"},
+		{in: "example.com:http", wantErr: "invalid port"},
+		{in: "example.com:70000", wantErr: "invalid port"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var hp HostPort
+			err := hp.parse(tt.in, tt.defaultPort)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Equal(t, "", hp.String())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHost, hp.Host())
+			assert.Equal(t, tt.wantPort, hp.Port())
+			assert.Equal(t, tt.wantString, hp.String())
+		})
+	}
+}
+
+func TestParseAndLoad_hostPort(t *testing.T) {
+	type addrParams struct {
+		Listen   HostPort `flag:"listen|Listen address|:8080"`
+		Upstream HostPort `flag:"upstream|Upstream server||required" defaultPort:"443"`
+	}
+	var p addrParams
+	err := ParseAndLoad(&p, WithArgsSource(StaticArgs{"program", "-upstream=example.com"}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, ":8080", p.Listen.String())
+	assert.Equal(t, "example.com", p.Upstream.Host())
+	assert.Equal(t, 443, p.Upstream.Port())
+
+	var missing addrParams
+	err = ParseAndLoad(&missing, WithArgsSource(StaticArgs{"program"}), WithOutput(io.Discard))
+	assert.EqualError(t, err, `missing required flag "upstream" or its value`)
+}
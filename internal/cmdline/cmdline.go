@@ -0,0 +1,47 @@
+// Package cmdline splits the command lines into the arguments, e.g. the content of the response files
+// or the command lines of the easyflagtest helpers, so that they all follow the same quoting rules.
+package cmdline
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Split splits the command line into the arguments separated by whitespace including newlines.
+// The parts quoted by the double or single quotes are kept together, the quotes themselves are removed,
+// e.g. "-name 'a b'" is split into -name and a b.
+func Split(s string) ([]string, error) {
+	var (
+		args  []string
+		b     strings.Builder
+		inArg bool
+		quote rune
+	)
+	for _, r := range s {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			b.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, b.String())
+				b.Reset()
+				inArg = false
+			}
+		default:
+			b.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote %c", quote)
+	}
+	if inArg {
+		args = append(args, b.String())
+	}
+	return args, nil
+}
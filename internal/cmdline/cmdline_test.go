@@ -0,0 +1,35 @@
+package cmdline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr string
+	}{
+		{in: ""},
+		{in: "  -a  b\t-c\n", want: []string{"-a", "b", "-c"}},
+		{in: "-a 1\n-b=2\r\n\t-c", want: []string{"-a", "1", "-b=2", "-c"}},
+		{in: `-name "a b" -x 'c "d"' ""`, want: []string{"-name", "a b", "-x", `c "d"`, ""}},
+		{in: `-name="a b" -path='C:\Program Files\x'`, want: []string{"-name=a b", `-path=C:\Program Files\x`}},
+		{in: `-name=a" b"c`, want: []string{"-name=a bc"}},
+		{in: `-q="it's"`, want: []string{"-q=it's"}},
+		{in: `-name "a b`, wantErr: "unterminated quote \""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Split(tt.in)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
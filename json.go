@@ -0,0 +1,51 @@
+package easyflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+// jsonFlagValue is the flag value of the fields with the json flag option, e.g. structures, maps or slices,
+// which are decoded from the JSON values.
+type jsonFlagValue struct {
+	ptr reflect.Value // the pointer to the field
+}
+
+func (v *jsonFlagValue) String() string {
+	if !v.ptr.IsValid() || v.ptr.Elem().IsZero() {
+		return ""
+	}
+	b, err := json.Marshal(v.ptr.Interface())
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// Set decodes the JSON value. The unknown keys of the structures are reported as errors, because they are
+// most likely typos. The field is not modified if the value is invalid.
+func (v *jsonFlagValue) Set(s string) error {
+	decoded := reflect.New(v.ptr.Elem().Type())
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(decoded.Interface()); err != nil {
+		return err
+	}
+	v.ptr.Elem().Set(decoded.Elem())
+	return nil
+}
+
+func (v *jsonFlagValue) valueName() string {
+	return "json"
+}
+
+// isJSONField reports whether the field has the json flag option and it should be decoded from the JSON values.
+func (fb *flagBuilder) isJSONField(flagMetadataStr string) bool {
+	if flagMetadataStr == "" {
+		return false
+	}
+	fm, err := fb.parseFieldMetadata(flagMetadataStr)
+	// the invalid metadata is reported when the flag is attached
+	return err == nil && fm.isJSON
+}
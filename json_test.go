@@ -0,0 +1,77 @@
+package easyflag
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type retryOptions struct {
+	Retries int  `json:"retries"`
+	Debug   bool `json:"debug"`
+}
+
+type jsonParams struct {
+	Opts    retryOptions      `flag:"opts|Retry options|{\"retries\":1}|json"`
+	Labels  map[string]string `flag:"labels|Labels||json"`
+	Weights []float64         `flag:"weights|Weights||json"`
+}
+
+func TestParseAndLoad_json(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    jsonParams
+		wantErr string
+	}{
+		{
+			name: "default",
+			args: []string{"program"},
+			want: jsonParams{Opts: retryOptions{Retries: 1}},
+		},
+		{
+			name: "set",
+			args: []string{"program", `-opts={"retries":3,"debug":true}`, `-labels={"env":"prod"}`, "-weights=[0.5,1.5]"},
+			want: jsonParams{
+				Opts:    retryOptions{Retries: 3, Debug: true},
+				Labels:  map[string]string{"env": "prod"},
+				Weights: []float64{0.5, 1.5},
+			},
+		},
+		{
+			name:    "unknown key",
+			args:    []string{"program", `-opts={"retry":3}`},
+			wantErr: `invalid value "{\"retry\":3}" for flag -opts: json: unknown field "retry"`,
+		},
+		{
+			name:    "invalid JSON",
+			args:    []string{"program", "-weights=[1,"},
+			wantErr: `invalid value "[1," for flag -weights: unexpected EOF`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p jsonParams
+			err := ParseAndLoad(&p, WithArgsSource(StaticArgs(tt.args)), WithOutput(io.Discard))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
+
+func TestParseAndLoad_jsonConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"opts": {"retries": 5}, "weights": [1, 2]}`), 0o600))
+	var p jsonParams
+	err := ParseAndLoad(&p, WithConfigFlag("config"), WithArgsSource(StaticArgs{"program", "-config=" + path}),
+		WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, jsonParams{Opts: retryOptions{Retries: 5}, Weights: []float64{1, 2}}, p)
+}
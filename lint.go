@@ -0,0 +1,24 @@
+package easyflag
+
+import (
+	"flag"
+	"fmt"
+)
+
+// lintFlag writes the warnings about the style issues of the flag definition to the writer set
+// by the WithLintWarnings option. The warnings never stop the parsing.
+func (fb *flagBuilder) lintFlag(fm flagMetadata, f *flag.Flag) {
+	w := fb.opts.lintOutput
+	if w == nil {
+		return
+	}
+	if fm.usage == "" {
+		fmt.Fprintf(w, "lint warning: flag -%s: usage text missing\n", fm.name)
+	}
+	if fm.defaultVal != "" && isZeroValue(f) {
+		fmt.Fprintf(w, "lint warning: flag -%s: default value %q equals the zero value and can be omitted\n", fm.name, fm.defaultVal)
+	}
+	if fm.isRequired && fm.ignoredDefault != "" {
+		fmt.Fprintf(w, "lint warning: flag -%s: default value %q of the required flag is ignored\n", fm.name, fm.ignoredDefault)
+	}
+}
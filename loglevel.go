@@ -0,0 +1,57 @@
+package easyflag
+
+import (
+	"errors"
+	"flag"
+	"strings"
+)
+
+// logLevels are the names of the log levels accepted by the LogLevel and slog.Level fields.
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+var errUnknownLevel = errors.New("unknown level, the allowed values are " + strings.Join(logLevels, ", "))
+
+// choicesLister is implemented by the flag values accepting only a fixed set of values. The values are listed
+// in the usage message, the generated documentation and the shell completions just like the choices option.
+type choicesLister interface {
+	choices() []string
+}
+
+// LogLevel is a flag field type holding the name of a log level: debug, info, warn or error. The values are accepted
+// case-insensitively and the warning alias of warn is accepted as well. The value is normalized to the lower case,
+// so that it can be converted to the level of any logging library by a simple switch.
+// The slog.Level fields are supported directly.
+type LogLevel string
+
+// String returns the name of the level.
+func (l *LogLevel) String() string {
+	if l == nil {
+		return ""
+	}
+	return string(*l)
+}
+
+// Set parses the name of the level. It implements the flag.Value interface.
+func (l *LogLevel) Set(value string) error {
+	v := strings.ToLower(value)
+	if v == "warning" {
+		v = "warn"
+	}
+	for _, level := range logLevels {
+		if v == level {
+			*l = LogLevel(v)
+			return nil
+		}
+	}
+	return errUnknownLevel
+}
+
+func (l *LogLevel) valueName() string {
+	return strings.Join(logLevels, "|")
+}
+
+func (l *LogLevel) choices() []string {
+	return logLevels
+}
+
+var _ flag.Value = (*LogLevel)(nil)
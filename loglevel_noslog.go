@@ -0,0 +1,11 @@
+//go:build !go1.21
+
+package easyflag
+
+import "flag"
+
+// versionedFlagValue returns the flag value of the field types available only in the newer Go versions.
+// There are none before Go 1.21, which added the log/slog package.
+func versionedFlagValue(interface{}) (flag.Value, bool) {
+	return nil, false
+}
@@ -0,0 +1,50 @@
+//go:build go1.21
+
+package easyflag
+
+import (
+	"flag"
+	"log/slog"
+	"strings"
+)
+
+// slogLevelValue is the flag value of the slog.Level fields. Besides the level names accepted by the LogLevel type,
+// it accepts the offsets supported by the slog.Level.UnmarshalText method, e.g. info+2.
+type slogLevelValue struct {
+	level *slog.Level
+}
+
+func (v *slogLevelValue) String() string {
+	if v.level == nil {
+		return strings.ToLower(slog.LevelInfo.String())
+	}
+	return strings.ToLower(v.level.String())
+}
+
+func (v *slogLevelValue) Set(s string) error {
+	if strings.EqualFold(s, "warning") {
+		s = "warn"
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return errUnknownLevel
+	}
+	*v.level = level
+	return nil
+}
+
+func (v *slogLevelValue) valueName() string {
+	return strings.Join(logLevels, "|")
+}
+
+func (v *slogLevelValue) choices() []string {
+	return logLevels
+}
+
+// versionedFlagValue returns the flag value of the field types available only in the newer Go versions.
+func versionedFlagValue(ptr interface{}) (flag.Value, bool) {
+	if level, ok := ptr.(*slog.Level); ok {
+		return &slogLevelValue{level: level}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,97 @@
+//go:build go1.21
+
+package easyflag
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogLevel_Set(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    LogLevel
+		wantErr error
+	}{
+		{in: "debug", want: "debug"},
+		{in: "INFO", want: "info"},
+		{in: "Warning", want: "warn"},
+		{in: "error", want: "error"},
+		{in: "fatal", wantErr: errUnknownLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var l LogLevel
+			assert.Equal(t, tt.wantErr, l.Set(tt.in))
+			assert.Equal(t, tt.want, l)
+		})
+	}
+}
+
+func TestParseAndLoad_logLevel(t *testing.T) {
+	type levelParams struct {
+		Level     LogLevel   `flag:"level|Log level|info"`
+		SlogLevel slog.Level `flag:"slog-level|Structured log level|warn"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		want    levelParams
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			args: []string{"program"},
+			want: levelParams{Level: "info", SlogLevel: slog.LevelWarn},
+		},
+		{
+			name: "set",
+			args: []string{"program", "-level=DEBUG", "-slog-level=Error"},
+			want: levelParams{Level: "debug", SlogLevel: slog.LevelError},
+		},
+		{
+			name: "offset",
+			args: []string{"program", "-slog-level=info+2"},
+			want: levelParams{Level: "info", SlogLevel: slog.LevelInfo + 2},
+		},
+		{
+			name:    "unknown level",
+			args:    []string{"program", "-slog-level=verbose"},
+			wantErr: `invalid value "verbose" for flag -slog-level: unknown level, the allowed values are debug, info, warn, error`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p levelParams
+			err := ParseAndLoad(&p, WithArgsSource(StaticArgs(tt.args)), WithOutput(io.Discard))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
+
+func TestWriteUsage_logLevel(t *testing.T) {
+	type levelParams struct {
+		Level     LogLevel   `flag:"level|Log level|info"`
+		SlogLevel slog.Level `flag:"slog-level|Structured log level|warn"`
+	}
+	var p levelParams
+	var buf bytes.Buffer
+	err := ParseAndLoad(&p, WithArgsSource(StaticArgs{"program", "-h"}), WithOutput(&buf), WithColor(ColorNever),
+		WithoutExit())
+	assert.Error(t, err)
+	assert.Contains(t, buf.String(), "  -level debug|info|warn|error\n    \tLog level (default info)\n")
+	assert.Contains(t, buf.String(), "  -slog-level debug|info|warn|error\n    \tStructured log level (default warn)\n")
+
+	d, err := Describe(&p)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"debug", "info", "warn", "error"}, d.Flags[0].Choices)
+}
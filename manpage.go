@@ -0,0 +1,128 @@
+package easyflag
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+/*
+WriteManPage writes the man page of the program in the roff format to w. The page is generated from the flag metadata
+of the params structure, which must be a pointer to a structure just like in the case of the ParseAndLoad function.
+The passed structure is not modified.
+
+The page contains the name, synopsis and description of the program, the description of every flag including its
+default value and whether it is required, and the usage examples. The program name and version shown are taken
+from the WithProgramName and WithVersion options, or from the params structure implementing the Versioner interface.
+The description and examples are taken from the WithDescription and WithExamples options, or from the params
+structure implementing the Describer interface.
+*/
+func WriteManPage(w io.Writer, params interface{}, opts ...Option) error {
+	fb, err := newDetachedFlagBuilder(params, opts)
+	if err != nil {
+		return err
+	}
+	return fb.writeManPage(w)
+}
+
+func (fb *flagBuilder) writeManPage(w io.Writer) error {
+	prog := fb.opts.programName()
+	var b strings.Builder
+	source := prog
+	if fb.opts.version != "" {
+		source += " " + fb.opts.version
+	}
+	fmt.Fprintf(&b, ".TH %s 1 \"\" %s \"User Commands\"\n", roffQuote(strings.ToUpper(prog)), roffQuote(source))
+
+	b.WriteString(".SH NAME\n")
+	if fb.opts.description != "" {
+		fmt.Fprintf(&b, "%s \\- %s\n", roffEscape(prog), roffEscape(firstLine(fb.opts.description)))
+	} else {
+		fmt.Fprintf(&b, "%s\n", roffEscape(prog))
+	}
+
+	b.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(&b, ".B %s\n", roffEscape(prog))
+	for _, f := range fb.flags {
+		if f.isRequired {
+			fmt.Fprintf(&b, "\\fB\\-%s\\fR %s\n", roffEscape(f.name), roffValueName(f))
+		}
+	}
+	b.WriteString("[\\fIOPTIONS\\fR]\n")
+
+	if fb.opts.description != "" {
+		b.WriteString(".SH DESCRIPTION\n")
+		fmt.Fprintf(&b, "%s\n", roffEscape(fb.opts.description))
+	}
+
+	b.WriteString(".SH OPTIONS\n")
+	for _, f := range append(fb.flags, fb.builtinFlags()...) {
+		b.WriteString(".TP\n")
+		fmt.Fprintf(&b, "\\fB\\-%s\\fR", roffEscape(f.name))
+		if vn := roffValueName(f); vn != "" {
+			b.WriteString(" " + vn)
+		}
+		b.WriteString("\n")
+		var details []string
+		if f.usage != "" {
+			details = append(details, roffEscape(f.usage))
+		}
+		if len(f.choices) > 0 {
+			details = append(details, fmt.Sprintf("Allowed values: %s.", roffEscape(strings.Join(f.choices, ", "))))
+		}
+		if f.defaultVal != "" {
+			details = append(details, fmt.Sprintf("Default: %s.", roffEscape(f.displayedDefault())))
+		}
+		if f.example != "" {
+			details = append(details, fmt.Sprintf("Example: %s.", roffEscape(f.example)))
+		}
+		if f.env != "" {
+			details = append(details, fmt.Sprintf("Environment variable: %s.", roffEscape(f.env)))
+		}
+		if f.isRequired {
+			details = append(details, "Required.")
+		}
+		b.WriteString(strings.Join(details, "\n") + "\n")
+	}
+
+	if len(fb.opts.examples) > 0 {
+		b.WriteString(".SH EXAMPLES\n")
+		for _, e := range fb.opts.examples {
+			fmt.Fprintf(&b, ".PP\n.nf\n%s\n.fi\n", roffEscape(e))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+func roffValueName(f flagInfo) string {
+	if f.displayName() == "" {
+		return ""
+	}
+	return fmt.Sprintf("\\fI%s\\fR", roffEscape(f.displayName()))
+}
+
+// roffEscape escapes the characters with a special meaning in the roff format.
+func roffEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		// a line starting with a dot or an apostrophe would be interpreted as a control line
+		if strings.HasPrefix(l, ".") || strings.HasPrefix(l, "'") {
+			lines[i] = `\&` + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func roffQuote(s string) string {
+	return `"` + strings.ReplaceAll(roffEscape(s), `"`, `\(dq`) + `"`
+}
@@ -0,0 +1,100 @@
+package easyflag
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteManPage(t *testing.T) {
+	params := &struct {
+		In     string `flag:"in|Input file path||required"`
+		Format string `flag:"fmt|Output format|json|choices=json yaml"`
+		IsV    bool   `flag:"v|Verbose output"`
+		Len    int    `flag:"n|Number of .lines to print|-1"`
+	}{}
+	want := `.TH "MY\-TOOL" 1 "" "my\-tool v1.2.3" "User Commands"
+.SH NAME
+my\-tool
+.SH SYNOPSIS
+.B my\-tool
+\fB\-in\fR \fIstring\fR
+[\fIOPTIONS\fR]
+.SH OPTIONS
+.TP
+\fB\-in\fR \fIstring\fR
+Input file path
+Required.
+.TP
+\fB\-fmt\fR \fIstring\fR
+Output format
+Allowed values: json, yaml.
+Default: json.
+.TP
+\fB\-v\fR
+Verbose output
+.TP
+\fB\-n\fR \fIint\fR
+Number of .lines to print
+Default: \-1.
+.TP
+\fB\-h\fR
+Prints the usage information
+.TP
+\fB\-help\fR
+Prints the usage information
+.TP
+\fB\-V\fR
+Prints the version information
+.TP
+\fB\-version\fR
+Prints the version information
+`
+	var buf bytes.Buffer
+	err := WriteManPage(&buf, params, WithProgramName("my-tool"), WithVersion("v1.2.3"))
+	assert.NoError(t, err)
+	assert.Equal(t, want, buf.String())
+	assert.Empty(t, params.Format, "the passed structure must not be modified")
+}
+
+func TestWriteManPage_description(t *testing.T) {
+	want := `.TH "PROGRAM" 1 "" "program" "User Commands"
+.SH NAME
+program \- Copies the input file to the standard output.
+.SH SYNOPSIS
+.B program
+\fB\-in\fR \fIstring\fR
+[\fIOPTIONS\fR]
+.SH DESCRIPTION
+Copies the input file to the standard output.
+.SH OPTIONS
+.TP
+\fB\-in\fR \fIstring\fR
+Path to the input file
+Required.
+.TP
+\fB\-h\fR
+Prints the usage information
+.TP
+\fB\-help\fR
+Prints the usage information
+.SH EXAMPLES
+.PP
+.nf
+program \-in file.txt
+.fi
+.PP
+.nf
+program \-in file.txt \-n 10
+.fi
+`
+	var buf bytes.Buffer
+	err := WriteManPage(&buf, &describedParams{}, WithProgramName("program"))
+	assert.NoError(t, err)
+	assert.Equal(t, want, buf.String())
+}
+
+func TestRoffEscape(t *testing.T) {
+	assert.Equal(t, `a\-b \e .dot`+"\n"+`\&.dot`+"\n"+`\&'quote`, roffEscape("a-b \\ .dot\n.dot\n'quote"))
+}
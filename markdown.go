@@ -0,0 +1,104 @@
+package easyflag
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+/*
+WriteMarkdown writes the reference of all the program flags in the Markdown format to w. The reference is generated
+from the flag metadata of the params structure, which must be a pointer to a structure just like in the case
+of the ParseAndLoad function. The passed structure is not modified.
+
+The flags are listed in tables containing their types, default values, required markers and descriptions.
+The flags of the nested structures are listed in separate sections named by their flagGroup tags
+or by the paths of the nested structure fields.
+*/
+func WriteMarkdown(w io.Writer, params interface{}, opts ...Option) error {
+	fb, err := newDetachedFlagBuilder(params, opts)
+	if err != nil {
+		return err
+	}
+	return fb.writeMarkdown(w)
+}
+
+func (fb *flagBuilder) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", markdownEscape(fb.opts.programName()))
+	if fb.opts.description != "" {
+		fmt.Fprintf(&b, "\n%s\n", fb.opts.description)
+	}
+	if len(fb.opts.examples) > 0 {
+		b.WriteString("\n```shell\n")
+		for _, e := range fb.opts.examples {
+			fmt.Fprintf(&b, "%s\n", e)
+		}
+		b.WriteString("```\n")
+	}
+	for _, g := range groupFlags(append(fb.flags, fb.builtinFlags()...)) {
+		b.WriteString("\n")
+		if g.name != "" {
+			fmt.Fprintf(&b, "## %s\n\n", markdownEscape(g.name))
+		}
+		b.WriteString("| Flag | Type | Default | Required | Description |\n")
+		b.WriteString("|------|------|---------|----------|-------------|\n")
+		for _, f := range g.flags {
+			typeName := f.valueName
+			if f.isBool {
+				typeName = "bool"
+			}
+			var defaultVal, required string
+			if f.defaultVal != "" {
+				defaultVal = fmt.Sprintf("`%s`", f.displayedDefault())
+			}
+			if f.isRequired {
+				required = "**yes**"
+			}
+			usage := f.usage
+			if len(f.choices) > 0 {
+				usage = strings.TrimSpace(fmt.Sprintf("%s (allowed values: %s)", usage, strings.Join(f.choices, ", ")))
+			}
+			if f.example != "" {
+				usage = strings.TrimSpace(fmt.Sprintf("%s (example: `%s`)", usage, f.example))
+			}
+			if f.env != "" {
+				usage = strings.TrimSpace(fmt.Sprintf("%s (env `%s`)", usage, f.env))
+			}
+			fmt.Fprintf(&b, "| `-%s` | %s | %s | %s | %s |\n",
+				f.name, typeName, markdownEscape(defaultVal), required, markdownEscape(usage))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type flagGroup struct {
+	name  string
+	flags []flagInfo
+}
+
+// groupFlags groups the flags by the nested structures defining them. The groups are ordered by their first occurrence
+// except for the top-level flags that always come first.
+func groupFlags(flags []flagInfo) []flagGroup {
+	groups := []flagGroup{{}}
+	index := map[string]int{"": 0}
+	for _, f := range flags {
+		i, ok := index[f.group]
+		if !ok {
+			i = len(groups)
+			index[f.group] = i
+			groups = append(groups, flagGroup{name: f.group})
+		}
+		groups[i].flags = append(groups[i].flags, f)
+	}
+	if len(groups[0].flags) == 0 {
+		return groups[1:]
+	}
+	return groups
+}
+
+// markdownEscape escapes the characters breaking the Markdown table cells.
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,44 @@
+package easyflag
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	type serverInfo struct {
+		Host string `flag:"a|Server host address|127.0.0.1"`
+		Port int    `flag:"p|Server port|80"`
+	}
+	params := &struct {
+		Username   string `flag:"user|Username||required"`
+		Format     string `flag:"fmt|Output format: json or yaml|json|choices=json yaml"`
+		ServerInfo serverInfo
+	}{}
+	want := "# my-tool\n" +
+		"\n" +
+		"| Flag | Type | Default | Required | Description |\n" +
+		"|------|------|---------|----------|-------------|\n" +
+		"| `-user` | string |  | **yes** | Username |\n" +
+		"| `-fmt` | string | `json` |  | Output format: json or yaml (allowed values: json, yaml) |\n" +
+		"| `-h` | bool |  |  | Prints the usage information |\n" +
+		"| `-help` | bool |  |  | Prints the usage information |\n" +
+		"\n" +
+		"## ServerInfo\n" +
+		"\n" +
+		"| Flag | Type | Default | Required | Description |\n" +
+		"|------|------|---------|----------|-------------|\n" +
+		"| `-a` | string | `127.0.0.1` |  | Server host address |\n" +
+		"| `-p` | int | `80` |  | Server port |\n"
+
+	var buf bytes.Buffer
+	err := WriteMarkdown(&buf, params, WithProgramName("my-tool"))
+	assert.NoError(t, err)
+	assert.Equal(t, want, buf.String())
+}
+
+func TestMarkdownEscape(t *testing.T) {
+	assert.Equal(t, `json\|yaml multi line`, markdownEscape("json|yaml multi\nline"))
+}
@@ -0,0 +1,42 @@
+package easyflag
+
+import (
+	"reflect"
+)
+
+/*
+MarshalArgs returns the CLI arguments which reproduce the params structure when they are parsed by the ParseAndLoad
+function with the same options, e.g. to spawn a worker subprocess with the same configuration:
+
+	args, err := easyflag.MarshalArgs(&p)
+	[...]
+	cmd := exec.Command(os.Args[0], args...)
+
+The arguments are in the -name=value form in the order of the structure fields. The flags whose values are equal
+to their default values are omitted, except for the required flags which are always included. The flags of
+the structures passed by the WithNamespace and WithParams options are not included. The passed structure
+is not modified.
+
+The secret flags are never included, because the command line arguments of a process are visible to the other
+users of the system, e.g. by the ps command. They must be passed to the subprocess by other means, e.g. by
+the environment variables read by the env tag or by the files read by the _FILE variables.
+*/
+func MarshalArgs(params interface{}, opts ...Option) ([]string, error) {
+	fb, err := newDetachedFlagBuilder(params, opts)
+	if err != nil {
+		return nil, err
+	}
+	fb.detached.Elem().Set(reflect.ValueOf(params).Elem())
+
+	var args []string
+	for _, f := range fb.flags {
+		if f.external || f.isSecret {
+			continue
+		}
+		fl := fb.flagSet.Lookup(f.name)
+		if val := fl.Value.String(); val != fl.DefValue || f.isRequired {
+			args = append(args, "-"+f.name+"="+val)
+		}
+	}
+	return args, nil
+}
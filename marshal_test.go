@@ -0,0 +1,53 @@
+package easyflag
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalArgs(t *testing.T) {
+	type workerParams struct {
+		Host    string        `flag:"host|Server host|localhost"`
+		Port    int           `flag:"port|Server port|80"`
+		Verbose bool          `flag:"v|Verbose output"`
+		Timeout time.Duration `flag:"timeout|Timeout|1s"`
+		Labels  Set           `flag:"label|Labels|a"`
+		Token   string        `flag:"token|Token||required"`
+		Ignored string
+	}
+	p := workerParams{Host: "localhost", Port: 8080, Verbose: true, Timeout: time.Second, Labels: newTestSet("x", "y"), Token: "t", Ignored: "i"}
+	args, err := MarshalArgs(&p)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"-port=8080", "-v=true", "-label=x,y", "-token=t"}, args)
+	assert.Equal(t, "i", p.Ignored)
+	assert.Equal(t, newTestSet("x", "y"), p.Labels)
+
+	var reparsed workerParams
+	err = ParseAndLoad(&reparsed, WithArgsSource(StaticArgs(append([]string{"worker"}, args...))), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	p.Ignored = ""
+	assert.Equal(t, p, reparsed)
+
+	_, err = MarshalArgs(p)
+	assert.Error(t, err)
+}
+
+func TestMarshalArgs_requiredAndSecret(t *testing.T) {
+	type workerParams struct {
+		Req      int    `flag:"req|Required number|5|required"`
+		Password string `flag:"password|Password||secret" env:"PASSWORD"`
+	}
+	p := workerParams{Req: 5, Password: "hunter2"}
+	args, err := MarshalArgs(&p)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"-req=5"}, args)
+
+	var reparsed workerParams
+	err = ParseAndLoad(&reparsed, WithArgsSource(StaticArgs(append([]string{"worker"}, args...))),
+		WithEnvSource(MapEnv{"PASSWORD": "hunter2"}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, p, reparsed)
+}
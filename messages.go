@@ -0,0 +1,79 @@
+package easyflag
+
+import "reflect"
+
+/*
+Messages is the table of the user-facing messages of the parse errors and the usage message.
+The messages are the format strings of the fmt package, each of them taking the same arguments
+in the same order as its default value shown in the field comment. The empty fields keep their default values.
+
+The messages of the native flag package (e.g. "flag provided but not defined") and the errors describing
+the invalid flag definitions, which are meant for the developers rather than the users, are not included.
+*/
+type Messages struct {
+	MissingRequiredFlag     string // missing required flag %q or its value
+	MissingRequiredFlags    string // missing required flags %q or their values
+	ValueNotAllowed         string // value %q not allowed, the allowed values are %s
+	InvalidEnvValue         string // invalid value %q of the environment variable %s for the flag -%s: %w
+	InvalidConfigValue      string // invalid value %q of the key %s in the configuration file %s: %w
+	DuplicateValue          string // warning: duplicate value %q of the flag -%s ignored
+	RepeatedFlag            string // warning: flag -%s used %d times, the last value is used
+	RepeatedFlagRejected    string // flag -%s used %d times, it can be used only once
+	ArgsPreprocessingFailed string // args preprocessing failed: %w
+	ValidationFailed        string // validation failed: %w
+	PostProcessingFailed    string // post-processing failed: %w
+	ExtensionFailed         string // extension running failed: %w
+
+	Usage      string // Usage:
+	UsageHint  string // run '%s -h' for the usage
+	Examples   string // Examples:
+	Required   string // (required)
+	RequiredIn string // (required in %s)
+	Default    string // (default %s)
+	Example    string // (example %s)
+	Env        string // (env %s)
+}
+
+var defaultMessages = Messages{
+	MissingRequiredFlag:     "missing required flag %q or its value",
+	MissingRequiredFlags:    "missing required flags %q or their values",
+	ValueNotAllowed:         "value %q not allowed, the allowed values are %s",
+	InvalidEnvValue:         "invalid value %q of the environment variable %s for the flag -%s: %w",
+	InvalidConfigValue:      "invalid value %q of the key %s in the configuration file %s: %w",
+	DuplicateValue:          "warning: duplicate value %q of the flag -%s ignored",
+	RepeatedFlag:            "warning: flag -%s used %d times, the last value is used",
+	RepeatedFlagRejected:    "flag -%s used %d times, it can be used only once",
+	ArgsPreprocessingFailed: "args preprocessing failed: %w",
+	ValidationFailed:        "validation failed: %w",
+	PostProcessingFailed:    "post-processing failed: %w",
+	ExtensionFailed:         "extension running failed: %w",
+
+	Usage:      "Usage:",
+	UsageHint:  "run '%s -h' for the usage",
+	Examples:   "Examples:",
+	Required:   "(required)",
+	RequiredIn: "(required in %s)",
+	Default:    "(default %s)",
+	Example:    "(example %s)",
+	Env:        "(env %s)",
+}
+
+// withDefaults returns the messages with the empty fields set to their default values.
+func (m Messages) withDefaults() Messages {
+	v := reflect.ValueOf(&m).Elem()
+	defaults := reflect.ValueOf(defaultMessages)
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).String() == "" {
+			v.Field(i).Set(defaults.Field(i))
+		}
+	}
+	return m
+}
+
+// WithMessages overrides the user-facing messages, e.g. to localize the parse errors and the usage message.
+// See the Messages type for more details.
+func WithMessages(m Messages) Option {
+	return func(o *options) {
+		o.messages = m
+	}
+}
@@ -0,0 +1,60 @@
+package easyflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+)
+
+/*
+MustParseAndLoad works the same way as the ParseAndLoad function, but instead of returning an error it terminates
+the program. This replaces the usual boilerplate of the main functions:
+
+	var p params
+	easyflag.MustParseAndLoad(&p)
+
+If the parsing fails, the error followed by the usage message is printed to the output set by the WithOutput option
+(the standard error output by default) and the program exits with the status code set by the WithExitCode option
+for the category of the error, or by the WithErrorExitCode option (2 by default). If the help, the version information,
+the completion script, the generated documentation or the effective configuration was requested, the program exits
+with the status code 0 after it is printed.
+
+The exit function can be replaced by the WithExitFunc option. If the replacement returns, MustParseAndLoad returns
+as well and the params structure is left in the same state as after the failed ParseAndLoad call.
+*/
+func MustParseAndLoad(params interface{}, opts ...Option) {
+	_, fb, err := parse(params, opts)
+	if err == nil {
+		return
+	}
+	o := newParamsOptions(params, opts)
+	if isTerminationRequest(err) {
+		if !errors.Is(err, flag.ErrHelp) { // the exit function has already been called in case of the help
+			o.exit(0)
+		}
+		return
+	}
+	out := o.errorWriter()
+	// the flag set has already printed the error and the usage in case of a flag parsing error
+	if fb == nil || !fb.usagePrinted {
+		fmt.Fprintf(out, "%s: %v\n", o.programName(), err)
+		if fb != nil && o.errorUsage == FullUsageOnError {
+			fmt.Fprintln(out)
+		}
+		if fb != nil {
+			fb.writeErrorUsage(out)
+		}
+	}
+	o.exit(o.errorExitCodeOf(err))
+}
+
+// isTerminationRequest reports whether the error returned by the Parse function signals that the program should stop
+// without a failure, e.g. because the help or the version information was requested.
+func isTerminationRequest(err error) bool {
+	for _, target := range []error{flag.ErrHelp, ErrVersion, ErrCompletion, ErrGenerated, ErrConfigPrinted} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package easyflag
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMustParseAndLoad(t *testing.T) {
+	type mustParams struct {
+		In string `flag:"in|Input file||required"`
+	}
+	tests := []struct {
+		name         string
+		args         []string
+		opts         []Option
+		want         mustParams
+		wantExitCode int
+		wantOutput   string
+	}{
+		{
+			name:         "success",
+			args:         []string{"-in=a.txt"},
+			want:         mustParams{In: "a.txt"},
+			wantExitCode: -1,
+		},
+		{
+			name:         "missing required flag",
+			wantExitCode: 2,
+			wantOutput:   "prog: missing required flag \"in\" or its value\n\nUsage:\n  -in string\n    \tInput file (required)\n",
+		},
+		{
+			name:         "flag parsing error printed once",
+			args:         []string{"-out=x"},
+			wantExitCode: 2,
+			wantOutput:   "flag provided but not defined: -out\nUsage:\n  -in string\n    \tInput file (required)\n",
+		},
+		{
+			name:         "custom exit code",
+			opts:         []Option{WithErrorExitCode(1)},
+			wantExitCode: 1,
+			wantOutput:   "prog: missing required flag \"in\" or its value\n\nUsage:\n  -in string\n    \tInput file (required)\n",
+		},
+		{
+			name:         "help",
+			args:         []string{"-h"},
+			wantExitCode: 0,
+			wantOutput:   "Usage:\n  -in string\n    \tInput file (required)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = append([]string{"prog"}, tt.args...)
+			var buf bytes.Buffer
+			exitCode := -1
+			var p mustParams
+			opts := append(tt.opts, WithOutput(&buf), WithColor(ColorNever), WithExitFunc(func(code int) { exitCode = code }))
+			MustParseAndLoad(&p, opts...)
+			assert.Equal(t, tt.want, p)
+			assert.Equal(t, tt.wantExitCode, exitCode)
+			assert.Equal(t, tt.wantOutput, buf.String())
+		})
+	}
+}
+
+func TestMustParseAndLoad_exitCodes(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config.json")
+	assert.NoError(t, os.WriteFile(config, []byte(`{"port": "eighty"}`), 0o600))
+
+	type exitParams struct {
+		Port int `flag:"port|Server port|80"`
+	}
+	tests := []struct {
+		name         string
+		args         []string
+		wantExitCode int
+	}{
+		{name: "unknown flag", args: []string{"-prot=80"}, wantExitCode: 64},
+		{name: "invalid value", args: []string{"-port=eighty"}, wantExitCode: 64},
+		{name: "invalid configuration file", args: []string{"-config", config}, wantExitCode: 78},
+		{name: "missing configuration file", args: []string{"-config", filepath.Join(dir, "missing.json")}, wantExitCode: 78},
+		{name: "other error", args: []string{"-port=8080"}, wantExitCode: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exitCode := -1
+			var p exitParams
+			MustParseAndLoad(&p,
+				WithArgsSource(StaticArgs(append([]string{"prog"}, tt.args...))),
+				WithConfigFlag("config"),
+				WithPostProcessors(func(interface{}) error { return errors.New("failure") }),
+				WithExitCode(ErrUsage, 64),
+				WithExitCode(ErrConfigFile, 78),
+				WithErrorExitCode(1),
+				WithOutput(io.Discard),
+				WithExitFunc(func(code int) { exitCode = code }),
+			)
+			assert.Equal(t, tt.wantExitCode, exitCode)
+		})
+	}
+}
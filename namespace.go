@@ -0,0 +1,91 @@
+package easyflag
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const namespaceSeparator = "."
+
+type namespace struct {
+	name   string
+	params interface{}
+	merged bool // the flags are merged with the flags of the params structure without any prefix, see WithParams
+}
+
+/*
+WithNamespace adds the flags of another params structure prefixed by the namespace name and a dot, e.g. the field
+tagged by `flag:"timeout"` becomes the -cache.timeout flag in the cache namespace. The params must be a pointer
+to a structure just like in the case of the ParseAndLoad function, and it is filled by the flag values as well.
+
+It is meant for the flags contributed by the plugins registered at runtime (e.g. by the build tags or Go plugins),
+which do not know about the params structure of the program:
+
+	var opts []easyflag.Option
+	for _, p := range plugins {
+		opts = append(opts, easyflag.WithNamespace(p.Name(), p.Params()))
+	}
+	err := easyflag.ParseAndLoad(&params, opts...)
+
+The flags of a namespace are listed in a separate section of the usage message named by the namespace,
+and the Validate and Extend methods of the namespace structures are called as well.
+The post-processors receive only the params structure passed to the ParseAndLoad function.
+*/
+func WithNamespace(name string, params interface{}) Option {
+	return func(o *options) {
+		o.namespaces = append(o.namespaces, namespace{name: name, params: params})
+	}
+}
+
+/*
+WithParams adds the flags of other params structures to the flags of the params structure passed to the ParseAndLoad
+function, so that the configuration can be composed from the structures defined by several packages
+without wrapping them in a single structure:
+
+	err := easyflag.ParseAndLoad(&httpParams, easyflag.WithParams(&dbParams, &logParams))
+
+Every params must be a pointer to a structure just like in the case of the ParseAndLoad function, and it is filled
+by the flag values as well. All the flags share a single flag set, so a flag name defined by more than one structure
+is reported as an error. The Validate and Extend methods of the structures are called in the order in which
+the structures are passed. The post-processors receive only the params structure passed to the ParseAndLoad function.
+*/
+func WithParams(params ...interface{}) Option {
+	return func(o *options) {
+		for _, p := range params {
+			o.namespaces = append(o.namespaces, namespace{params: p, merged: true})
+		}
+	}
+}
+
+// setUpNamespaces sets up the flags of the structures passed by the WithNamespace and WithParams options. If detached is true,
+// the flags are set up on new instances of the namespace structures, so that the passed structures are not modified.
+func (fb *flagBuilder) setUpNamespaces(detached bool) error {
+	for _, ns := range fb.opts.namespaces {
+		if err := checkParams(ns.params); err != nil {
+			if ns.merged {
+				return err
+			}
+			return fmt.Errorf("invalid params of the namespace %q: %w", ns.name, err)
+		}
+		params := ns.params
+		if detached {
+			params = reflect.New(reflect.TypeOf(params).Elem()).Interface()
+		}
+		if ns.merged {
+			// the field paths of the merged structures start with their type names, e.g. dbParams.Host
+			fb.fieldIndex, fb.fieldPath = nil, reflect.TypeOf(params).Elem().Name()
+		} else {
+			if reason := checkFlagName(ns.name); reason != "" {
+				return fmt.Errorf("invalid namespace %q: %s", ns.name, reason)
+			}
+			fb.namespace, fb.group, fb.fieldIndex, fb.fieldPath = ns.name, ns.name, nil, ns.name
+		}
+		fb.external = true
+		err := fb.setUpFlags(params)
+		fb.namespace, fb.group, fb.fieldIndex, fb.fieldPath, fb.external = "", "", nil, "", false
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
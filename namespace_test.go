@@ -0,0 +1,125 @@
+package easyflag
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cachePluginParams struct {
+	Size int    `flag:"size|Cache size|100"`
+	Dir  string `flag:"dir|Cache directory||required"`
+}
+
+func (p cachePluginParams) Validate() error {
+	if p.Size <= 0 {
+		return errors.New("cache size must be positive")
+	}
+	return nil
+}
+
+func TestParseAndLoad_namespace(t *testing.T) {
+	type mainParams struct {
+		Size int `flag:"size|Main size|1"`
+	}
+	tests := []struct {
+		name       string
+		args       []string
+		namespace  string
+		wantMain   mainParams
+		wantPlugin cachePluginParams
+		wantErr    string
+	}{
+		{
+			name:       "namespaced flags",
+			args:       []string{"-size=2", "-cache.dir=/tmp", "-cache.size=10"},
+			namespace:  "cache",
+			wantMain:   mainParams{Size: 2},
+			wantPlugin: cachePluginParams{Size: 10, Dir: "/tmp"},
+		},
+		{
+			name:      "required namespaced flag",
+			args:      []string{"-size=2"},
+			namespace: "cache",
+			wantErr:   `missing required flag "cache.dir" or its value`,
+		},
+		{
+			name:      "namespace validation",
+			args:      []string{"-cache.dir=/tmp", "-cache.size=0"},
+			namespace: "cache",
+			wantErr:   "validation failed: cache size must be positive",
+		},
+		{
+			name:      "invalid namespace",
+			namespace: "-cache",
+			wantErr:   `invalid namespace "-cache": flag name "-cache" starts with a hyphen`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args = append([]string{"executable_name"}, tt.args...)
+			var p mainParams
+			var plugin cachePluginParams
+			err := ParseAndLoad(&p, WithNamespace(tt.namespace, &plugin), WithOutput(io.Discard))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMain, p)
+			assert.Equal(t, tt.wantPlugin, plugin)
+		})
+	}
+}
+
+func TestParseAndLoad_namespaceUsage(t *testing.T) {
+	os.Args = []string{"executable_name", "-h"}
+	var buf bytes.Buffer
+	var p struct {
+		Verbose bool `flag:"v|Verbose output"`
+	}
+	var plugin cachePluginParams
+	err := ParseAndLoad(&p, WithNamespace("cache", &plugin), WithOutput(&buf), WithoutExit())
+	assert.Equal(t, flag.ErrHelp, err)
+	want := `Usage:
+  -v	Verbose output
+
+cache:
+  -cache.dir string
+    	Cache directory (required)
+  -cache.size int
+    	Cache size (default 100)
+`
+	assert.Equal(t, want, buf.String())
+}
+
+func TestParseAndLoad_withParams(t *testing.T) {
+	type httpParams struct {
+		Port int `flag:"port|HTTP port|80"`
+	}
+	type dbParams struct {
+		Host string `flag:"db-host|Database host||required"`
+	}
+	type logParams struct {
+		Port int `flag:"port|Syslog port"`
+	}
+	os.Args = []string{"executable_name", "-port=8080", "-db-host=db"}
+	var hp httpParams
+	var dp dbParams
+	err := ParseAndLoad(&hp, WithParams(&dp), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, httpParams{Port: 8080}, hp)
+	assert.Equal(t, dbParams{Host: "db"}, dp)
+
+	var lp logParams
+	err = ParseAndLoad(&hp, WithParams(&dp, &lp), WithOutput(io.Discard))
+	assert.EqualError(t, err, "invalid field logParams.Port: flag -port already defined by the field Port")
+
+	err = ParseAndLoad(&hp, WithParams(dp), WithOutput(io.Discard))
+	assert.EqualError(t, err, "flags parse: got non-pointer easyflag.dbParams")
+}
@@ -0,0 +1,86 @@
+package easyflag
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// autoNameValue is the flag tag name part replaced by the name derived from the field name, e.g. `flag:",auto"`.
+const autoNameValue = ",auto"
+
+// cutAutoName cuts the ,auto name from the beginning of the flag tag and returns the rest of the tag.
+// The ,auto name must be followed by the end of the tag, a comma or a pipe, otherwise, e.g. for ",automatic",
+// the reason of the tag syntax error is returned.
+func cutAutoName(tag string) (rest string, autoName bool, reason string) {
+	rest, ok := cutPrefix(tag, autoNameValue)
+	if !ok {
+		return tag, false, ""
+	}
+	if rest != "" && rest[0] != ',' && rest[0] != '|' {
+		return tag, false, fmt.Sprintf("the %s name must be followed by a comma, a pipe or the end of the tag", autoNameValue)
+	}
+	return rest, true, ""
+}
+
+// splitFieldName splits the Go field name into the words, e.g. MaxRetryCount into Max, Retry and Count.
+// The acronyms are kept together, e.g. HTTPPort is split into HTTP and Port.
+func splitFieldName(name string) []string {
+	runes := []rune(name)
+	var (
+		words []string
+		start int
+	)
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		var next rune
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		switch {
+		case cur == '_':
+			words = append(words, string(runes[start:i]))
+			start = i + 1
+		case unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			words = append(words, string(runes[start:i]))
+			start = i
+		case unicode.IsUpper(cur) && unicode.IsUpper(prev) && unicode.IsLower(next):
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	words = append(words, string(runes[start:]))
+	result := words[:0]
+	for _, w := range words {
+		if w != "" {
+			result = append(result, w)
+		}
+	}
+	return result
+}
+
+// NamingStrategy derives the flag name from the Go field name. See the WithNamingStrategy option.
+type NamingStrategy func(fieldName string) string
+
+// KebabCase is the NamingStrategy deriving the names like max-retry-count from MaxRetryCount. It is the default.
+func KebabCase(fieldName string) string {
+	return strings.ToLower(strings.Join(splitFieldName(fieldName), "-"))
+}
+
+// SnakeCase is the NamingStrategy deriving the names like max_retry_count from MaxRetryCount.
+func SnakeCase(fieldName string) string {
+	return strings.ToLower(strings.Join(splitFieldName(fieldName), "_"))
+}
+
+// CamelCase is the NamingStrategy deriving the names like maxRetryCount from MaxRetryCount.
+func CamelCase(fieldName string) string {
+	words := splitFieldName(fieldName)
+	for i, w := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(w)
+			continue
+		}
+		words[i] = strings.ToUpper(w[:1]) + strings.ToLower(w[1:])
+	}
+	return strings.Join(words, "")
+}
@@ -0,0 +1,32 @@
+package easyflag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamingStrategy(t *testing.T) {
+	tests := []struct {
+		fieldName string
+		wantKebab string
+		wantSnake string
+		wantCamel string
+	}{
+		{fieldName: "Port", wantKebab: "port", wantSnake: "port", wantCamel: "port"},
+		{fieldName: "MaxRetryCount", wantKebab: "max-retry-count", wantSnake: "max_retry_count", wantCamel: "maxRetryCount"},
+		{fieldName: "HTTPPort", wantKebab: "http-port", wantSnake: "http_port", wantCamel: "httpPort"},
+		{fieldName: "ID", wantKebab: "id", wantSnake: "id", wantCamel: "id"},
+		{fieldName: "UserID", wantKebab: "user-id", wantSnake: "user_id", wantCamel: "userId"},
+		{fieldName: "Port2", wantKebab: "port2", wantSnake: "port2", wantCamel: "port2"},
+		{fieldName: "TLS2Cert", wantKebab: "tls2-cert", wantSnake: "tls2_cert", wantCamel: "tls2Cert"},
+		{fieldName: "Max_Retry", wantKebab: "max-retry", wantSnake: "max_retry", wantCamel: "maxRetry"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fieldName, func(t *testing.T) {
+			assert.Equal(t, tt.wantKebab, KebabCase(tt.fieldName))
+			assert.Equal(t, tt.wantSnake, SnakeCase(tt.fieldName))
+			assert.Equal(t, tt.wantCamel, CamelCase(tt.fieldName))
+		})
+	}
+}
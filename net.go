@@ -0,0 +1,114 @@
+package easyflag
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+)
+
+var (
+	errInvalidIP     = errors.New("invalid IP address")
+	errInvalidPrefix = errors.New("invalid CIDR prefix")
+)
+
+// ipValue is the flag value of the net.IP fields.
+type ipValue struct {
+	ip *net.IP
+}
+
+func (v *ipValue) String() string {
+	if v.ip == nil || *v.ip == nil {
+		return ""
+	}
+	return v.ip.String()
+}
+
+func (v *ipValue) Set(s string) error {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return errInvalidIP
+	}
+	*v.ip = ip
+	return nil
+}
+
+func (v *ipValue) valueName() string {
+	return "ip"
+}
+
+// ipNetValue is the flag value of the net.IPNet fields. The address is masked by the prefix length,
+// e.g. 10.1.2.3/8 is stored as 10.0.0.0/8 just like by the net.ParseCIDR function.
+type ipNetValue struct {
+	ipNet *net.IPNet
+}
+
+func (v *ipNetValue) String() string {
+	if v.ipNet == nil || v.ipNet.IP == nil {
+		return ""
+	}
+	return v.ipNet.String()
+}
+
+func (v *ipNetValue) Set(s string) error {
+	_, ipNet, err := net.ParseCIDR(s)
+	if err != nil {
+		return errInvalidPrefix
+	}
+	*v.ipNet = *ipNet
+	return nil
+}
+
+func (v *ipNetValue) valueName() string {
+	return "cidr"
+}
+
+// addrValue is the flag value of the netip.Addr fields.
+type addrValue struct {
+	addr *netip.Addr
+}
+
+func (v *addrValue) String() string {
+	if v.addr == nil || !v.addr.IsValid() {
+		return ""
+	}
+	return v.addr.String()
+}
+
+func (v *addrValue) Set(s string) error {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return errInvalidIP
+	}
+	*v.addr = addr
+	return nil
+}
+
+func (v *addrValue) valueName() string {
+	return "ip"
+}
+
+// prefixValue is the flag value of the netip.Prefix fields. Unlike the net.IPNet fields, the address is kept
+// as it is, see the netip.Prefix.Masked method.
+type prefixValue struct {
+	prefix *netip.Prefix
+}
+
+func (v *prefixValue) String() string {
+	if v.prefix == nil || !v.prefix.IsValid() {
+		return ""
+	}
+	return v.prefix.String()
+}
+
+func (v *prefixValue) Set(s string) error {
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return errInvalidPrefix
+	}
+	*v.prefix = prefix
+	return nil
+}
+
+func (v *prefixValue) valueName() string {
+	return "cidr"
+}
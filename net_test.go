@@ -0,0 +1,85 @@
+package easyflag
+
+import (
+	"bytes"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type netParams struct {
+	Listen  net.IP       `flag:"listen|Listen address|127.0.0.1"`
+	Subnet  net.IPNet    `flag:"subnet|Allowed subnet"`
+	Gateway netip.Addr   `flag:"gateway|Gateway address||required"`
+	Range   netip.Prefix `flag:"range|Address range|fd00::/8"`
+}
+
+func TestParseAndLoad_net(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    netParams
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			args: []string{"program", "-gateway=10.0.0.1"},
+			want: netParams{
+				Listen:  net.ParseIP("127.0.0.1"),
+				Gateway: netip.MustParseAddr("10.0.0.1"),
+				Range:   netip.MustParsePrefix("fd00::/8"),
+			},
+		},
+		{
+			name: "set",
+			args: []string{"program", "-listen=::1", "-subnet=10.1.2.3/8", "-gateway=fe80::1", "-range=192.168.1.7/24"},
+			want: netParams{
+				Listen:  net.ParseIP("::1"),
+				Subnet:  net.IPNet{IP: net.IP{10, 0, 0, 0}, Mask: net.CIDRMask(8, 32)},
+				Gateway: netip.MustParseAddr("fe80::1"),
+				Range:   netip.MustParsePrefix("192.168.1.7/24"),
+			},
+		},
+		{
+			name:    "invalid IP",
+			args:    []string{"program", "-gateway=10.0.0.1", "-listen=localhost"},
+			wantErr: `invalid value "localhost" for flag -listen: invalid IP address`,
+		},
+		{
+			name:    "invalid prefix",
+			args:    []string{"program", "-gateway=10.0.0.1", "-subnet=10.0.0.0"},
+			wantErr: `invalid value "10.0.0.0" for flag -subnet: invalid CIDR prefix`,
+		},
+		{
+			name:    "missing required",
+			args:    []string{"program"},
+			wantErr: `missing required flag "gateway" or its value`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p netParams
+			var buf bytes.Buffer
+			err := ParseAndLoad(&p, WithArgsSource(StaticArgs(tt.args)), WithOutput(&buf))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
+
+func TestWriteUsage_net(t *testing.T) {
+	var p netParams
+	var buf bytes.Buffer
+	err := ParseAndLoad(&p, WithArgsSource(StaticArgs{"program", "-h"}), WithOutput(&buf), WithColor(ColorNever),
+		WithoutExit())
+	assert.Error(t, err)
+	assert.Contains(t, buf.String(), "  -listen ip\n    \tListen address (default 127.0.0.1)\n")
+	assert.Contains(t, buf.String(), "  -subnet cidr\n    \tAllowed subnet\n")
+	assert.Contains(t, buf.String(), "  -range cidr\n    \tAddress range (default fd00::/8)\n")
+}
@@ -0,0 +1,109 @@
+package easyflag
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var errNotInteger = errors.New("value is not an integer")
+
+// parseInt parses a signed integer of the given bit size. Besides the Go integer literals (including the digit
+// separators like 1_000_000 and the 0x, 0o and 0b prefixes) it accepts the scientific notation (e.g. 1e6)
+// as long as the value is an exact integer. The leading zeros are decimal, e.g. 010 is 10, not the legacy octal 8.
+func parseInt(s string, bitSize int) (int64, error) {
+	i, err := strconv.ParseInt(trimLeadingZeros(s), 0, bitSize)
+	if !errors.Is(err, strconv.ErrSyntax) {
+		return i, err
+	}
+	n, numErr := parseExactInteger(s)
+	if numErr != nil {
+		return 0, numErr
+	}
+	if n == nil {
+		return 0, err
+	}
+	if !n.IsInt64() {
+		return 0, errRange
+	}
+	i = n.Int64()
+	if minVal, maxVal := -int64(1)<<(bitSize-1), int64(1)<<(bitSize-1)-1; i < minVal || i > maxVal {
+		return 0, errRange
+	}
+	return i, nil
+}
+
+// parseUint is an unsigned variant of the parseInt function.
+func parseUint(s string, bitSize int) (uint64, error) {
+	u, err := strconv.ParseUint(trimLeadingZeros(s), 0, bitSize)
+	if !errors.Is(err, strconv.ErrSyntax) {
+		return u, err
+	}
+	n, numErr := parseExactInteger(s)
+	if numErr != nil {
+		return 0, numErr
+	}
+	if n == nil {
+		return 0, err
+	}
+	if n.Sign() < 0 || !n.IsUint64() {
+		return 0, errRange
+	}
+	u = n.Uint64()
+	if bitSize < 64 && u > uint64(1)<<bitSize-1 {
+		return 0, errRange
+	}
+	return u, nil
+}
+
+// trimLeadingZeros removes the leading zeros of a decimal integer, so that the strconv package does not take
+// them as the legacy octal prefix. The explicit 0x, 0o and 0b prefixes are kept.
+func trimLeadingZeros(s string) string {
+	sign := ""
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		sign, s = s[:1], s[1:]
+	}
+	if len(s) < 2 || s[0] != '0' {
+		return sign + s
+	}
+	switch s[1] {
+	case 'x', 'X', 'o', 'O', 'b', 'B':
+		return sign + s
+	}
+	trimmed := strings.TrimLeft(s, "0")
+	switch {
+	case trimmed == "":
+		return sign + "0"
+	case trimmed[0] >= '0' && trimmed[0] <= '9' || trimmed[0] == '_':
+		// a leading separator is a syntax error, the value is then parsed by the decimal fallback
+		return sign + trimmed
+	}
+	return sign + "0" + trimmed // e.g. 0e3
+}
+
+// parseExactInteger parses a number in the decimal or scientific notation which must be an exact integer.
+// It returns nil without an error if the string is not a number at all.
+func parseExactInteger(s string) (*big.Int, error) {
+	s = strings.ReplaceAll(s, "_", "")
+	// the float parsing protects the big.Rat parsing from the huge exponents
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return nil, errRange
+		}
+		return nil, nil
+	}
+	if math.Abs(f) > math.MaxUint64 {
+		return nil, errRange
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, nil
+	}
+	if !r.IsInt() {
+		return nil, errNotInteger
+	}
+	return r.Num(), nil
+}
@@ -0,0 +1,89 @@
+package easyflag
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInt(t *testing.T) {
+	tests := []struct {
+		in      string
+		bitSize int
+		want    int64
+		wantErr error
+	}{
+		{in: "123", bitSize: 64, want: 123},
+		{in: "-123", bitSize: 64, want: -123},
+		{in: "1_000_000", bitSize: 64, want: 1_000_000},
+		{in: "0x10", bitSize: 64, want: 16},
+		{in: "0o10", bitSize: 64, want: 8},
+		{in: "0b10", bitSize: 64, want: 2},
+		{in: "010", bitSize: 64, want: 10},
+		{in: "0080", bitSize: 64, want: 80},
+		{in: "007", bitSize: 64, want: 7},
+		{in: "-010", bitSize: 64, want: -10},
+		{in: "0_100", bitSize: 64, want: 100},
+		{in: "000", bitSize: 64, want: 0},
+		{in: "0", bitSize: 64, want: 0},
+		{in: "0e3", bitSize: 64, want: 0},
+		{in: "1e6", bitSize: 64, want: 1_000_000},
+		{in: "-2.5E3", bitSize: 64, want: -2500},
+		{in: "1_000e3", bitSize: 64, want: 1_000_000},
+		{in: "1.5", bitSize: 64, wantErr: errNotInteger},
+		{in: "1e-3", bitSize: 64, wantErr: errNotInteger},
+		{in: "1e19", bitSize: 64, wantErr: errRange},
+		{in: "1e400", bitSize: 64, wantErr: errRange},
+		{in: "3e4", bitSize: 8, wantErr: errRange},
+		{in: "abc", bitSize: 64, wantErr: errParse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseInt(tt.in, tt.bitSize)
+			assert.Equal(t, tt.wantErr, numError(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseUint(t *testing.T) {
+	tests := []struct {
+		in      string
+		bitSize int
+		want    uint64
+		wantErr error
+	}{
+		{in: "123", bitSize: 64, want: 123},
+		{in: "1_000_000", bitSize: 64, want: 1_000_000},
+		{in: "1e19", bitSize: 64, want: 10_000_000_000_000_000_000},
+		{in: "010", bitSize: 64, want: 10},
+		{in: "0x10", bitSize: 64, want: 16},
+		{in: "-1e3", bitSize: 64, wantErr: errRange},
+		{in: "1e10", bitSize: 32, wantErr: errRange},
+		{in: "2.5", bitSize: 64, wantErr: errNotInteger},
+		{in: "-1", bitSize: 64, wantErr: errRange},
+		{in: "abc", bitSize: 64, wantErr: errParse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseUint(tt.in, tt.bitSize)
+			assert.Equal(t, tt.wantErr, numError(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseAndLoad_leadingZeros(t *testing.T) {
+	var p struct {
+		Mode  int  `flag:"mode|File mode|010"`
+		Count uint `flag:"count|Count"`
+		Mask  int  `flag:"mask|Mask"`
+	}
+	os.Args = []string{"executable_name", "-count=0080", "-mask=0o17"}
+	err := ParseAndLoad(&p)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, p.Mode)
+	assert.Equal(t, uint(80), p.Count)
+	assert.Equal(t, 15, p.Mask)
+}
@@ -0,0 +1,391 @@
+package easyflag
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Option is a functional option modifying the behavior of the ParseAndLoad function.
+type Option func(*options)
+
+type options struct {
+	version      string
+	whitespace   WhitespacePolicy
+	progName     string
+	boolSynonyms bool
+
+	reservedFlags   []ReservedFlag
+	exitFn          func(code int)
+	preprocessors   []ArgsPreprocessor
+	postProcessors  []PostProcessor
+	description     string
+	examples        []string
+	output          io.Writer
+	usedFlagsHook   func(names []string)
+	flagOrder       FlagOrder
+	argsSource      ArgsSource
+	envSource       EnvSource
+	lintOutput      io.Writer
+	color           ColorMode
+	messages        Messages
+	envFlag         string
+	envPrefix       string
+	autoNames       bool
+	naming          NamingStrategy
+	prepopulated    bool
+	generatorFlags  bool
+	errorExitCode   int
+	exitCodes       []exitCode // the status codes of the error categories, see WithExitCode
+	errorUsage      ErrorUsageMode
+	errorOutput     io.Writer
+	namespaces      []namespace
+	bootstrap       bool // only the flags of the params structure are parsed, see the ParseBootstrap function
+	backend         Backend
+	printConfigFlag bool
+	configFlag      string // the name of the flag setting the path of the configuration file
+	strictConfig    bool   // the unknown keys of the configuration file are reported as errors
+	dotEnvPath      string
+	secretProvider  SecretProvider
+	ctx             context.Context // the context of the secret provider, remote source and completion lookups, see WithContext
+	remoteSources   []RemoteSource
+	prompt          PromptFunc
+	responseFiles   bool // the @file arguments are replaced by the arguments read from the files
+	windowsSyntax   bool // the /name value and /name:value arguments are accepted, see WithWindowsSyntax
+	shortGrouping   bool // the groups of the single-character flags are expanded, see WithShortFlagGrouping
+	repeatedPolicy  RepeatedFlagPolicy
+	unknownFlags    bool // the unknown flags are returned instead of failing, see WithUnknownFlags
+
+	completionProviders map[string]CompletionProvider
+	defaultFuncs        map[string]func() string // the functions computing the default values, see WithDefaultFunc
+	reload              bool                     // the parsing is a reload of a Reloadable, see Reloadable.Reload
+	reloadPrompted      map[string]string        // the values of the prompted flags kept by the reload of a Reloadable
+}
+
+func (o options) exit(code int) {
+	if o.exitFn != nil {
+		o.exitFn(code)
+		return
+	}
+	os.Exit(code)
+}
+
+func (o options) programName() string {
+	if o.progName != "" {
+		return o.progName
+	}
+	if args := o.args(); len(args) > 0 {
+		return filepath.Base(args[0])
+	}
+	if exe, err := os.Executable(); err == nil {
+		return filepath.Base(exe)
+	}
+	return ""
+}
+
+func (o options) args() []string {
+	if o.argsSource != nil {
+		return o.argsSource.Args()
+	}
+	return osArgs{}.Args()
+}
+
+func (o options) lookupEnv(key string) (string, bool) {
+	if o.envSource != nil {
+		return o.envSource.LookupEnv(key)
+	}
+	return osEnv{}.LookupEnv(key)
+}
+
+func (o options) namingStrategy() NamingStrategy {
+	if o.naming != nil {
+		return o.naming
+	}
+	return KebabCase
+}
+
+func newOptions(opts []Option) options {
+	o := options{errorExitCode: defaultErrorExitCode}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.messages = o.messages.withDefaults()
+	return o
+}
+
+// WithVersion enables the built-in -version and -V flags printing the passed version information.
+// It takes precedence over the version provided by the params structure implementing the Versioner interface.
+func WithVersion(version string) Option {
+	return func(o *options) {
+		o.version = version
+	}
+}
+
+// WithWhitespacePolicy sets the policy of handling the leading and trailing whitespace of the flag values passed
+// on the command line. It can be overridden for a single flag by the keepspace, trim or rejectspace tag options.
+// The policy applies to all the built-in field types except for the Set which always trims its values.
+func WithWhitespacePolicy(policy WhitespacePolicy) Option {
+	return func(o *options) {
+		o.whitespace = policy
+	}
+}
+
+// WithProgramName sets the program name used in the generated documentation and completion scripts.
+// By default, the base name of the first program argument (or os.Executable if there are no arguments) is used.
+func WithProgramName(name string) Option {
+	return func(o *options) {
+		o.progName = name
+	}
+}
+
+// WithBoolSynonyms makes the boolean flags accept the yes/no and on/off values (case-insensitively)
+// besides the values accepted by the native flag package (1, 0, t, f, true, false, ...).
+func WithBoolSynonyms() Option {
+	return func(o *options) {
+		o.boolSynonyms = true
+	}
+}
+
+// WithExitFunc replaces the os.Exit function called by the package when the program should terminate,
+// e.g. after the help is printed. If the passed function returns, the ParseAndLoad function returns an error
+// describing the reason of the termination (e.g. flag.ErrHelp) instead.
+// This allows the embedding applications, tests and TUIs to intercept the termination.
+func WithExitFunc(exitFn func(code int)) Option {
+	return func(o *options) {
+		o.exitFn = exitFn
+	}
+}
+
+// WithoutExit is a shorthand for WithExitFunc(func(int) {}), it adds no behavior of its own. It makes the ParseAndLoad
+// function return instead of terminating the program, e.g. after the help is printed, the flag.ErrHelp error
+// is returned. This allows testing the CLI wiring end-to-end, and it prevents the help requests handled inside
+// the long-running processes from killing the whole process.
+func WithoutExit() Option {
+	return WithExitFunc(func(int) {})
+}
+
+const defaultErrorExitCode = 2
+
+// WithErrorExitCode sets the status code with which the MustParseAndLoad function terminates the program
+// if the parsing fails. The default status code is 2, the same as in the native flag package.
+func WithErrorExitCode(code int) Option {
+	return func(o *options) {
+		o.errorExitCode = code
+	}
+}
+
+// exitCode is the status code of the errors matching the target error.
+type exitCode struct {
+	target error
+	code   int
+}
+
+/*
+WithExitCode sets the status code with which the MustParseAndLoad function terminates the program if the parsing fails
+with an error matching the target error by the errors.Is function. It can be used repeatedly to distinguish the error
+categories, e.g.
+
+	easyflag.MustParseAndLoad(&p,
+		easyflag.WithExitCode(easyflag.ErrUsage, 2),
+		easyflag.WithExitCode(easyflag.ErrConfigFile, 3),
+		easyflag.WithErrorExitCode(1),
+	)
+
+The first matching target wins. The errors matching none of the targets terminate the program with the status code
+set by the WithErrorExitCode option.
+*/
+func WithExitCode(target error, code int) Option {
+	return func(o *options) {
+		o.exitCodes = append(o.exitCodes, exitCode{target: target, code: code})
+	}
+}
+
+// errorExitCodeOf returns the status code with which the program terminates if the parsing fails with the error.
+func (o options) errorExitCodeOf(err error) int {
+	for _, ec := range o.exitCodes {
+		if errors.Is(err, ec.target) {
+			return ec.code
+		}
+	}
+	return o.errorExitCode
+}
+
+// ArgsPreprocessor is a transformation of the raw CLI arguments applied before the flags are parsed,
+// e.g. an alias expansion, a legacy syntax rewriting or a removal of the arguments injected by a wrapper.
+// The arguments passed do not contain the command name.
+type ArgsPreprocessor func(args []string) ([]string, error)
+
+// WithArgsPreprocessors adds the argument pre-processors. The pre-processors are applied in the order
+// in which they are passed, each of them receiving the output of the previous one.
+func WithArgsPreprocessors(preprocessors ...ArgsPreprocessor) Option {
+	return func(o *options) {
+		o.preprocessors = append(o.preprocessors, preprocessors...)
+	}
+}
+
+// PostProcessor is a transformation of the whole params structure applied after the flag values are validated.
+// It receives the pointer passed to the ParseAndLoad function, so it can implement the application-wide policies
+// (e.g. clamping all the timeouts) once, independently of the Extend methods of the particular structures.
+type PostProcessor func(params interface{}) error
+
+// WithPostProcessors adds the post-processors. The post-processors are applied in the order in which they are passed,
+// after all the Validate methods and before any Extend method is called.
+func WithPostProcessors(postProcessors ...PostProcessor) Option {
+	return func(o *options) {
+		o.postProcessors = append(o.postProcessors, postProcessors...)
+	}
+}
+
+// WithDescription sets the short program description shown in the usage message above the list of the flags,
+// as well as in the generated documentation. It takes precedence over the description provided by the params
+// structure implementing the Describer interface.
+func WithDescription(description string) Option {
+	return func(o *options) {
+		o.description = description
+	}
+}
+
+// WithExamples sets the usage examples shown in the usage message above the list of the flags,
+// as well as in the generated documentation. It takes precedence over the examples provided by the params
+// structure implementing the Describer interface.
+func WithExamples(examples ...string) Option {
+	return func(o *options) {
+		o.examples = examples
+	}
+}
+
+// WithOutput sets the writer to which the usage message, the parsing errors and the warnings are written.
+// By default, they are written to the standard error output.
+func WithOutput(w io.Writer) Option {
+	return func(o *options) {
+		o.output = w
+	}
+}
+
+// WithUsedFlagsHook sets a hook reporting the names of the flags explicitly used on the command line.
+// The hook is called once the flags are parsed successfully, and it receives only the flag names in the lexical order,
+// never their values. It can be used e.g. for collecting the telemetry of the flag usage.
+func WithUsedFlagsHook(hook func(names []string)) Option {
+	return func(o *options) {
+		o.usedFlagsHook = hook
+	}
+}
+
+// FlagOrder is the order of the flags in the usage message.
+type FlagOrder int
+
+const (
+	// AlphabeticalOrder lists the flags sorted by their names just like the native flag package. It is the default.
+	AlphabeticalOrder FlagOrder = iota
+	// DefinitionOrder lists the flags in the order of the params structure fields.
+	DefinitionOrder
+)
+
+// WithFlagOrder sets the order of the flags in the usage message. In both orders, the flags with a higher priority
+// set by the priority tag option are listed first and the flags of the nested structures stay in their own sections.
+func WithFlagOrder(order FlagOrder) Option {
+	return func(o *options) {
+		o.flagOrder = order
+	}
+}
+
+// WithArgsSource sets the source of the command line arguments parsed instead of os.Args.
+// It is useful in the runtimes without the usual process arguments, e.g. in a browser.
+func WithArgsSource(src ArgsSource) Option {
+	return func(o *options) {
+		o.argsSource = src
+	}
+}
+
+// WithEnvSource sets the source of the environment variables used instead of the process environment.
+func WithEnvSource(src EnvSource) Option {
+	return func(o *options) {
+		o.envSource = src
+	}
+}
+
+// WithLintWarnings enables the linting of the flag definitions. The warnings about the style issues
+// (e.g. a missing usage text, a default value equal to the zero value or a default value of a required flag)
+// are written to w while the flags are set up. They are only informative and never cause the parsing to fail.
+func WithLintWarnings(w io.Writer) Option {
+	return func(o *options) {
+		o.lintOutput = w
+	}
+}
+
+// WithColor sets whether the flag names, required markers and default values in the usage message are styled
+// by the ANSI escape sequences. By default (ColorAuto), the styles are used only if the usage message is written
+// to a terminal and the NO_COLOR environment variable is not set.
+func WithColor(mode ColorMode) Option {
+	return func(o *options) {
+		o.color = mode
+	}
+}
+
+// WithEnvironmentFlag sets the flag whose value is the current environment (e.g. dev or prod) of the program.
+// The flags with the required_env field tag listing the current environment, e.g. `required_env:"prod,staging"`,
+// are required, while they are optional in the other environments. The environment flag itself can be set
+// by an environment variable using the env field tag.
+func WithEnvironmentFlag(name string) Option {
+	return func(o *options) {
+		o.envFlag = name
+	}
+}
+
+// WithEnvPrefix sets the prefix of the environment variables named by the env field tags,
+// e.g. the `env:"PORT"` tag with the "APP_" prefix names the APP_PORT variable.
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) {
+		o.envPrefix = prefix
+	}
+}
+
+// WithAutoNames makes the exported fields without the flag tag flags as well. Their names are derived from the field
+// names, e.g. the MaxRetryCount field becomes the -max-retry-count flag. The fields tagged by `flag:"-"` are ignored.
+// A single field can use the derived name by the `flag:",auto"` tag even without this option.
+func WithAutoNames() Option {
+	return func(o *options) {
+		o.autoNames = true
+	}
+}
+
+// WithNamingStrategy sets the strategy deriving the flag names from the field names (see the WithAutoNames option),
+// e.g. KebabCase (the default), SnakeCase, CamelCase or a custom function.
+func WithNamingStrategy(strategy NamingStrategy) Option {
+	return func(o *options) {
+		o.naming = strategy
+	}
+}
+
+// WithPrepopulatedDefaults makes the non-zero values of the params structure fields set by the caller the default values
+// of their flags instead of the default values from the tags. This enables layering, e.g. the structure can be loaded
+// from a configuration file first and then the flags used on the command line override it.
+// If the parsing fails, the structure is restored to the pre-populated values instead of being zeroed.
+func WithPrepopulatedDefaults() Option {
+	return func(o *options) {
+		o.prepopulated = true
+	}
+}
+
+// Options composes several options into a single one, so that a standard set of the options can be shared
+// across the programs. The options are applied in the passed order, so the later ones override the earlier ones.
+func Options(opts ...Option) Option {
+	return func(o *options) {
+		for _, opt := range opts {
+			opt(o)
+		}
+	}
+}
+
+// ServerDefaults is the bundle of the options suitable for the long-running server programs configured
+// by the deployment systems rather than by hand. It rejects the values with the leading or trailing whitespace
+// and disables the colors in the usage message. The bundle can be combined with other options,
+// e.g. ParseAndLoad(&p, ServerDefaults(), WithEnvPrefix("APP_")).
+func ServerDefaults() Option {
+	return Options(
+		WithWhitespacePolicy(RejectWhitespace),
+		WithColor(ColorNever),
+	)
+}
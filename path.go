@@ -0,0 +1,37 @@
+package easyflag
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// expandPath expands a leading ~ of the path to the home directory of the current user and the $VAR and ${VAR}
+// references to the values of the environment variables looked up by the lookupEnv function. The undefined variables
+// are replaced by empty strings. The ~user form is not supported and it is kept as it is.
+func expandPath(path string, lookupEnv func(string) (string, bool)) (string, error) {
+	mapping := func(key string) string {
+		val, _ := lookupEnv(key)
+		return val
+	}
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return os.Expand(path, mapping), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return home + os.Expand(path[1:], mapping), nil
+}
+
+// expandParseFn returns the parse function of the string fields with the expand flag option expanding the values
+// before they are stored.
+func (fb *flagBuilder) expandParseFn(parseFn func(string) (string, error)) func(string) (string, error) {
+	return func(s string) (string, error) {
+		expanded, err := expandPath(s, fb.opts.lookupEnv)
+		if err != nil {
+			return "", err
+		}
+		return parseFn(expanded)
+	}
+}
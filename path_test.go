@@ -0,0 +1,58 @@
+package easyflag
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	assert.NoError(t, err)
+	env := MapEnv{"DIR": "/data", "NAME": "app"}
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "/etc/app.conf", want: "/etc/app.conf"},
+		{in: "~", want: home},
+		{in: "~/file", want: home + "/file"},
+		{in: "~/$NAME/file", want: home + "/app/file"},
+		{in: "$DIR/${NAME}.conf", want: "/data/app.conf"},
+		{in: "$UNDEFINED/file", want: "/file"},
+		{in: "~user/file", want: "~user/file"},
+		{in: "a~/file", want: "a~/file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := expandPath(tt.in, env.LookupEnv)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseAndLoad_expand(t *testing.T) {
+	home, err := os.UserHomeDir()
+	assert.NoError(t, err)
+	type pathParams struct {
+		Config string `flag:"config|Configuration file|~/.app.conf|expand"`
+		Data   string `flag:"data|Data directory||expand"`
+		Raw    string `flag:"raw|Unexpanded path"`
+	}
+	var p pathParams
+	err = ParseAndLoad(&p, WithArgsSource(StaticArgs{"program", "-data=$DIR/app", "-raw=~/$DIR"}),
+		WithEnvSource(MapEnv{"DIR": "/var/lib"}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, home+"/.app.conf", p.Config)
+	assert.Equal(t, "/var/lib/app", p.Data)
+	assert.Equal(t, "~/$DIR", p.Raw)
+
+	var invalid struct {
+		Port int `flag:"port|Port||expand"`
+	}
+	err = ParseAndLoad(&invalid, WithArgsSource(StaticArgs{"program"}), WithOutput(io.Discard))
+	assert.EqualError(t, err, "invalid field Port: expand not supported for the flag -port")
+}
@@ -0,0 +1,17 @@
+module github.com/matusvla/easyflag/pflagbackend
+
+go 1.18
+
+require (
+	github.com/matusvla/easyflag v0.0.0
+	github.com/spf13/pflag v1.0.5
+	github.com/stretchr/testify v1.7.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
+
+replace github.com/matusvla/easyflag => ../
@@ -0,0 +1,71 @@
+/*
+Package pflagbackend provides the easyflag backend parsing the command line arguments by the github.com/spf13/pflag
+package, which supports the POSIX-style --long flags and the -s short flags defined by the short field tag:
+
+	type params struct {
+		Port    int  `flag:"port|Server port|80" short:"p"`
+		Verbose bool `flag:"verbose|Verbose output" short:"v"`
+	}
+
+	var p params
+	err := easyflag.ParseAndLoad(&p, easyflag.WithBackend(pflagbackend.New()))
+
+The program then accepts e.g. --port=8080, -p 8080 or -vp8080.
+*/
+package pflagbackend
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/matusvla/easyflag"
+	"github.com/spf13/pflag"
+)
+
+type backend struct{}
+
+// New returns the easyflag backend based on the pflag package.
+func New() easyflag.Backend {
+	return backend{}
+}
+
+// Parse sets the flags of the flag set from the arguments parsed by a pflag.FlagSet wrapping the flags.
+func (backend) Parse(fs *flag.FlagSet, args []string, shortNames map[string]string) ([]string, []string, error) {
+	pfs := pflag.NewFlagSet(fs.Name(), pflag.ContinueOnError)
+	pfs.SetOutput(fs.Output())
+	pfs.Usage = fs.Usage
+	fs.VisitAll(func(f *flag.Flag) {
+		pf := pflag.PFlagFromGoFlag(f)
+		if short, ok := shortNames[f.Name]; ok {
+			pf.Shorthand = short
+		}
+		pfs.AddFlag(pf)
+	})
+	if err := pfs.Parse(args); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return nil, nil, flag.ErrHelp
+		}
+		return nil, nil, unknownFlagError(err)
+	}
+	var used []string
+	pfs.Visit(func(f *pflag.Flag) {
+		used = append(used, f.Name)
+	})
+	return used, pfs.Args(), nil
+}
+
+// unknownFlagError converts the errors of the pflag package reporting an undefined flag to the easyflag.UnknownFlagError.
+func unknownFlagError(err error) error {
+	msg := err.Error()
+	if name := strings.TrimPrefix(msg, "unknown flag: --"); name != msg {
+		return &easyflag.UnknownFlagError{Flag: name, Err: err}
+	}
+	if rest := strings.TrimPrefix(msg, "unknown shorthand flag: '"); rest != msg {
+		if r, _ := utf8.DecodeRuneInString(rest); r != utf8.RuneError {
+			return &easyflag.UnknownFlagError{Flag: string(r), Err: err}
+		}
+	}
+	return err
+}
@@ -0,0 +1,89 @@
+package pflagbackend
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/matusvla/easyflag"
+	"github.com/stretchr/testify/assert"
+)
+
+type backendParams struct {
+	Port    int    `flag:"port|Server port|80" short:"p"`
+	Host    string `flag:"host|Server host|localhost"`
+	Verbose bool   `flag:"verbose|Verbose output" short:"v"`
+	Debug   bool   `flag:"debug|Debug output" short:"d"`
+}
+
+func TestBackend_Parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		want     backendParams
+		wantArgs []string
+		wantErr  string
+	}{
+		{
+			name: "defaults",
+			want: backendParams{Port: 80, Host: "localhost"},
+		},
+		{
+			name:     "long flags",
+			args:     []string{"--port=8080", "--host", "example.com", "--verbose", "file.txt"},
+			want:     backendParams{Port: 8080, Host: "example.com", Verbose: true},
+			wantArgs: []string{"file.txt"},
+		},
+		{
+			name: "short flags",
+			args: []string{"-p", "8080", "-v"},
+			want: backendParams{Port: 8080, Host: "localhost", Verbose: true},
+		},
+		{
+			name: "grouped short flags",
+			args: []string{"-vdp8080"},
+			want: backendParams{Port: 8080, Host: "localhost", Verbose: true, Debug: true},
+		},
+		{
+			name: "bool flag values",
+			args: []string{"--verbose=false", "--debug=true"},
+			want: backendParams{Port: 80, Host: "localhost", Debug: true},
+		},
+		{
+			name:    "unknown flag",
+			args:    []string{"--prot=8080"},
+			wantErr: "unknown flag: --prot",
+		},
+		{
+			name:    "unknown short flag",
+			args:    []string{"-x"},
+			wantErr: "unknown shorthand flag: 'x' in -x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p backendParams
+			res, err := easyflag.Parse(&p, easyflag.WithBackend(New()), easyflag.WithOutput(io.Discard),
+				easyflag.WithArgsSource(easyflag.StaticArgs(append([]string{"executable_name"}, tt.args...))))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				var ufe *easyflag.UnknownFlagError
+				assert.True(t, errors.As(err, &ufe))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+			assert.ElementsMatch(t, tt.wantArgs, res.Args)
+		})
+	}
+}
+
+func TestBackend_duplicateShortName(t *testing.T) {
+	var p struct {
+		Verbose bool `flag:"verbose|Verbose output" short:"v"`
+		Version bool `flag:"print-version|Prints the version" short:"v"`
+	}
+	_, err := easyflag.Parse(&p, easyflag.WithBackend(New()), easyflag.WithOutput(io.Discard),
+		easyflag.WithArgsSource(easyflag.StaticArgs{"executable_name", "-v"}))
+	assert.EqualError(t, err, "invalid field Version: short name -v of the flag -print-version already defined by the field Verbose")
+}
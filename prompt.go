@@ -0,0 +1,65 @@
+package easyflag
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrPromptUnavailable is the error returned by a PromptFunc if the value cannot be read interactively,
+// e.g. because the standard input is not a terminal. The flag is then left unset.
+var ErrPromptUnavailable = errors.New("prompt unavailable")
+
+// PromptFunc reads a value interactively, e.g. a password from the terminal with the echo disabled.
+// The prompt is the text shown to the user, e.g. "Database password: ".
+type PromptFunc func(prompt string) (string, error)
+
+/*
+WithPrompt sets the function reading the values of the flags marked by the prompt tag option interactively
+if they are not set by any other source (the command line, environment, secret store, remote sources
+or the configuration file). Passing the passwords on the command line is discouraged and this is the standard
+alternative:
+
+	Password string `flag:"db-password|Database password||secret,prompt" env:"DB_PASSWORD"`
+
+The github.com/matusvla/easyflag/termprompt module provides the function reading the value from the terminal
+with the echo disabled. Without the function, the prompt tag option is ignored.
+*/
+func WithPrompt(fn PromptFunc) Option {
+	return func(o *options) {
+		o.prompt = fn
+	}
+}
+
+// loadPrompts reads the values of the flags marked by the prompt tag option which are not set yet.
+// The reloads never prompt the user again, they keep the current values of the flags instead.
+func (fb *flagBuilder) loadPrompts() error {
+	if fb.opts.prompt == nil {
+		return nil
+	}
+	for _, f := range fb.flags {
+		if _, used := fb.sources[f.name]; !f.isPrompted || used {
+			continue
+		}
+		if fb.opts.reload {
+			if val, ok := fb.opts.reloadPrompted[f.name]; ok {
+				if err := fb.flagSet.Set(f.name, val); err != nil {
+					return fmt.Errorf("invalid value %q for flag -%s: %w", f.displayed(val), f.name, err)
+				}
+				fb.sources[f.name] = SourcePrompt
+			}
+			continue
+		}
+		val, err := fb.opts.prompt(f.usage + ": ")
+		if errors.Is(err, ErrPromptUnavailable) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("reading the value of the flag -%s: %w", f.name, err)
+		}
+		if err := fb.flagSet.Set(f.name, val); err != nil {
+			return fmt.Errorf("invalid value %q for flag -%s: %w", f.displayed(val), f.name, err)
+		}
+		fb.sources[f.name] = SourcePrompt
+	}
+	return nil
+}
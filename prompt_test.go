@@ -0,0 +1,82 @@
+package easyflag
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_prompt(t *testing.T) {
+	type promptParams struct {
+		Password string `flag:"password|Database password||secret,prompt,required" env:"DB_PASSWORD"`
+		User     string `flag:"user|Database user|admin|prompt"`
+		Host     string `flag:"host|Database host|localhost"`
+	}
+	answers := func(values map[string]string) PromptFunc {
+		return func(prompt string) (string, error) {
+			v, ok := values[prompt]
+			if !ok {
+				return "", errors.New("unexpected prompt " + prompt)
+			}
+			return v, nil
+		}
+	}
+	tests := []struct {
+		name        string
+		args        []string
+		env         MapEnv
+		prompt      PromptFunc
+		want        promptParams
+		wantSources map[string]Source
+		wantErr     string
+	}{
+		{
+			name:        "prompted",
+			args:        []string{"program"},
+			prompt:      answers(map[string]string{"Database password: ": "hunter2", "Database user: ": "root"}),
+			want:        promptParams{Password: "hunter2", User: "root", Host: "localhost"},
+			wantSources: map[string]Source{"password": SourcePrompt, "user": SourcePrompt, "host": SourceDefault},
+		},
+		{
+			name:        "set by other sources",
+			args:        []string{"program", "-user=guest"},
+			env:         MapEnv{"DB_PASSWORD": "env"},
+			prompt:      answers(nil),
+			want:        promptParams{Password: "env", User: "guest", Host: "localhost"},
+			wantSources: map[string]Source{"password": SourceEnv, "user": SourceCommandLine, "host": SourceDefault},
+		},
+		{
+			name:    "prompt unavailable",
+			args:    []string{"program"},
+			prompt:  func(string) (string, error) { return "", ErrPromptUnavailable },
+			wantErr: `missing required flag "password" or its value`,
+		},
+		{
+			name:    "no prompt function",
+			args:    []string{"program"},
+			wantErr: `missing required flag "password" or its value`,
+		},
+		{
+			name:    "prompt failure",
+			args:    []string{"program", "-password=x"},
+			prompt:  answers(nil),
+			wantErr: "reading the value of the flag -user: unexpected prompt Database user: ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p promptParams
+			res, err := Parse(&p, WithArgsSource(StaticArgs(tt.args)), WithEnvSource(tt.env), WithPrompt(tt.prompt),
+				WithOutput(io.Discard), WithoutExit())
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+			assert.Equal(t, tt.wantSources, res.Sources)
+		})
+	}
+}
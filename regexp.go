@@ -0,0 +1,28 @@
+package easyflag
+
+import "regexp"
+
+// regexpValue is the flag value of the *regexp.Regexp fields compiled from the flag values.
+type regexpValue struct {
+	re **regexp.Regexp
+}
+
+func (v *regexpValue) String() string {
+	if v.re == nil || *v.re == nil {
+		return ""
+	}
+	return (*v.re).String()
+}
+
+func (v *regexpValue) Set(s string) error {
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return err
+	}
+	*v.re = re
+	return nil
+}
+
+func (v *regexpValue) valueName() string {
+	return "regexp"
+}
@@ -0,0 +1,57 @@
+package easyflag
+
+import (
+	"io"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAndLoad_regexp(t *testing.T) {
+	type filterParams struct {
+		Include *regexp.Regexp `flag:"include|Included names|^app-"`
+		Exclude *regexp.Regexp `flag:"exclude|Excluded names"`
+	}
+	tests := []struct {
+		name        string
+		args        []string
+		wantInclude string
+		wantExclude string
+		wantErr     string
+	}{
+		{
+			name:        "default",
+			args:        []string{"program"},
+			wantInclude: "^app-",
+		},
+		{
+			name:        "set",
+			args:        []string{"program", "-include=(?i)^svc-", "-exclude=-test$"},
+			wantInclude: "(?i)^svc-",
+			wantExclude: "-test$",
+		},
+		{
+			name:    "invalid pattern",
+			args:    []string{"program", "-exclude=(abc"},
+			wantErr: "invalid value \"(abc\" for flag -exclude: error parsing regexp: missing closing ): `(abc`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p filterParams
+			err := ParseAndLoad(&p, WithArgsSource(StaticArgs(tt.args)), WithOutput(io.Discard))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantInclude, p.Include.String())
+			if tt.wantExclude == "" {
+				assert.Nil(t, p.Exclude)
+			} else {
+				assert.Equal(t, tt.wantExclude, p.Exclude.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package easyflag
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// RestartRequiredError is an error returned in case that the flags which are not marked by the reloadable tag option
+// differ between the current and the reloaded configuration, i.e. the program must be restarted to apply them.
+type RestartRequiredError struct {
+	Flags []string
+}
+
+// Error prints the description of the RestartRequiredError.
+func (e *RestartRequiredError) Error() string {
+	return fmt.Sprintf("restart required to change the flags %q", strings.Join(e.Flags, ", "))
+}
+
+/*
+CheckReload checks that the reloaded params structure differs from the current one only in the flags marked
+by the reloadable tag option. Both arguments must be pointers to the structures of the same type just like
+in the case of the ParseAndLoad function. The flags are compared by their values as printed in the usage message,
+e.g. the File flags by their paths. If any other flag differs, a *RestartRequiredError listing the changed
+flags is returned.
+*/
+func CheckReload(current, reloaded interface{}, opts ...Option) error {
+	if err := checkParams(reloaded); err != nil {
+		return err
+	}
+	if reflect.TypeOf(current) != reflect.TypeOf(reloaded) {
+		return &InvalidParamsError{Type: reflect.TypeOf(reloaded)}
+	}
+	fb, err := newDetachedFlagBuilder(current, opts)
+	if err != nil {
+		return err
+	}
+	// both structures are copied to the detached instance in turn to compare their flag values
+	fb.detached.Elem().Set(reflect.ValueOf(current).Elem())
+	cur := fb.flagValues()
+	fb.detached.Elem().Set(reflect.ValueOf(reloaded).Elem())
+	rel := fb.flagValues()
+	var changed []string
+	for _, f := range fb.flags {
+		// the external flags are not part of the compared structures
+		if f.isReloadable || f.external {
+			continue
+		}
+		if cur[f.name] != rel[f.name] {
+			changed = append(changed, f.name)
+		}
+	}
+	if len(changed) > 0 {
+		return &RestartRequiredError{Flags: changed}
+	}
+	return nil
+}
+
+/*
+Reloadable holds the params structure of type T parsed just like by the ParseAndLoad function, which can be reloaded
+while the program is running, e.g. after the configuration file or the remote source is changed:
+
+	r, err := easyflag.NewReloadable[params]()
+	[...]
+	go r.ReloadOnSignal(ctx, logReloadError) // reloads on SIGHUP
+	[...]
+	p := r.Get()
+
+The reload parses the same command line arguments again, so only the values loaded from the other sources
+(environment variables, secret store, remote sources and configuration file) can change. The validations
+and extensions are run on the new structure, which replaces the current one atomically only if the reload succeeds.
+The reload does not prompt the user again, the prompted flags keep their values. The File flags keep the files
+opened by the initial parsing, a file is opened by the reload only if its path changes, and then the previous file
+is closed once the new params structure replaces the current one.
+*/
+type Reloadable[T any] struct {
+	opts      []Option
+	mu        sync.Mutex   // serializes the reloads and guards the fields below
+	current   atomic.Value // *T
+	values    map[string]string
+	prompted  map[string]string // the values of the flags read by the prompts, the reloads keep them
+	callbacks map[string][]func(old, new string)
+}
+
+// NewReloadable creates the Reloadable by parsing the params structure of type T, which must be a structure,
+// with the given options the same way as the ParseAndLoad function.
+func NewReloadable[T any](opts ...Option) (*Reloadable[T], error) {
+	params := new(T)
+	res, err := Parse(params, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// the reloads parse the same arguments and they must not print anything or terminate the program
+	reloadOpts := append(opts[:len(opts):len(opts)],
+		WithArgsSource(StaticArgs(newOptions(opts).args())), WithOutput(io.Discard), WithoutExit())
+	r := &Reloadable[T]{opts: reloadOpts, values: res.fb.flagValues(), prompted: map[string]string{},
+		callbacks: map[string][]func(old, new string){}}
+	for name, src := range res.fb.sources {
+		if src == SourcePrompt {
+			r.prompted[name] = r.values[name]
+		}
+	}
+	r.current.Store(params)
+	return r, nil
+}
+
+// Get returns the current params structure. The structure must not be modified, it is replaced by a new one
+// on every successful reload.
+func (r *Reloadable[T]) Get() *T {
+	return r.current.Load().(*T)
+}
+
+// Reload parses the params structure again and replaces the current one by it. If the parsing fails or
+// the flags not marked by the reloadable tag option change (see the CheckReload function), the current structure
+// is kept and the error is returned. Otherwise, the functions registered by the OnChange method are called
+// for the changed flags.
+func (r *Reloadable[T]) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	params := new(T)
+	res, err := Parse(params, append(r.opts[:len(r.opts):len(r.opts)], func(o *options) {
+		o.reload, o.reloadPrompted = true, r.prompted
+	})...)
+	if err != nil {
+		return fmt.Errorf("reloading the params: %w", err)
+	}
+	if err := CheckReload(r.Get(), params, r.opts...); err != nil {
+		return err
+	}
+	replaced, err := r.keepFiles(res.fb, params)
+	if err != nil {
+		return fmt.Errorf("reloading the params: %w", err)
+	}
+	r.current.Store(params)
+	for _, f := range replaced {
+		_ = f.Close()
+	}
+	old := r.values
+	r.values = res.fb.flagValues()
+	for _, f := range res.fb.flags {
+		if oldVal, newVal := old[f.name], r.values[f.name]; oldVal != newVal {
+			for _, fn := range r.callbacks[f.name] {
+				fn(oldVal, newVal)
+			}
+		}
+	}
+	return nil
+}
+
+// keepFiles sets the File flags of the reloaded params to the files opened for the current params. Only the files
+// whose paths changed, i.e. the files of the reloadable File flags, are opened. The replaced files of the current
+// params are returned, so that they are closed once the reloaded params replace the current ones.
+func (r *Reloadable[T]) keepFiles(fb *flagBuilder, params *T) ([]*os.File, error) {
+	cur, rel := reflect.ValueOf(r.Get()).Elem(), reflect.ValueOf(params).Elem()
+	current := make(map[*File]*File, len(fb.files))
+	for _, f := range fb.flags {
+		if f.external {
+			continue
+		}
+		if file, ok := rel.FieldByIndex(f.fieldIndex).Addr().Interface().(*File); ok {
+			current[file] = cur.FieldByIndex(f.fieldIndex).Addr().Interface().(*File)
+		}
+	}
+	var (
+		opened   []*fileValue
+		replaced []*os.File
+	)
+	for _, fv := range fb.files {
+		c, ok := current[fv.File]
+		if ok && c.path == fv.path {
+			fv.File.File = c.File
+			continue
+		}
+		if err := fv.open(); err != nil {
+			for _, o := range opened {
+				_ = o.File.Close()
+			}
+			return nil, err
+		}
+		opened = append(opened, fv)
+		if ok && c.File != nil {
+			replaced = append(replaced, c.File)
+		}
+	}
+	return replaced, nil
+}
+
+// OnChange registers the function called with the old and the new value of the flag with the given name
+// whenever a reload changes it, e.g. to adjust the level of a logger. The functions are called after the new
+// params structure replaces the current one, in the order of the flag definitions and of the registrations.
+// They must not call the Reload method. An error is returned if the flag is not defined.
+func (r *Reloadable[T]) OnChange(name string, fn func(old, new string)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.values[name]; !ok {
+		return fmt.Errorf("flag -%s not defined", name)
+	}
+	r.callbacks[name] = append(r.callbacks[name], fn)
+	return nil
+}
+
+// flagValues returns the string representations of the current values of the flags by their names.
+func (fb *flagBuilder) flagValues() map[string]string {
+	values := make(map[string]string, len(fb.flags))
+	for _, f := range fb.flags {
+		values[f.name] = fb.flagSet.Lookup(f.name).Value.String()
+	}
+	return values
+}
+
+// ReloadOnSignal reloads the params structure whenever the program receives any of the signals (SIGHUP by default)
+// until the context is canceled. The done function, if not nil, is called with the result of every reload.
+func (r *Reloadable[T]) ReloadOnSignal(ctx context.Context, done func(error), sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		sigs = defaultReloadSignals
+	}
+	if len(sigs) == 0 {
+		// the runtime does not support any signals
+		<-ctx.Done()
+		return
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	defer signal.Stop(ch)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ch:
+			err := r.Reload()
+			if done != nil {
+				done(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !js
+
+package easyflag
+
+import (
+	"os"
+	"syscall"
+)
+
+// defaultReloadSignals are the signals reloading the params, see the Reloadable.ReloadOnSignal method.
+var defaultReloadSignals = []os.Signal{syscall.SIGHUP}
@@ -0,0 +1,7 @@
+package easyflag
+
+import "os"
+
+// defaultReloadSignals are the signals reloading the params, see the Reloadable.ReloadOnSignal method.
+// There are no signals in the JavaScript runtime.
+var defaultReloadSignals []os.Signal
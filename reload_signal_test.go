@@ -0,0 +1,53 @@
+//go:build !js
+
+package easyflag
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloadable_ReloadOnSignal(t *testing.T) {
+	type reloadableParams struct {
+		Port     int    `flag:"port|Server port|80" env:"PORT"`
+		LogLevel string `flag:"log|Log level|info|reloadable" env:"LOG_LEVEL"`
+	}
+	env := MapEnv{"LOG_LEVEL": "warn"}
+	r, err := NewReloadable[reloadableParams](WithArgsSource(StaticArgs{"program", "-port=8080"}), WithEnvSource(env))
+	assert.NoError(t, err)
+
+	// the SIGHUP sent before the handler of ReloadOnSignal is registered must not terminate the test binary
+	guard := make(chan os.Signal, 1)
+	signal.Notify(guard, syscall.SIGHUP)
+	defer signal.Stop(guard)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan error, 1)
+	env["LOG_LEVEL"] = "error"
+	go r.ReloadOnSignal(ctx, func(err error) {
+		select {
+		case reloaded <- err:
+		default:
+		}
+	})
+	p, err := os.FindProcess(os.Getpid())
+	assert.NoError(t, err)
+	// the signal is repeated until the handler is registered and the reload is done
+	for {
+		assert.NoError(t, p.Signal(syscall.SIGHUP))
+		select {
+		case err := <-reloaded:
+			assert.NoError(t, err)
+			assert.Equal(t, &reloadableParams{Port: 8080, LogLevel: "error"}, r.Get())
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package easyflag
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reloadParams struct {
+	Port     int    `flag:"port|Server port|80"`
+	LogLevel string `flag:"log|Log level|info|reloadable"`
+	Limits   struct {
+		Rate  int `flag:"rate|Rate limit|10|reloadable"`
+		Burst int `flag:"burst|Burst limit|20"`
+	}
+}
+
+func TestCheckReload(t *testing.T) {
+	base := reloadParams{Port: 80, LogLevel: "info"}
+	base.Limits.Rate, base.Limits.Burst = 10, 20
+	tests := []struct {
+		name     string
+		reloaded func(p reloadParams) interface{}
+		wantErr  error
+	}{
+		{
+			name:     "unchanged",
+			reloaded: func(p reloadParams) interface{} { return &p },
+		},
+		{
+			name: "reloadable flags changed",
+			reloaded: func(p reloadParams) interface{} {
+				p.LogLevel, p.Limits.Rate = "debug", 100
+				return &p
+			},
+		},
+		{
+			name: "restart required",
+			reloaded: func(p reloadParams) interface{} {
+				p.Port, p.LogLevel, p.Limits.Burst = 8080, "debug", 50
+				return &p
+			},
+			wantErr: &RestartRequiredError{Flags: []string{"port", "burst"}},
+		},
+		{
+			name:     "different types",
+			reloaded: func(p reloadParams) interface{} { return &struct{}{} },
+			wantErr:  &InvalidParamsError{Type: reflect.TypeOf(&struct{}{})},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := base
+			assert.Equal(t, tt.wantErr, CheckReload(&current, tt.reloaded(base)))
+			assert.Equal(t, base, current)
+		})
+	}
+}
+
+func TestReloadable(t *testing.T) {
+	type reloadableParams struct {
+		Port     int    `flag:"port|Server port|80" env:"PORT"`
+		LogLevel string `flag:"log|Log level|info|reloadable" env:"LOG_LEVEL"`
+	}
+	env := MapEnv{"LOG_LEVEL": "warn"}
+	r, err := NewReloadable[reloadableParams](WithArgsSource(StaticArgs{"program", "-port=8080"}), WithEnvSource(env))
+	assert.NoError(t, err)
+	assert.Equal(t, &reloadableParams{Port: 8080, LogLevel: "warn"}, r.Get())
+
+	env["LOG_LEVEL"] = "debug"
+	env["PORT"] = "1" // the command line still takes precedence
+	assert.NoError(t, r.Reload())
+	assert.Equal(t, &reloadableParams{Port: 8080, LogLevel: "debug"}, r.Get())
+}
+
+func TestReloadable_restartRequired(t *testing.T) {
+	type restartParams struct {
+		Port int `flag:"port|Server port|80" env:"PORT"`
+	}
+	env := MapEnv{"PORT": "8080"}
+	r, err := NewReloadable[restartParams](WithArgsSource(StaticArgs{"program"}), WithEnvSource(env))
+	assert.NoError(t, err)
+
+	env["PORT"] = "9090"
+	assert.Equal(t, &RestartRequiredError{Flags: []string{"port"}}, r.Reload())
+	assert.Equal(t, &restartParams{Port: 8080}, r.Get())
+
+	env["PORT"] = "abc"
+	assert.EqualError(t, r.Reload(), `reloading the params: invalid value "abc" of the environment variable PORT for the flag -port: parse error`)
+	assert.Equal(t, &restartParams{Port: 8080}, r.Get())
+
+	_, err = NewReloadable[int](WithArgsSource(StaticArgs{"program"}))
+	assert.Equal(t, &InvalidParamsError{Type: reflect.TypeOf(new(int))}, err)
+}
+
+func TestReloadable_fileAndPrompt(t *testing.T) {
+	type fileParams struct {
+		In       File   `flag:"in|Input file"`
+		Password string `flag:"password|Password||prompt"`
+		LogLevel string `flag:"log|Log level|info|reloadable" env:"LOG_LEVEL"`
+		Out      File   `flag:"out|Output file||mode=create,reloadable" env:"OUT"`
+	}
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in.txt")
+	assert.NoError(t, os.WriteFile(in, nil, 0o600))
+	env := MapEnv{"OUT": filepath.Join(dir, "a.txt")}
+	prompts := 0
+	r, err := NewReloadable[fileParams](WithArgsSource(StaticArgs{"program", "-in", in}), WithEnvSource(env),
+		WithPrompt(func(string) (string, error) {
+			prompts++
+			return "secret", nil
+		}))
+	assert.NoError(t, err)
+	defer r.Get().In.Close()
+	file, out := r.Get().In.File, r.Get().Out.File
+
+	env["LOG_LEVEL"] = "debug"
+	assert.NoError(t, r.Reload())
+	assert.Equal(t, 1, prompts)
+	assert.Same(t, file, r.Get().In.File)
+	assert.Same(t, out, r.Get().Out.File)
+	assert.Equal(t, "secret", r.Get().Password)
+	assert.Equal(t, "debug", r.Get().LogLevel)
+
+	// the file of the changed path is opened and the previous one is closed
+	env["OUT"] = filepath.Join(dir, "b.txt")
+	assert.NoError(t, r.Reload())
+	defer r.Get().Out.Close()
+	assert.Same(t, file, r.Get().In.File)
+	assert.Equal(t, filepath.Join(dir, "b.txt"), r.Get().Out.Name())
+	_, err = out.WriteString("x")
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestReloadable_OnChange(t *testing.T) {
+	type changeParams struct {
+		LogLevel string `flag:"log-level|Log level|info|reloadable" env:"LOG_LEVEL"`
+		Rate     int    `flag:"rate|Rate limit|10|reloadable" env:"RATE"`
+	}
+	env := MapEnv{}
+	r, err := NewReloadable[changeParams](WithArgsSource(StaticArgs{"program"}), WithEnvSource(env))
+	assert.NoError(t, err)
+
+	var changes []string
+	record := func(prefix string) func(old, new string) {
+		return func(old, new string) { changes = append(changes, prefix+": "+old+" -> "+new) }
+	}
+	assert.NoError(t, r.OnChange("log-level", record("log")))
+	assert.NoError(t, r.OnChange("rate", record("rate")))
+	assert.NoError(t, r.OnChange("log-level", record("log again")))
+	assert.EqualError(t, r.OnChange("level", record("unknown")), "flag -level not defined")
+
+	assert.NoError(t, r.Reload())
+	assert.Empty(t, changes)
+
+	env["LOG_LEVEL"], env["RATE"] = "debug", "20"
+	assert.NoError(t, r.Reload())
+	assert.Equal(t, []string{"log: info -> debug", "log again: info -> debug", "rate: 10 -> 20"}, changes)
+
+	changes = nil
+	env["RATE"] = "abc"
+	assert.Error(t, r.Reload())
+	assert.Empty(t, changes)
+}
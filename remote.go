@@ -0,0 +1,98 @@
+package easyflag
+
+import (
+	"context"
+	"fmt"
+)
+
+/*
+RemoteSource provides the flag values stored remotely, e.g. in the AWS SSM Parameter Store.
+The github.com/matusvla/easyflag/ssmsource module provides the implementation for the AWS SSM Parameter Store.
+
+The key is the flag name. The remote sources are consulted only for the flags neither used on the command line,
+nor set by the environment or the secret store, so they take precedence over the configuration file
+and the default value.
+*/
+type RemoteSource interface {
+	// Lookup returns the value of the key and whether it is present in the source. The context is the one passed
+	// by the WithContext option, e.g. with a timeout.
+	Lookup(ctx context.Context, key string) (string, bool, error)
+}
+
+// WithRemoteSource adds the source of the flag values stored remotely. The option can be used multiple times,
+// in which case the sources are consulted in the order of the options and the first one containing the flag wins.
+// See the RemoteSource type for more details.
+func WithRemoteSource(s RemoteSource) Option {
+	return func(o *options) {
+		o.remoteSources = append(o.remoteSources, s)
+	}
+}
+
+// loadRemote sets the flags not set yet from the remote sources.
+func (fb *flagBuilder) loadRemote() error {
+	ctx := fb.opts.context()
+	for _, f := range fb.flags {
+		if _, used := fb.sources[f.name]; used {
+			continue
+		}
+		for _, s := range fb.opts.remoteSources {
+			val, ok, err := s.Lookup(ctx, f.name)
+			if err != nil {
+				return fmt.Errorf("looking up the flag -%s in the remote source: %w", f.name, err)
+			}
+			if !ok {
+				continue
+			}
+			if err := fb.flagSet.Set(f.name, val); err != nil {
+				return fmt.Errorf("invalid value %q of the flag -%s in the remote source: %w", f.displayed(val), f.name, err)
+			}
+			fb.sources[f.name] = SourceRemote
+			break
+		}
+	}
+	return nil
+}
+
+// WatchableSource is a RemoteSource notifying about the changes of its values, e.g. the etcd or Consul key-value store.
+// The github.com/matusvla/easyflag/etcdsource and github.com/matusvla/easyflag/consulsource modules provide
+// the implementations.
+type WatchableSource interface {
+	RemoteSource
+	// Watch calls the changed function whenever a value of the source changes until the context is canceled.
+	Watch(ctx context.Context, changed func()) error
+}
+
+/*
+WatchSources watches all the remote sources passed by the WithRemoteSource options implementing the WatchableSource
+interface and calls the changed function whenever a value of any of them changes, e.g. to parse the params again.
+It blocks until the context is canceled or watching any of the sources fails, in which case the other sources
+stop being watched as well and the error is returned. If none of the sources is watchable, it returns nil immediately.
+*/
+func WatchSources(ctx context.Context, changed func(), opts ...Option) error {
+	o := newOptions(opts)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errs := make(chan error, len(o.remoteSources))
+	var watched int
+	for _, s := range o.remoteSources {
+		ws, ok := s.(WatchableSource)
+		if !ok {
+			continue
+		}
+		watched++
+		go func() {
+			errs <- ws.Watch(ctx, changed)
+		}()
+	}
+	var firstErr error
+	for i := 0; i < watched; i++ {
+		if err := <-errs; err != nil && firstErr == nil && ctx.Err() == nil {
+			firstErr = fmt.Errorf("watching the remote source: %w", err)
+			cancel()
+		}
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,171 @@
+package easyflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mapRemote map[string]string
+
+func (r mapRemote) Lookup(_ context.Context, key string) (string, bool, error) {
+	v, ok := r[key]
+	return v, ok, nil
+}
+
+type failingRemote struct{}
+
+func (failingRemote) Lookup(context.Context, string) (string, bool, error) {
+	return "", false, errors.New("access denied")
+}
+
+// ctxRemote is a remote source failing if the context is done, e.g. on a timeout.
+type ctxRemote struct{}
+
+func (ctxRemote) Lookup(ctx context.Context, _ string) (string, bool, error) {
+	<-ctx.Done()
+	return "", false, ctx.Err()
+}
+
+func TestParse_remoteSourceContext(t *testing.T) {
+	var p struct {
+		Host string `flag:"host|Server host|localhost"`
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := Parse(&p, WithArgsSource(StaticArgs{"program"}), WithRemoteSource(ctxRemote{}), WithContext(ctx),
+		WithOutput(io.Discard))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestParse_remoteSource(t *testing.T) {
+	type remoteParams struct {
+		Host string `flag:"host|Server host|localhost" env:"HOST"`
+		Port int    `flag:"port|Server port|80"`
+		Tag  string `flag:"tag|Release tag|latest"`
+	}
+	tests := []struct {
+		name        string
+		args        []string
+		env         MapEnv
+		sources     []RemoteSource
+		want        remoteParams
+		wantSources map[string]Source
+		wantErr     string
+	}{
+		{
+			name:        "remote values",
+			args:        []string{"program"},
+			sources:     []RemoteSource{mapRemote{"host": "example.com", "port": "8080"}},
+			want:        remoteParams{Host: "example.com", Port: 8080, Tag: "latest"},
+			wantSources: map[string]Source{"host": SourceRemote, "port": SourceRemote, "tag": SourceDefault},
+		},
+		{
+			name:        "command line and environment take precedence",
+			args:        []string{"program", "-port=1"},
+			env:         MapEnv{"HOST": "env.com"},
+			sources:     []RemoteSource{mapRemote{"host": "example.com", "port": "8080", "tag": "v1"}},
+			want:        remoteParams{Host: "env.com", Port: 1, Tag: "v1"},
+			wantSources: map[string]Source{"host": SourceEnv, "port": SourceCommandLine, "tag": SourceRemote},
+		},
+		{
+			name:        "first source wins",
+			args:        []string{"program"},
+			sources:     []RemoteSource{mapRemote{"tag": "v1"}, mapRemote{"tag": "v2", "port": "8080"}},
+			want:        remoteParams{Host: "localhost", Port: 8080, Tag: "v1"},
+			wantSources: map[string]Source{"host": SourceDefault, "port": SourceRemote, "tag": SourceRemote},
+		},
+		{
+			name:    "lookup failure",
+			args:    []string{"program"},
+			sources: []RemoteSource{failingRemote{}},
+			wantErr: "looking up the flag -host in the remote source: access denied",
+		},
+		{
+			name:    "invalid value",
+			args:    []string{"program"},
+			sources: []RemoteSource{mapRemote{"port": "abc"}},
+			wantErr: `invalid value "abc" of the flag -port in the remote source: parse error`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p remoteParams
+			opts := []Option{WithArgsSource(StaticArgs(tt.args)), WithEnvSource(tt.env), WithOutput(io.Discard), WithoutExit()}
+			for _, s := range tt.sources {
+				opts = append(opts, WithRemoteSource(s))
+			}
+			res, err := Parse(&p, opts...)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+			assert.Equal(t, tt.wantSources, res.Sources)
+		})
+	}
+}
+
+type watchedRemote struct {
+	mapRemote
+	changes int
+	err     error
+}
+
+func (r *watchedRemote) Watch(ctx context.Context, changed func()) error {
+	for i := 0; i < r.changes; i++ {
+		changed()
+	}
+	if r.err != nil {
+		return r.err
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func TestWatchSources(t *testing.T) {
+	tests := []struct {
+		name        string
+		sources     []RemoteSource
+		wantChanges int32
+		wantErr     error
+	}{
+		{
+			name:        "changes until canceled",
+			sources:     []RemoteSource{&watchedRemote{changes: 2}, mapRemote{}, &watchedRemote{changes: 1}},
+			wantChanges: 3,
+			wantErr:     context.DeadlineExceeded,
+		},
+		{
+			name:    "no watchable sources",
+			sources: []RemoteSource{mapRemote{}},
+		},
+		{
+			name:        "watch failure",
+			sources:     []RemoteSource{&watchedRemote{}, &watchedRemote{changes: 1, err: errors.New("connection lost")}},
+			wantChanges: 1,
+			wantErr:     fmt.Errorf("watching the remote source: %w", errors.New("connection lost")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			var opts []Option
+			for _, s := range tt.sources {
+				opts = append(opts, WithRemoteSource(s))
+			}
+			var changes int32
+			err := WatchSources(ctx, func() { atomic.AddInt32(&changes, 1) }, opts...)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantChanges, atomic.LoadInt32(&changes))
+		})
+	}
+}
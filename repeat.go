@@ -0,0 +1,91 @@
+package easyflag
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+)
+
+// RepeatedFlagPolicy defines what happens when a flag which does not collect multiple values (e.g. a string or int
+// flag, unlike a Set or slice flag) is used more than once on the command line.
+type RepeatedFlagPolicy int
+
+const (
+	// LastValueWins silently uses the last value just like the native flag package. This is the default policy.
+	LastValueWins RepeatedFlagPolicy = iota
+	// WarnRepeated uses the last value and writes a warning to the output.
+	WarnRepeated
+	// RejectRepeated makes the parsing fail.
+	RejectRepeated
+)
+
+// WithRepeatedFlagPolicy sets the policy of the flags used more than once on the command line,
+// e.g. to catch the accidental duplicate flags in the long command lines.
+func WithRepeatedFlagPolicy(policy RepeatedFlagPolicy) Option {
+	return func(o *options) {
+		o.repeatedPolicy = policy
+	}
+}
+
+// countingFlagValue wraps the value of a flag while the command line is parsed to count how many times it is set.
+type countingFlagValue struct {
+	flag.Value
+	count int
+}
+
+func (v *countingFlagValue) Set(s string) error {
+	v.count++
+	return v.Value.Set(s)
+}
+
+func (v *countingFlagValue) IsBoolFlag() bool {
+	bf, ok := v.Value.(interface{ IsBoolFlag() bool })
+	return ok && bf.IsBoolFlag()
+}
+
+// wrapCountingValues wraps the values of the flags which do not collect multiple values by the countingFlagValue,
+// unless the policy is LastValueWins, and returns the function checking the counts and restoring the values.
+func (fb *flagBuilder) wrapCountingValues() func() error {
+	if fb.opts.repeatedPolicy == LastValueWins {
+		return func() error { return nil }
+	}
+	var wrapped []*flag.Flag
+	for _, f := range fb.flags {
+		fl := fb.flagSet.Lookup(f.name)
+		if collectsValues(f, fl.Value) {
+			continue
+		}
+		fl.Value = &countingFlagValue{Value: fl.Value}
+		wrapped = append(wrapped, fl)
+	}
+	return func() error {
+		var err error
+		for _, fl := range wrapped {
+			cv := fl.Value.(*countingFlagValue)
+			fl.Value = cv.Value
+			if cv.count < 2 || err != nil {
+				continue
+			}
+			if fb.opts.repeatedPolicy == RejectRepeated {
+				err = fmt.Errorf(fb.opts.messages.RepeatedFlagRejected, fl.Name, cv.count)
+				continue
+			}
+			fmt.Fprintf(fb.flagSet.Output(), fb.opts.messages.RepeatedFlag+"\n", fl.Name, cv.count)
+		}
+		return err
+	}
+}
+
+// collectsValues reports whether the flag collects the values of its repeated uses, e.g. a Set or a slice flag.
+func collectsValues(f flagInfo, val flag.Value) bool {
+	if _, ok := val.(*setValue); ok {
+		return true
+	}
+	switch f.fieldType.Kind() {
+	case reflect.Slice:
+		return f.fieldType.Elem().Kind() != reflect.Uint8 // []byte holds a single value
+	case reflect.Map:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package easyflag
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_repeatedFlagPolicy(t *testing.T) {
+	type repeatParams struct {
+		Env     string `flag:"env|Environment"`
+		Token   string `flag:"token|API token||secret"`
+		Verbose bool   `flag:"verbose|Verbose output"`
+		Labels  Set    `flag:"label|Labels"`
+		Tags    []int  `flag:"tags|Tags||json"`
+	}
+	tests := []struct {
+		name       string
+		policy     RepeatedFlagPolicy
+		args       []string
+		wantEnv    string
+		wantOutput string
+		wantErr    string
+	}{
+		{
+			name:    "last value wins",
+			policy:  LastValueWins,
+			args:    []string{"-env=dev", "-env=prod"},
+			wantEnv: "prod",
+		},
+		{
+			name:       "warning",
+			policy:     WarnRepeated,
+			args:       []string{"-env=dev", "-env=prod", "-verbose", "-verbose"},
+			wantEnv:    "prod",
+			wantOutput: "warning: flag -env used 2 times, the last value is used\nwarning: flag -verbose used 2 times, the last value is used\n",
+		},
+		{
+			name:    "error",
+			policy:  RejectRepeated,
+			args:    []string{"-env=dev", "-env=prod", "-env=test"},
+			wantErr: "flag -env used 3 times, it can be used only once",
+		},
+		{
+			name:    "error of a secret flag",
+			policy:  RejectRepeated,
+			args:    []string{"-token=a", "-token=b"},
+			wantErr: "flag -token used 2 times, it can be used only once",
+		},
+		{
+			name:    "flags collecting values",
+			policy:  RejectRepeated,
+			args:    []string{"-label=a", "-label=b", "-tags=[1]", "-tags=[2]", "-env=dev"},
+			wantEnv: "dev",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p repeatParams
+			var out bytes.Buffer
+			_, err := Parse(&p, WithRepeatedFlagPolicy(tt.policy),
+				WithArgsSource(StaticArgs(append([]string{"program"}, tt.args...))), WithOutput(&out))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantEnv, p.Env)
+			assert.Equal(t, tt.wantOutput, out.String())
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package easyflag
+
+import (
+	"fmt"
+	"os"
+)
+
+/*
+ReservedFlag is a flag handled by the package itself instead of being loaded into the params structure.
+The names of the reserved flags cannot be used by the params structure fields.
+
+The built-in -version, -V, -easyflag-completion, -generate-docs and -generate-completion flags are implemented as the reserved flags.
+The -h and -help flags are reserved as well, but they are handled by the native flag package.
+
+If the Handler is nil, the names are only reserved and the flag is not defined at all.
+*/
+type ReservedFlag struct {
+	// Names are the names of the flag without the leading hyphen, e.g. {"version", "V"}.
+	Names []string
+	// Usage is the usage description of the flag.
+	Usage string
+	// IsBool specifies that the flag is a boolean flag which does not take any value.
+	IsBool bool
+	// Hidden flags are not shown in the usage message and the generated documentation.
+	Hidden bool
+	// Handler is called with the flag value after the flags are parsed if the flag was used.
+	// If it returns a non-nil error, the parsing stops and the error is returned by the ParseAndLoad function.
+	// The returned error can be a sentinel (e.g. ErrVersion) signaling that the program should stop without a failure.
+	Handler func(value string) error
+}
+
+// WithReservedFlag adds a reserved flag with its handler. See the ReservedFlag type for more details.
+func WithReservedFlag(rf ReservedFlag) Option {
+	return func(o *options) {
+		o.reservedFlags = append(o.reservedFlags, rf)
+	}
+}
+
+// reservedValue is the flag.Value of a reserved flag remembering whether the flag was used.
+type reservedValue struct {
+	value  string
+	isSet  bool
+	isBool bool
+}
+
+func (v *reservedValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.value
+}
+
+func (v *reservedValue) Set(s string) error {
+	v.value, v.isSet = s, true
+	return nil
+}
+
+func (v *reservedValue) IsBoolFlag() bool {
+	return v.isBool
+}
+
+type reservedFlag struct {
+	ReservedFlag
+	value        *reservedValue
+	isDocumented bool
+}
+
+// setUpReservedFlags registers the built-in reserved flags and the reserved flags passed in the options.
+func (fb *flagBuilder) setUpReservedFlags() error {
+	// the help flags are not defined in the flag set, because they are handled by the native flag package
+	fb.reserved = append(fb.reserved, reservedFlag{
+		ReservedFlag: ReservedFlag{
+			Names:  []string{helpArgShort[1:], helpArg[1:]},
+			Usage:  "Prints the usage information",
+			IsBool: true,
+		},
+		value:        &reservedValue{isBool: true},
+		isDocumented: true,
+	})
+
+	var builtin []ReservedFlag
+	if fb.opts.version != "" {
+		builtin = append(builtin, ReservedFlag{
+			Names:  []string{versionArgShort[1:], versionArg[1:]},
+			Usage:  "Prints the version information",
+			IsBool: true,
+			Handler: func(string) error {
+				fmt.Fprintln(os.Stdout, fb.opts.version)
+				return ErrVersion
+			},
+		})
+	}
+	if fb.opts.configFlag != "" {
+		builtin = append(builtin, fb.configFlag())
+	}
+	if fb.opts.printConfigFlag {
+		builtin = append(builtin, fb.printConfigFlag())
+	}
+	if fb.opts.generatorFlags {
+		builtin = append(builtin, fb.generatorFlags()...)
+	}
+	builtin = append(builtin, ReservedFlag{
+		Names:  []string{completionArg[1:]},
+		Usage:  "Prints the shell completion script",
+		Hidden: true,
+		Handler: func(shell string) error {
+			if err := fb.writeCompletion(os.Stdout, Shell(shell)); err != nil {
+				return err
+			}
+			return ErrCompletion
+		},
+	})
+
+	builtin = append(builtin, ReservedFlag{
+		Names:  []string{completeArg[1:]},
+		Usage:  "Prints the completion candidates of the flag value",
+		Hidden: true,
+		Handler: func(name string) error {
+			return fb.completeValue(os.Stdout, name)
+		},
+	})
+
+	return fb.addReservedFlags(append(builtin, fb.opts.reservedFlags...))
+}
+
+// addReservedFlags defines the reserved flags with a handler in the flag set and remembers all of them.
+func (fb *flagBuilder) addReservedFlags(rfs []ReservedFlag) error {
+	for _, rf := range rfs {
+		for _, name := range rf.Names {
+			if err := fb.checkReserved(name); err != nil {
+				return err
+			}
+			if err := fb.checkDuplicate(name); err != nil {
+				return err
+			}
+		}
+		v := &reservedValue{isBool: rf.IsBool}
+		fb.reserved = append(fb.reserved, reservedFlag{rf, v, rf.Handler != nil && !rf.Hidden})
+		if rf.Handler == nil {
+			continue
+		}
+		for _, name := range rf.Names {
+			fb.flagSet.Var(v, name, rf.Usage)
+		}
+	}
+	return nil
+}
+
+// runReservedHandlers runs the handlers of the reserved flags used on the command line in the order of their definition.
+func (fb *flagBuilder) runReservedHandlers() error {
+	for _, rf := range fb.reserved {
+		if rf.Handler == nil || !rf.value.isSet {
+			continue
+		}
+		if err := rf.Handler(rf.value.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fb *flagBuilder) checkReserved(name string) error {
+	for _, rf := range fb.reserved {
+		for _, n := range rf.Names {
+			if n == name {
+				return fmt.Errorf("reserved flag -%s overwriting not allowed", name)
+			}
+		}
+	}
+	return nil
+}
+
+// checkDuplicate checks that the flag has not been defined by another field yet.
+func (fb *flagBuilder) checkDuplicate(name string) error {
+	if fb.flagSet.Lookup(name) == nil {
+		return nil
+	}
+	if f, ok := fb.lookupFlagInfo(name); ok {
+		return fmt.Errorf("flag -%s already defined by the field %s", name, f.fieldPath)
+	}
+	// the flag is defined by the caller's flag set or by a reserved flag
+	return fmt.Errorf("flag -%s defined more than once", name)
+}
+
+// checkDuplicateShort checks that neither the name nor the short name of the flag is used as the name or the short name
+// of another flag, because the backends and the short flag grouping cannot tell such flags apart.
+func (fb *flagBuilder) checkDuplicateShort(name, short string) error {
+	for _, f := range fb.flags {
+		if f.short != "" && f.short == name {
+			return fmt.Errorf("flag -%s already defined as the short name by the field %s", name, f.fieldPath)
+		}
+	}
+	if short == "" || short == name {
+		return nil
+	}
+	for _, f := range fb.flags {
+		if f.short == short || f.name == short {
+			return fmt.Errorf("short name -%s of the flag -%s already defined by the field %s", short, name, f.fieldPath)
+		}
+	}
+	if err := fb.checkReserved(short); err != nil {
+		return err
+	}
+	if fb.flagSet.Lookup(short) != nil {
+		return fmt.Errorf("short name -%s of the flag -%s defined more than once", short, name)
+	}
+	return nil
+}
+
+// reservedValue returns the value of the reserved flag with the given name, or an empty string if it was not used.
+func (fb *flagBuilder) reservedValue(name string) string {
+	for _, rf := range fb.reserved {
+		if rf.value.isSet && contains(rf.Names, name) {
+			return rf.value.value
+		}
+	}
+	return ""
+}
+
+func (fb *flagBuilder) isHidden(name string) bool {
+	for _, rf := range fb.reserved {
+		for _, n := range rf.Names {
+			if n == name {
+				return rf.Hidden
+			}
+		}
+	}
+	return false
+}
+
+// builtinFlags returns the metadata of the visible reserved flags which should be documented and completed as well.
+func (fb *flagBuilder) builtinFlags() []flagInfo {
+	var flags []flagInfo
+	for _, rf := range fb.reserved {
+		if !rf.isDocumented {
+			continue
+		}
+		for _, name := range rf.Names {
+			fi := flagInfo{flagMetadata: flagMetadata{name: name, usage: rf.Usage}, isBool: rf.IsBool}
+			if !rf.IsBool {
+				fi.valueName = "value"
+			}
+			flags = append(flags, fi)
+		}
+	}
+	return flags
+}
@@ -0,0 +1,86 @@
+package easyflag
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/matusvla/easyflag/internal/cmdline"
+)
+
+const (
+	responseFilePrefix   = "@"
+	maxResponseFileDepth = 16
+)
+
+// WithResponseFiles enables the response files: an argument starting with the @ character, e.g. @args.txt,
+// is replaced by the arguments read from the file. The arguments in the file are separated by whitespace
+// including newlines and they can be quoted by the double or single quotes to contain whitespace, e.g. "-name=a b".
+// The response files can reference other response files, the cycles are reported as errors. The arguments after
+// the -- terminator and the values of the flags passed as separate arguments, e.g. -to @alice, are not expanded.
+// This is useful for the very long argument lists and the Windows toolchains.
+func WithResponseFiles() Option {
+	return func(o *options) {
+		o.responseFiles = true
+	}
+}
+
+// expandResponseFiles replaces the @file arguments by the arguments read from the files. It reports whether
+// the -- terminator was found, possibly in a response file, so that the following arguments are not expanded.
+// The values of the flags passed as separate arguments, e.g. -to @alice, are not expanded either.
+// The stack holds the absolute paths of the response files being expanded to detect the cycles.
+func (fb *flagBuilder) expandResponseFiles(args []string, stack []string) (_ []string, terminated bool, _ error) {
+	expanded := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(expanded, args[i:]...), true, nil
+		}
+		if path, ok := cutPrefix(arg, responseFilePrefix); ok && path != "" {
+			fileArgs, terminated, err := fb.readResponseFile(path, stack)
+			if err != nil {
+				return nil, false, err
+			}
+			expanded = append(expanded, fileArgs...)
+			if terminated {
+				return append(expanded, args[i+1:]...), true, nil
+			}
+			if len(fileArgs) == 0 {
+				continue
+			}
+		} else {
+			expanded = append(expanded, arg)
+		}
+		// the value of the flag passed as a separate argument is kept as it is, even if it starts with @
+		if name, hasValue, ok := flagArgName(expanded[len(expanded)-1]); ok && !hasValue && i+1 < len(args) {
+			if f := fb.flagSet.Lookup(name); f != nil && !isBoolFlag(f) {
+				i++
+				expanded = append(expanded, args[i])
+			}
+		}
+	}
+	return expanded, false, nil
+}
+
+// readResponseFile reads the arguments from the response file expanding the nested response files.
+func (fb *flagBuilder) readResponseFile(path string, stack []string) (_ []string, terminated bool, _ error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, false, fmt.Errorf("response file %s: %w", path, err)
+	}
+	if contains(stack, abs) {
+		return nil, false, fmt.Errorf("response file %s includes itself", path)
+	}
+	if len(stack) >= maxResponseFileDepth {
+		return nil, false, fmt.Errorf("response file %s nested too deeply", path)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, fmt.Errorf("response file %s: %w", path, err)
+	}
+	fileArgs, err := cmdline.Split(string(b))
+	if err != nil {
+		return nil, false, fmt.Errorf("response file %s: %w", path, err)
+	}
+	return fb.expandResponseFiles(fileArgs, append(stack, abs))
+}
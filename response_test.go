@@ -0,0 +1,90 @@
+package easyflag
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandResponseFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	common := write("common.txt", "-b=2\n-c=3")
+	main := write("main.txt", "-a=1 @"+common)
+	terminated := write("terminated.txt", "-a=1 -- @x")
+	cycle := filepath.Join(dir, "cycle.txt")
+	write("cycle.txt", "@"+cycle)
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr string
+	}{
+		{name: "no response files", args: []string{"-a=1", "x@y"}, want: []string{"-a=1", "x@y"}},
+		{name: "nested response files", args: []string{"@" + main, "-d=4"}, want: []string{"-a=1", "-b=2", "-c=3", "-d=4"}},
+		{name: "bare @", args: []string{"@"}, want: []string{"@"}},
+		{name: "terminator", args: []string{"--", "@" + main}, want: []string{"--", "@" + main}},
+		{
+			name: "terminator in a response file",
+			args: []string{"@" + terminated, "@" + main},
+			want: []string{"-a=1", "--", "@x", "@" + main},
+		},
+		{name: "cycle", args: []string{"@" + cycle}, wantErr: "response file " + cycle + " includes itself"},
+		{
+			name:    "missing file",
+			args:    []string{"@" + filepath.Join(dir, "missing.txt")},
+			wantErr: "response file " + filepath.Join(dir, "missing.txt") + ": open " + filepath.Join(dir, "missing.txt") + ": no such file or directory",
+		},
+	}
+	fb, err := newFlagBuilder(newOptions(nil))
+	assert.NoError(t, err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := fb.expandResponseFiles(tt.args, nil)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseAndLoad_responseFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "args.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("-name \"a b\"\n-count=3\n"), 0o600))
+	type respParams struct {
+		Name  string `flag:"name|Name"`
+		Count int    `flag:"count|Count"`
+		To    string `flag:"to|Recipient"`
+		Debug bool   `flag:"debug|Debug output"`
+	}
+
+	var p respParams
+	err := ParseAndLoad(&p, WithResponseFiles(), WithArgsSource(StaticArgs{"program", "@" + path, "-count=4"}),
+		WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, respParams{Name: "a b", Count: 4}, p)
+
+	// the value of a flag is not a response file, unlike the argument after a boolean flag
+	var values respParams
+	res, err := Parse(&values, WithResponseFiles(), WithArgsSource(StaticArgs{"program", "-to", "@alice", "-debug", "@" + path}),
+		WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, respParams{Name: "a b", Count: 3, To: "@alice", Debug: true}, values)
+	assert.Empty(t, res.Args)
+
+	var disabled respParams
+	res, err = Parse(&disabled, WithArgsSource(StaticArgs{"program", "@" + path}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"@" + path}, res.Args)
+}
@@ -0,0 +1,109 @@
+package easyflag
+
+import (
+	"io"
+)
+
+// Source is the source of a flag value.
+type Source int
+
+// The sources of the flag values. The command line takes precedence over the environment,
+// which takes precedence over the secret store, which takes precedence over the remote sources,
+// which take precedence over the configuration file, which takes precedence over the default value.
+const (
+	SourceDefault     Source = iota // the default value from the field tag or the pre-populated structure
+	SourceEnv                       // the environment variable named by the env field tag
+	SourceCommandLine               // the command line argument
+	SourceConfigFile                // the configuration file, see the WithConfigFlag option
+	SourceSecretStore               // the secret store, see the SecretProvider type
+	SourceRemote                    // the remote source, see the RemoteSource type
+	SourcePrompt                    // the interactive prompt, see the WithPrompt option
+)
+
+// String returns the name of the source.
+func (s Source) String() string {
+	switch s {
+	case SourceEnv:
+		return "env"
+	case SourceCommandLine:
+		return "command line"
+	case SourceConfigFile:
+		return "config file"
+	case SourceSecretStore:
+		return "secret store"
+	case SourceRemote:
+		return "remote"
+	case SourcePrompt:
+		return "prompt"
+	default:
+		return "default"
+	}
+}
+
+// Result holds the details of a successful parsing returned by the Parse function.
+type Result struct {
+	// Params is the params structure passed to the Parse function, filled by the flag values.
+	Params interface{}
+	// Args are the non-flag arguments remaining after the flags, e.g. the file names.
+	Args []string
+	// Sources are the sources of the values of all the flags of the params structure.
+	Sources map[string]Source
+	// Unknown are the flags not defined by the params structure with their values, see the WithUnknownFlags option.
+	Unknown []string
+
+	fb *flagBuilder
+}
+
+// explainSource describes the source of the flag value, including the environment variable, the configuration file
+// or the secret reference setting it.
+func (fb *flagBuilder) explainSource(f flagInfo) string {
+	switch s := fb.sources[f.name]; s {
+	case SourceEnv:
+		return s.String() + " " + f.env
+	case SourceConfigFile:
+		return s.String() + " " + fb.configPath
+	case SourceSecretStore:
+		return s.String() + " " + f.secretRef
+	default:
+		return s.String()
+	}
+}
+
+func newResult(params interface{}, fb *flagBuilder) *Result {
+	sources := make(map[string]Source, len(fb.flags))
+	for _, f := range fb.flags {
+		sources[f.name] = fb.sources[f.name]
+	}
+	return &Result{
+		Params:  params,
+		Args:    fb.remainingArgs(),
+		Sources: sources,
+		Unknown: fb.unknown,
+		fb:      fb,
+	}
+}
+
+// IsSet reports whether the flag with the given name was set on the command line or by the environment,
+// i.e. its value is not the default one.
+func (r *Result) IsSet(name string) bool {
+	return r.Sources[name] != SourceDefault
+}
+
+/*
+Explain writes the values of all the flags together with their sources to w, one flag per line, e.g.
+
+	host = "localhost" (default)
+	port = 8080 (env APP_PORT)
+	v = true (command line)
+
+The values of the secret flags are masked. It helps debugging the layered configuration.
+*/
+func (r *Result) Explain(w io.Writer) error {
+	return r.fb.writeConfig(w, true)
+}
+
+// WriteUsage writes the usage message of the parsed flags to w.
+// It can be used e.g. to print the usage after a failed validation of the remaining arguments.
+func (r *Result) WriteUsage(w io.Writer) {
+	r.fb.writeUsage(w)
+}
@@ -0,0 +1,67 @@
+package easyflag
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	type resultParams struct {
+		Host string `flag:"host|Server host|localhost"`
+		Port int    `flag:"port|Server port|80" env:"PORT"`
+		Verb bool   `flag:"v|Verbose output"`
+	}
+	os.Args = []string{"executable_name", "-v", "a.txt", "b.txt"}
+	var p resultParams
+	res, err := Parse(&p, WithEnvSource(MapEnv{"PORT": "8080"}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.Equal(t, &p, res.Params)
+	assert.Equal(t, resultParams{Host: "localhost", Port: 8080, Verb: true}, p)
+	assert.Equal(t, []string{"a.txt", "b.txt"}, res.Args)
+	assert.Equal(t, map[string]Source{"host": SourceDefault, "port": SourceEnv, "v": SourceCommandLine}, res.Sources)
+	assert.True(t, res.IsSet("v"))
+	assert.True(t, res.IsSet("port"))
+	assert.False(t, res.IsSet("host"))
+	assert.False(t, res.IsSet("undefined"))
+
+	var buf bytes.Buffer
+	res.WriteUsage(&buf)
+	assert.Contains(t, buf.String(), "-host string")
+
+	os.Args = []string{"executable_name", "-port=x"}
+	res, err = Parse(&p, WithOutput(io.Discard))
+	assert.Error(t, err)
+	assert.Nil(t, res)
+	assert.Equal(t, resultParams{}, p)
+}
+
+func TestSource_String(t *testing.T) {
+	assert.Equal(t, "default", SourceDefault.String())
+	assert.Equal(t, "env", SourceEnv.String())
+	assert.Equal(t, "command line", SourceCommandLine.String())
+}
+
+func TestResult_Explain(t *testing.T) {
+	type explainParams struct {
+		Host     string `flag:"host|Server host|localhost"`
+		Port     int    `flag:"port|Server port|80" env:"PORT"`
+		Password string `flag:"password|Password||secret"`
+		Verb     bool   `flag:"v|Verbose output"`
+	}
+	os.Args = []string{"executable_name", "-v", "-password=hunter2"}
+	var p explainParams
+	res, err := Parse(&p, WithEnvPrefix("APP_"), WithEnvSource(MapEnv{"APP_PORT": "8080"}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	var buf bytes.Buffer
+	assert.NoError(t, res.Explain(&buf))
+	want := `host = "localhost" (default)
+port = 8080 (env APP_PORT)
+password = *** (command line)
+v = true (command line)
+`
+	assert.Equal(t, want, buf.String())
+}
@@ -0,0 +1,142 @@
+package easyflag
+
+import (
+	"context"
+	"flag"
+	"fmt"
+)
+
+/*
+SecretProvider resolves the secret references of the flags from a secret store, e.g. HashiCorp Vault.
+The github.com/matusvla/easyflag/vaultsecrets module provides the implementation for HashiCorp Vault.
+
+A flag refers to a secret by the secretRef field tag, e.g.
+
+	Password string `flag:"db-password|Database password||secret" env:"DB_PASSWORD" secretRef:"db/password"`
+
+The secret store is consulted only if the flag is neither used on the command line nor set by the environment,
+so the command line takes precedence over the environment, which takes precedence over the secret store,
+which takes precedence over the remote sources, the configuration file and the default value.
+*/
+type SecretProvider interface {
+	// Secret returns the value of the secret with the given reference. A missing secret is reported as an error.
+	Secret(ctx context.Context, ref string) (string, error)
+}
+
+// WithSecretProvider sets the provider resolving the secret references of the flags defined by the secretRef
+// field tag. Without a provider, the secretRef tags are ignored. See the SecretProvider type for more details.
+func WithSecretProvider(p SecretProvider) Option {
+	return func(o *options) {
+		o.secretProvider = p
+	}
+}
+
+// WithContext sets the context passed to the secret provider, the remote sources and the completion providers,
+// e.g. with a timeout, so that an unreachable secret store or remote source does not block the parsing forever:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	err := easyflag.ParseAndLoad(&p, easyflag.WithSecretProvider(provider), easyflag.WithContext(ctx))
+//
+// The context.Background is used by default.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// context returns the context of the lookups set by the WithContext option.
+func (o options) context() context.Context {
+	if o.ctx != nil {
+		return o.ctx
+	}
+	return context.Background()
+}
+
+// loadSecrets sets the flags not set on the command line or by the environment from the secret provider.
+func (fb *flagBuilder) loadSecrets() error {
+	if fb.opts.secretProvider == nil {
+		return nil
+	}
+	ctx := fb.opts.context()
+	for _, f := range fb.flags {
+		if _, used := fb.sources[f.name]; f.secretRef == "" || used {
+			continue
+		}
+		val, err := fb.opts.secretProvider.Secret(ctx, f.secretRef)
+		if err != nil {
+			return fmt.Errorf("resolving the secret %s for the flag -%s: %w", f.secretRef, f.name, err)
+		}
+		if err := fb.flagSet.Set(f.name, val); err != nil {
+			// the value is never shown, because it is a secret
+			return fmt.Errorf("invalid value %q of the secret %s for the flag -%s: %w", maskedValue, f.secretRef, f.name, err)
+		}
+		fb.sources[f.name] = SourceSecretStore
+	}
+	return nil
+}
+
+// displayed returns the value of the flag to be shown to the user, i.e. the masked value in case of a secret flag.
+func (fm flagMetadata) displayed(val string) string {
+	if fm.isSecret {
+		return maskedValue
+	}
+	return val
+}
+
+// displayedDefault returns the default value of the flag to be shown in the usage message and the documentation.
+func (f flagInfo) displayedDefault() string {
+	if f.defaultVal == "" {
+		return ""
+	}
+	return f.displayed(f.defaultVal)
+}
+
+// maskedFlagValue wraps the value of a secret flag while the command line is parsed. It defers the error of setting
+// the value, so that the native flag package or the backend does not include the value in the error message.
+type maskedFlagValue struct {
+	flag.Value
+	err error
+}
+
+func (v *maskedFlagValue) Set(s string) error {
+	if err := v.Value.Set(s); err != nil && v.err == nil {
+		v.err = err
+	}
+	return nil
+}
+
+func (v *maskedFlagValue) IsBoolFlag() bool {
+	bf, ok := v.Value.(interface{ IsBoolFlag() bool })
+	return ok && bf.IsBoolFlag()
+}
+
+// wrapSecretValues wraps the values of the secret flags by the maskedFlagValue and returns the function restoring them.
+func (fb *flagBuilder) wrapSecretValues() func() {
+	var wrapped []*flag.Flag
+	for _, f := range fb.flags {
+		if f.isSecret {
+			fl := fb.flagSet.Lookup(f.name)
+			fl.Value = &maskedFlagValue{Value: fl.Value}
+			wrapped = append(wrapped, fl)
+		}
+	}
+	return func() {
+		for _, fl := range wrapped {
+			fl.Value = fl.Value.(*maskedFlagValue).Value
+		}
+	}
+}
+
+// secretValueError returns the first error deferred by the wrapped values of the secret flags with the masked value.
+// The error is reported the same way as the native flag package does it.
+func (fb *flagBuilder) secretValueError() error {
+	for _, f := range fb.flags {
+		if sv, ok := fb.flagSet.Lookup(f.name).Value.(*maskedFlagValue); ok && sv.err != nil {
+			err := fmt.Errorf("invalid value %q for flag -%s: %w", maskedValue, f.name, sv.err)
+			fb.reportParseError(err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package easyflag
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mapSecrets map[string]string
+
+func (s mapSecrets) Secret(_ context.Context, ref string) (string, error) {
+	v, ok := s[ref]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+	return v, nil
+}
+
+// ctxSecrets is a secret provider failing if the context is done, e.g. on a timeout.
+type ctxSecrets struct{}
+
+func (ctxSecrets) Secret(ctx context.Context, _ string) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestParse_secretProviderContext(t *testing.T) {
+	var p struct {
+		Password string `flag:"db-password|Database password||secret" secretRef:"db/password"`
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := Parse(&p, WithArgsSource(StaticArgs{"program"}), WithSecretProvider(ctxSecrets{}), WithContext(ctx),
+		WithOutput(io.Discard))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestParse_secretProvider(t *testing.T) {
+	type secretParams struct {
+		Password string `flag:"db-password|Database password||secret" env:"DB_PASSWORD" secretRef:"db/password"`
+		Port     int    `flag:"port|Server port|80" secretRef:"db/port"`
+		Host     string `flag:"host|Server host|localhost"`
+	}
+	tests := []struct {
+		name        string
+		args        []string
+		env         MapEnv
+		provider    SecretProvider
+		want        secretParams
+		wantSources map[string]Source
+		wantErr     string
+	}{
+		{
+			name:        "secret store",
+			args:        []string{"program"},
+			provider:    mapSecrets{"db/password": "s3cret", "db/port": "5432"},
+			want:        secretParams{Password: "s3cret", Port: 5432, Host: "localhost"},
+			wantSources: map[string]Source{"db-password": SourceSecretStore, "port": SourceSecretStore, "host": SourceDefault},
+		},
+		{
+			name:        "command line and environment take precedence",
+			args:        []string{"program", "-port=1"},
+			env:         MapEnv{"DB_PASSWORD": "env"},
+			provider:    mapSecrets{},
+			want:        secretParams{Password: "env", Port: 1, Host: "localhost"},
+			wantSources: map[string]Source{"db-password": SourceEnv, "port": SourceCommandLine, "host": SourceDefault},
+		},
+		{
+			name:        "no provider",
+			args:        []string{"program"},
+			want:        secretParams{Port: 80, Host: "localhost"},
+			wantSources: map[string]Source{"db-password": SourceDefault, "port": SourceDefault, "host": SourceDefault},
+		},
+		{
+			name:     "missing secret",
+			args:     []string{"program", "-db-password=x"},
+			provider: mapSecrets{},
+			wantErr:  "resolving the secret db/port for the flag -port: secret not found",
+		},
+		{
+			name:     "invalid value",
+			args:     []string{"program"},
+			provider: mapSecrets{"db/password": "s3cret", "db/port": "abc"},
+			wantErr:  `invalid value "***" of the secret db/port for the flag -port: parse error`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p secretParams
+			opts := []Option{WithArgsSource(StaticArgs(tt.args)), WithEnvSource(tt.env), WithOutput(io.Discard), WithoutExit()}
+			if tt.provider != nil {
+				opts = append(opts, WithSecretProvider(tt.provider))
+			}
+			res, err := Parse(&p, opts...)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+			assert.Equal(t, tt.wantSources, res.Sources)
+		})
+	}
+}
+
+func TestParse_secretMasking(t *testing.T) {
+	type maskedParams struct {
+		Token string `flag:"token|API token|default-token|secret" env:"TOKEN"`
+		Pin   int    `flag:"pin|PIN code|1234|secret" env:"PIN"`
+	}
+	tests := []struct {
+		name       string
+		args       []string
+		env        MapEnv
+		wantErr    string
+		wantOutput []string
+	}{
+		{
+			name:       "help",
+			args:       []string{"program", "-h"},
+			wantErr:    "flag: help requested",
+			wantOutput: []string{"API token (default ***) (env TOKEN)", "PIN code (default ***) (env PIN)"},
+		},
+		{
+			name:       "invalid command line value",
+			args:       []string{"program", "-pin=98x6"},
+			wantErr:    `invalid value "***" for flag -pin: parse error`,
+			wantOutput: []string{`invalid value "***" for flag -pin: parse error`, "Usage:"},
+		},
+		{
+			name:    "invalid environment value",
+			args:    []string{"program"},
+			env:     MapEnv{"PIN": "98x6"},
+			wantErr: `invalid value "***" of the environment variable PIN for the flag -pin: parse error`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p maskedParams
+			var buf bytes.Buffer
+			_, err := Parse(&p, WithArgsSource(StaticArgs(tt.args)), WithEnvSource(tt.env), WithOutput(&buf),
+				WithColor(ColorNever), WithoutExit())
+			assert.EqualError(t, err, tt.wantErr)
+			for _, w := range tt.wantOutput {
+				assert.Contains(t, buf.String(), w)
+			}
+			assert.NotContains(t, buf.String(), "98x6")
+			assert.NotContains(t, buf.String(), "default-token")
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package easyflag
+
+import (
+	"strings"
+)
+
+// Set is a flag field type accumulating the values of a repeated flag into a deduplicated set of strings.
+// It is intended for the tag or label selection flags, e.g. -label=a -label=b or -label=a,b.
+//
+// The values are kept in the order of their first occurrence. A repeated value is ignored and reported as a warning.
+// The values passed on the command line replace the default ones defined in the field tag.
+type Set struct {
+	values []string
+	index  map[string]struct{}
+}
+
+// String returns the values of the set separated by commas.
+func (s *Set) String() string {
+	return strings.Join(s.values, ",")
+}
+
+// Set adds one or more comma separated values to the set. It implements the flag.Value interface.
+func (s *Set) Set(value string) error {
+	for _, v := range splitSetValues(value) {
+		s.add(v)
+	}
+	return nil
+}
+
+// Values returns a copy of the values in the set in the order of their first occurrence.
+func (s *Set) Values() []string {
+	return append([]string(nil), s.values...)
+}
+
+// Contains reports whether the set contains the given value.
+func (s *Set) Contains(value string) bool {
+	_, ok := s.index[value]
+	return ok
+}
+
+// Len returns the number of values in the set.
+func (s *Set) Len() int {
+	return len(s.values)
+}
+
+func (s *Set) add(value string) bool {
+	if s.index == nil {
+		s.index = make(map[string]struct{})
+	}
+	if _, ok := s.index[value]; ok {
+		return false
+	}
+	s.index[value] = struct{}{}
+	s.values = append(s.values, value)
+	return true
+}
+
+func splitSetValues(value string) []string {
+	var result []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// setValue is the flag.Value used for the Set fields.
+// It replaces the default values by the first value passed on the command line and reports duplicates.
+type setValue struct {
+	set         *Set
+	isDefault   bool
+	onDuplicate func(value string)
+	choices     []string
+	msgs        *Messages
+}
+
+func (v *setValue) String() string {
+	if v.set == nil {
+		return ""
+	}
+	return v.set.String()
+}
+
+func (v *setValue) Set(value string) error {
+	items := splitSetValues(value)
+	for _, item := range items {
+		if err := checkChoice(v.choices, item, v.msgs.ValueNotAllowed); err != nil {
+			return err
+		}
+	}
+	if v.isDefault {
+		*v.set = Set{}
+		v.isDefault = false
+	}
+	for _, item := range items {
+		if !v.set.add(item) && v.onDuplicate != nil {
+			v.onDuplicate(item)
+		}
+	}
+	return nil
+}
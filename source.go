@@ -0,0 +1,46 @@
+package easyflag
+
+import "os"
+
+// ArgsSource provides the command line arguments of the program. Just like os.Args, the arguments start
+// with the program name. It allows using the package in the runtimes without the usual process arguments,
+// e.g. in a browser (GOOS=js) where the arguments can be taken from the page URL.
+type ArgsSource interface {
+	Args() []string
+}
+
+// EnvSource provides the environment variables of the program.
+type EnvSource interface {
+	LookupEnv(key string) (string, bool)
+}
+
+// StaticArgs is an ArgsSource returning the fixed arguments. The first argument is the program name.
+type StaticArgs []string
+
+// Args returns the arguments.
+func (a StaticArgs) Args() []string {
+	return a
+}
+
+// MapEnv is an EnvSource returning the variables from the map.
+type MapEnv map[string]string
+
+// LookupEnv returns the value of the variable and whether it is present in the map.
+func (e MapEnv) LookupEnv(key string) (string, bool) {
+	v, ok := e[key]
+	return v, ok
+}
+
+// osArgs is the default ArgsSource returning os.Args.
+type osArgs struct{}
+
+func (osArgs) Args() []string {
+	return os.Args
+}
+
+// osEnv is the default EnvSource returning the variables of the process environment.
+type osEnv struct{}
+
+func (osEnv) LookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
@@ -0,0 +1,18 @@
+module github.com/matusvla/easyflag/ssmsource
+
+go 1.18
+
+require (
+	github.com/aws/aws-sdk-go-v2 v1.21.0
+	github.com/aws/aws-sdk-go-v2/service/ssm v1.37.5
+	github.com/matusvla/easyflag v0.0.0
+)
+
+require (
+	github.com/aws/aws-sdk-go-v2/internal/configsources v1.1.41 // indirect
+	github.com/aws/aws-sdk-go-v2/internal/endpoints/v2 v2.4.35 // indirect
+	github.com/aws/smithy-go v1.14.2 // indirect
+	github.com/jmespath/go-jmespath v0.4.0 // indirect
+)
+
+replace github.com/matusvla/easyflag => ../
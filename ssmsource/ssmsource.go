@@ -0,0 +1,53 @@
+/*
+Package ssmsource provides the easyflag remote source reading the flag values from the AWS SSM Parameter Store:
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	[...]
+	var p params
+	err = easyflag.ParseAndLoad(&p, easyflag.WithRemoteSource(ssmsource.New(ssm.NewFromConfig(cfg), "/my-service/")))
+
+The name of the parameter is the prefix followed by the flag name, e.g. /my-service/db-host for the db-host flag.
+The SecureString parameters are decrypted.
+*/
+package ssmsource
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/matusvla/easyflag"
+)
+
+// Client is the subset of the SSM client used by the source, satisfied by *ssm.Client.
+type Client interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+type source struct {
+	client Client
+	prefix string
+}
+
+// New returns the easyflag remote source reading the parameters named by the prefix followed by the flag name.
+func New(client Client, prefix string) easyflag.RemoteSource {
+	return source{client: client, prefix: prefix}
+}
+
+// Lookup returns the value of the parameter named by the prefix followed by the key and whether it exists.
+func (s source) Lookup(ctx context.Context, key string) (string, bool, error) {
+	out, err := s.client.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(s.prefix + key),
+		WithDecryption: aws.Bool(true),
+	})
+	var notFound *types.ParameterNotFound
+	if errors.As(err, &notFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return aws.ToString(out.Parameter.Value), true, nil
+}
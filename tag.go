@@ -0,0 +1,95 @@
+package easyflag
+
+import "strings"
+
+// FlagMetadata is the flag metadata defined by the flag field tag, see the ParseTag function.
+type FlagMetadata struct {
+	Name     string // empty if AutoName is set
+	AutoName bool   // the name is derived from the field name by the naming strategy, see the WithNamingStrategy option
+	Usage    string
+	Default  string // empty for the required flags, see IgnoredDefault
+	// IgnoredDefault is the default value defined by the tag of a required flag, which is never used.
+	IgnoredDefault string
+	Required       bool
+	Choices        []string
+	Priority       int    // the flags with a higher priority are listed first in the usage message
+	Placeholder    string // the name of the flag value shown in the usage message instead of the value type
+	Secret         bool
+	Reloadable     bool
+	Prompted       bool
+	Encoding       string // the encoding of the []byte values, hex or base64
+	JSON           bool
+	FileMode       string // the mode in which the File values are opened, read, create or append
+	Expand         bool
+	PathCheck      string // mustExist, mustBeDir or mustBeFile
+	URL            bool
+	URLSchemes     []string // the schemes allowed by the url option, any if empty
+	NotBlank       bool
+	DefaultFrom    string            // the flag whose resolved value is used if the flag is not set by any source
+	Whitespace     *WhitespacePolicy // overrides the global whitespace policy if set
+}
+
+/*
+ParseTag parses the value of the flag field tag by the same grammar which is used when the flags are set up,
+e.g. ParseTag("port|Server port|80|priority=1") or ParseTag("name=port,usage=Server port,default=80"). It lets
+the tools such as linters or documentation generators consume the tags without reimplementing the grammar.
+
+The tag is parsed on its own, so the separate usage, default and required field tags are not taken into account,
+and the ,auto name is reported by the AutoName field instead of being derived from the field name.
+All the errors are the TagSyntaxError errors with an empty field path.
+*/
+func ParseTag(tag string) (FlagMetadata, error) {
+	metadataStr, autoName, reason := cutAutoName(tag)
+	if reason != "" {
+		return FlagMetadata{}, &TagSyntaxError{Tag: tag, Reason: reason}
+	}
+	if autoName {
+		if strings.HasPrefix(metadataStr, ",") {
+			metadataStr = nameKey + "=" + autoNameValue[1:] + metadataStr
+		} else {
+			metadataStr = autoNameValue[1:] + metadataStr
+		}
+	}
+	fm, err := parseFlagMetadata(metadataStr)
+	if err != nil {
+		return FlagMetadata{}, &TagSyntaxError{Tag: tag, Reason: err.Error()}
+	}
+	if autoName {
+		fm.name = ""
+	} else if reason := checkFlagName(fm.name); reason != "" {
+		return FlagMetadata{}, &TagSyntaxError{Tag: tag, Reason: reason}
+	}
+	return fm.export(autoName), nil
+}
+
+// export returns the exported form of the flag metadata.
+func (fm flagMetadata) export(autoName bool) FlagMetadata {
+	var urlSchemes []string
+	if fm.urlSchemes != "" {
+		urlSchemes = strings.Fields(fm.urlSchemes)
+	}
+	return FlagMetadata{
+		Name:           fm.name,
+		AutoName:       autoName,
+		Usage:          fm.usage,
+		Default:        fm.defaultVal,
+		IgnoredDefault: fm.ignoredDefault,
+		Required:       fm.isRequired,
+		Choices:        fm.choices,
+		Priority:       fm.priority,
+		Placeholder:    fm.placeholder,
+		Secret:         fm.isSecret,
+		Reloadable:     fm.isReloadable,
+		Prompted:       fm.isPrompted,
+		Encoding:       fm.encoding,
+		JSON:           fm.isJSON,
+		FileMode:       fm.fileMode,
+		Expand:         fm.expand,
+		PathCheck:      fm.pathCheck,
+		URL:            fm.isURL,
+		URLSchemes:     urlSchemes,
+		NotBlank:       fm.isNotBlank,
+		DefaultFrom:    fm.defaultFrom,
+		Whitespace:     fm.whitespace,
+	}
+}
@@ -0,0 +1,116 @@
+package easyflag
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    FlagMetadata
+		wantErr string
+	}{
+		{tag: "port", want: FlagMetadata{Name: "port"}},
+		{tag: " port | Server port | 80 ", want: FlagMetadata{Name: "port", Usage: "Server port", Default: "80"}},
+		{
+			tag:  "in|Input file|a.txt|required, secret,,priority=2",
+			want: FlagMetadata{Name: "in", Usage: "Input file", IgnoredDefault: "a.txt", Required: true, Secret: true, Priority: 2},
+		},
+		{
+			tag: "fmt|Format: json\\|yaml|json|choices=json yaml,placeholder=FORMAT,trim",
+			want: FlagMetadata{
+				Name:        "fmt",
+				Usage:       "Format: json|yaml",
+				Default:     "json",
+				Choices:     []string{"json", "yaml"},
+				Placeholder: "FORMAT",
+				Whitespace:  policyPtr(TrimWhitespace),
+			},
+		},
+		{
+			tag:  "web|Web page||url=http https,notblank,expand,defaultFrom=site",
+			want: FlagMetadata{Name: "web", Usage: "Web page", URL: true, URLSchemes: []string{"http", "https"}, NotBlank: true, Expand: true, DefaultFrom: "site"},
+		},
+		{
+			tag:  "name=key, usage=API key\\, base64 encoded, base64, reloadable",
+			want: FlagMetadata{Name: "key", Usage: "API key, base64 encoded", Encoding: "base64", Reloadable: true},
+		},
+		{tag: ",auto|Server port|80", want: FlagMetadata{AutoName: true, Usage: "Server port", Default: "80"}},
+		{tag: ",auto,usage=Server port", want: FlagMetadata{AutoName: true, Usage: "Server port"}},
+		{tag: "", wantErr: `invalid flag tag "": empty flag name`},
+		{tag: " |Usage", wantErr: `invalid flag tag " |Usage": empty flag name`},
+		{tag: "-port", wantErr: `invalid flag tag "-port": flag name "-port" starts with a hyphen`},
+		{tag: "my port", wantErr: `invalid flag tag "my port": flag name "my port" contains a space or an equals sign`},
+		{
+			tag:     "fmt|json|yaml|json|required",
+			wantErr: `invalid flag tag "fmt|json|yaml|json|required": too many metadata parts, the | characters of the usage must be escaped as \|`,
+		},
+		{tag: "in|||whatever", wantErr: `invalid flag tag "in|||whatever": unsupported value "whatever" in the fourth metadata part`},
+		{tag: "in|||priority=high", wantErr: `invalid flag tag "in|||priority=high": invalid priority "high" in the fourth metadata part`},
+		{tag: "name=in,name=out", wantErr: `invalid flag tag "name=in,name=out": duplicate key "name" in the flag metadata`},
+		{tag: ",automatic", wantErr: `invalid flag tag ",automatic": the ,auto name must be followed by a comma, a pipe or the end of the tag`},
+		{tag: ",auto,name=in", wantErr: `invalid flag tag ",auto,name=in": duplicate key "name" in the flag metadata`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, err := ParseTag(tt.tag)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				var tse *TagSyntaxError
+				assert.True(t, errors.As(err, &tse))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func FuzzParseTag(f *testing.F) {
+	for _, tag := range []string{
+		"port|Server port|80",
+		"in|Input file|a.txt|required,secret",
+		"fmt|Format: json\\|yaml|json|choices=json yaml,priority=-1",
+		"name=key,usage=API key\\, base64,default=,base64",
+		",auto|Usage",
+		",auto,usage=Usage",
+		",automatic",
+		"a|b|c|d|e",
+		"web|||url=https,mode=read,mustExist",
+		"\\",
+		"|||,,,",
+	} {
+		f.Add(tag)
+	}
+	f.Fuzz(func(t *testing.T, tag string) {
+		fm, err := ParseTag(tag)
+		if err != nil {
+			var tse *TagSyntaxError
+			if !errors.As(err, &tse) || tse.Tag != tag {
+				t.Fatalf("ParseTag(%q) returned an unexpected error %#v", tag, err)
+			}
+			return
+		}
+		rest, isAuto := cutPrefix(tag, autoNameValue)
+		if fm.AutoName != (isAuto && (rest == "" || rest[0] == ',' || rest[0] == '|')) {
+			t.Fatalf("ParseTag(%q) returned the auto name %t", tag, fm.AutoName)
+		}
+		if fm.AutoName != (fm.Name == "") {
+			t.Fatalf("ParseTag(%q) returned the name %q with the auto name %t", tag, fm.Name, fm.AutoName)
+		}
+		if reason := checkFlagName(fm.Name); !fm.AutoName && reason != "" {
+			t.Fatalf("ParseTag(%q) accepted an invalid name: %s", tag, reason)
+		}
+		if fm.Required && fm.Default != "" {
+			t.Fatalf("ParseTag(%q) returned the default value %q of a required flag", tag, fm.Default)
+		}
+		again, err := ParseTag(tag)
+		if err != nil {
+			t.Fatalf("ParseTag(%q) is not deterministic: %v", tag, err)
+		}
+		assert.Equal(t, fm, again)
+	})
+}
@@ -0,0 +1,12 @@
+module github.com/matusvla/easyflag/termprompt
+
+go 1.18
+
+require (
+	github.com/matusvla/easyflag v0.0.0
+	golang.org/x/term v0.12.0
+)
+
+require golang.org/x/sys v0.12.0 // indirect
+
+replace github.com/matusvla/easyflag => ../
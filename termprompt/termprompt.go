@@ -0,0 +1,39 @@
+/*
+Package termprompt provides the easyflag prompt function reading the values from the terminal with the echo disabled:
+
+	type params struct {
+		Password string `flag:"db-password|Database password||secret,prompt,required" env:"DB_PASSWORD"`
+	}
+
+	var p params
+	err := easyflag.ParseAndLoad(&p, easyflag.WithPrompt(termprompt.ReadPassword))
+
+If the standard input is not a terminal, e.g. in a CI pipeline, the flag is left unset.
+*/
+package termprompt
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/matusvla/easyflag"
+	"golang.org/x/term"
+)
+
+// ReadPassword writes the prompt to the standard error and reads a line from the terminal with the echo disabled.
+// If the standard input is not a terminal, it returns the easyflag.ErrPromptUnavailable error.
+func ReadPassword(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", easyflag.ErrPromptUnavailable
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	b, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr) // the newline typed by the user is not echoed
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+var _ easyflag.PromptFunc = ReadPassword
@@ -0,0 +1,65 @@
+package easyflag
+
+import "strings"
+
+/*
+WithUnknownFlags makes the parsing tolerate the flags not defined by the params structure. Instead of failing,
+the unknown flags are removed from the arguments and returned in the Unknown field of the Result, so that
+a wrapper binary can forward them to another program:
+
+	res, err := easyflag.Parse(&p, easyflag.WithUnknownFlags())
+	[...]
+	cmd := exec.Command("tool", append(res.Unknown, res.Args...)...)
+
+An unknown flag takes the next argument as its value, unless the value is a part of the flag argument
+(-name=value) or the next argument starts with the - character. The unknown boolean flags followed
+by a non-flag argument should therefore use the -name=true form.
+*/
+func WithUnknownFlags() Option {
+	return func(o *options) {
+		o.unknownFlags = true
+	}
+}
+
+// splitUnknownFlags removes the unknown flags with their values from the arguments and returns them separately.
+// The native flag package stops at the first non-flag argument, so the unknown flags after it are left as they are,
+// unless a backend is set. The arguments after the -- terminator are never split.
+func (fb *flagBuilder) splitUnknownFlags(args []string) (known []string, unknown []string) {
+	shortNames := make(map[string]bool)
+	for _, short := range fb.shortNames() {
+		shortNames[short] = true
+	}
+	isKnown := func(name string) bool {
+		return fb.flagSet.Lookup(name) != nil || shortNames[name] || "-"+name == helpArg || "-"+name == helpArgShort
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(known, args[i:]...), unknown
+		}
+		name, hasValue, ok := flagArgName(arg)
+		if !ok {
+			if fb.opts.backend == nil {
+				return append(known, args[i:]...), unknown
+			}
+			known = append(known, arg)
+			continue
+		}
+		if isKnown(name) {
+			known = append(known, arg)
+			f := fb.flagSet.Lookup(name)
+			if !hasValue && f != nil && !isBoolFlag(f) && i+1 < len(args) {
+				i++
+				known = append(known, args[i])
+			}
+			continue
+		}
+		unknown = append(unknown, arg)
+		if !hasValue && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			i++
+			unknown = append(unknown, args[i])
+		}
+	}
+	return known, unknown
+}
@@ -0,0 +1,62 @@
+package easyflag
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_unknownFlags(t *testing.T) {
+	type wrapperParams struct {
+		Debug bool   `flag:"debug|Debug output"`
+		Tool  string `flag:"tool|Wrapped tool|tool"`
+	}
+	tests := []struct {
+		name        string
+		args        []string
+		want        wrapperParams
+		wantUnknown []string
+		wantArgs    []string
+	}{
+		{
+			name:        "unknown flags with values",
+			args:        []string{"-debug", "-level", "3", "--color=always", "-tool", "x", "-n", "-v", "file"},
+			want:        wrapperParams{Debug: true, Tool: "x"},
+			wantUnknown: []string{"-level", "3", "--color=always", "-n", "-v", "file"},
+		},
+		{
+			name:        "unknown boolean flag before an argument",
+			args:        []string{"-verbose=true", "file", "-debug"},
+			want:        wrapperParams{Tool: "tool"},
+			wantUnknown: []string{"-verbose=true"},
+			wantArgs:    []string{"file", "-debug"},
+		},
+		{
+			name:     "terminator",
+			args:     []string{"-debug", "--", "-level", "3"},
+			want:     wrapperParams{Debug: true, Tool: "tool"},
+			wantArgs: []string{"-level", "3"},
+		},
+		{
+			name: "known flag value starting with -",
+			args: []string{"-tool", "-x"},
+			want: wrapperParams{Tool: "-x"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p wrapperParams
+			res, err := Parse(&p, WithUnknownFlags(), WithArgsSource(StaticArgs(append([]string{"program"}, tt.args...))),
+				WithOutput(io.Discard))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+			assert.Equal(t, tt.wantUnknown, res.Unknown)
+			assert.ElementsMatch(t, tt.wantArgs, res.Args)
+		})
+	}
+
+	var p wrapperParams
+	_, err := Parse(&p, WithArgsSource(StaticArgs{"program", "-level", "3"}), WithOutput(io.Discard))
+	assert.EqualError(t, err, "flag provided but not defined: -level")
+}
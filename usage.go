@@ -0,0 +1,232 @@
+package easyflag
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// valueNamer is implemented by the flag values knowing the name of the value shown in the usage message.
+type valueNamer interface {
+	valueName() string
+}
+
+func (v *value[T]) valueName() string {
+	return v.typeName
+}
+
+/*
+WriteUsage writes the usage message of the program flags to w, the same one which is printed after the -h flag.
+Unlike the -h flag, it neither parses the command line arguments nor terminates the program, so the usage message
+can be shown e.g. in the error paths, TUIs or web UIs. The params structure must be a pointer to a structure
+just like in the case of the ParseAndLoad function and it is not modified.
+*/
+func WriteUsage(w io.Writer, params interface{}, opts ...Option) error {
+	fb, err := newDetachedFlagBuilder(params, opts)
+	if err != nil {
+		return err
+	}
+	fb.writeUsage(w)
+	return nil
+}
+
+// UsageString returns the usage message of the program flags, see the WriteUsage function.
+func UsageString(params interface{}, opts ...Option) (string, error) {
+	var b strings.Builder
+	if err := WriteUsage(&b, params, opts...); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// printUsage prints the usage message to the output of the flag set. It is used as the Usage function of the flag set.
+func (fb *flagBuilder) printUsage() {
+	if fb.usageDeferred {
+		fb.usageRequested = true
+		return
+	}
+	fb.usagePrinted = true
+	fb.writeUsage(fb.flagSet.Output())
+}
+
+// writeUsage writes the usage message in the format of the native flag package preceded by the program description
+// and usage examples if they are available.
+// Unlike the native flag.PrintDefaults, it derives the value names from the field types
+// and it lists the flags of the nested structures in separate sections.
+// It only reads the state of the flag builder, so it can be called concurrently with different writers.
+func (fb *flagBuilder) writeUsage(out io.Writer) {
+	if d := fb.opts.description; d != "" {
+		fmt.Fprintf(out, "%s\n\n", d)
+	}
+	if len(fb.opts.examples) > 0 {
+		fmt.Fprintf(out, "%s\n", fb.opts.messages.Examples)
+		for _, e := range fb.opts.examples {
+			fmt.Fprintf(out, "  %s\n", e)
+		}
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintf(out, "%s\n", fb.opts.messages.Usage)
+
+	st := fb.usageStyle(out)
+	infos := make(map[string]flagInfo, len(fb.flags))
+	for _, f := range fb.flags {
+		infos[f.name] = f
+	}
+	byGroup := make(map[string][]*flag.Flag)
+	fb.flagSet.VisitAll(func(f *flag.Flag) {
+		if !fb.isHidden(f.Name) {
+			byGroup[infos[f.Name].group] = append(byGroup[infos[f.Name].group], f)
+		}
+	})
+
+	for _, flags := range byGroup {
+		fb.sortFlags(flags)
+	}
+
+	for _, f := range byGroup[""] {
+		printFlagUsage(out, f, infos[f.Name], st, &fb.opts.messages)
+	}
+	for _, g := range groupFlags(fb.flags) {
+		if g.name == "" {
+			continue
+		}
+		fmt.Fprintf(out, "\n%s:\n", g.name)
+		for _, f := range byGroup[g.name] {
+			printFlagUsage(out, f, infos[f.Name], st, &fb.opts.messages)
+		}
+	}
+}
+
+// sortFlags sorts the alphabetically ordered flags according to their priorities and the FlagOrder option.
+// The built-in flags come after the params structure flags in the definition order.
+func (fb *flagBuilder) sortFlags(flags []*flag.Flag) {
+	position := make(map[string]int, len(fb.flags))
+	priority := make(map[string]int, len(fb.flags))
+	for i, f := range fb.flags {
+		position[f.name] = i
+		priority[f.name] = f.priority
+	}
+	positionOf := func(name string) int {
+		if p, ok := position[name]; ok {
+			return p
+		}
+		return len(fb.flags)
+	}
+	sort.SliceStable(flags, func(i, j int) bool {
+		if pi, pj := priority[flags[i].Name], priority[flags[j].Name]; pi != pj {
+			return pi > pj
+		}
+		if fb.opts.flagOrder == DefinitionOrder {
+			return positionOf(flags[i].Name) < positionOf(flags[j].Name)
+		}
+		return false
+	})
+}
+
+// unwrapParsingValue returns the flag value wrapped while the command line is parsed by the maskedFlagValue
+// or the countingFlagValue.
+func unwrapParsingValue(v flag.Value) flag.Value {
+	for {
+		switch wv := v.(type) {
+		case *maskedFlagValue:
+			v = wv.Value
+		case *countingFlagValue:
+			v = wv.Value
+		default:
+			return v
+		}
+	}
+}
+
+// printFlagUsage prints the usage of a single flag. The metadata of the flag, if available, adds the placeholder
+// replacing the value name, the required marker, the example value and the environment variable setting the flag.
+func printFlagUsage(out io.Writer, f *flag.Flag, fi flagInfo, st style, msgs *Messages) {
+	if v := unwrapParsingValue(f.Value); v != f.Value {
+		// the usage is printed while the command line is parsed, e.g. after the -h flag
+		unwrapped := *f
+		unwrapped.Value = v
+		f = &unwrapped
+	}
+	var b strings.Builder
+	name, usage := unquoteUsage(f)
+	valueName := name
+	if len(name) > 0 && fi.placeholder != "" {
+		valueName = fi.placeholder
+	}
+	fmt.Fprintf(&b, "  %s", st.flagName("-"+f.Name))
+	width := len("  -") + len(f.Name)
+	if len(valueName) > 0 {
+		b.WriteString(" ")
+		b.WriteString(valueName)
+		width += len(" ") + len(valueName)
+	}
+	// Boolean flags of one ASCII letter are so common we treat them specially, putting their usage on the same line.
+	if width <= 4 {
+		b.WriteString("\t")
+	} else {
+		b.WriteString("\n    \t")
+	}
+	b.WriteString(strings.ReplaceAll(usage, "\n", "\n    \t"))
+	if fi.isRequired {
+		fmt.Fprintf(&b, " %s", st.required(msgs.Required))
+	} else if len(fi.requiredEnvs) > 0 {
+		fmt.Fprintf(&b, " %s", st.required(fmt.Sprintf(msgs.RequiredIn, strings.Join(fi.requiredEnvs, ", "))))
+	}
+	if !isZeroValue(f) {
+		defaultVal := f.DefValue
+		if fi.isSecret {
+			defaultVal = maskedValue
+		} else if name == "string" {
+			defaultVal = strconv.Quote(defaultVal)
+		}
+		fmt.Fprintf(&b, " %s", st.defaultValue(fmt.Sprintf(msgs.Default, defaultVal)))
+	}
+	if fi.example != "" {
+		fmt.Fprintf(&b, " "+msgs.Example, fi.example)
+	}
+	if fi.env != "" {
+		fmt.Fprintf(&b, " "+msgs.Env, fi.env)
+	}
+	fmt.Fprint(out, b.String(), "\n")
+}
+
+// unquoteUsage extracts a back-quoted name from the usage string for a flag and returns it and the un-quoted usage.
+// If there is no back-quoted name, the name is derived from the flag value the same way as in the native flag package.
+func unquoteUsage(f *flag.Flag) (name string, usage string) {
+	usage = f.Usage
+	if start := strings.IndexByte(usage, '`'); start >= 0 {
+		if end := strings.IndexByte(usage[start+1:], '`'); end >= 0 {
+			end += start + 1
+			return usage[start+1 : end], usage[:start] + usage[start+1:end] + usage[end+1:]
+		}
+	}
+	if vn, ok := f.Value.(valueNamer); ok {
+		return vn.valueName(), usage
+	}
+	if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && bf.IsBoolFlag() {
+		return "", usage
+	}
+	return "value", usage
+}
+
+// isZeroValue determines whether the string represents the zero value for a flag.
+func isZeroValue(f *flag.Flag) (ok bool) {
+	typ := reflect.TypeOf(f.Value)
+	var z reflect.Value
+	if typ.Kind() == reflect.Pointer {
+		z = reflect.New(typ.Elem())
+	} else {
+		z = reflect.Zero(typ)
+	}
+	// the String method of a user defined flag.Value might not expect to be called on a zero value
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	return f.DefValue == z.Interface().(flag.Value).String()
+}
@@ -0,0 +1,269 @@
+package easyflag
+
+import (
+	"bytes"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlagBuilder_printUsage(t *testing.T) {
+	type serverInfo struct {
+		Host string `flag:"a|Server host address|127.0.0.1"`
+		Port int    `flag:"p|Server port|80"`
+	}
+	tests := []struct {
+		name   string
+		params interface{}
+		opts   []Option
+		want   string
+	}{
+		{
+			name: "flat",
+			params: &struct {
+				In      string `flag:"in|Path to the input file||required"`
+				Verbose bool   `flag:"v|Verbose output"`
+				Len     int64  `flag:"n|Maximum number of characters to read|-1"`
+				Labels  Set    `flag:"label|Labels"`
+			}{},
+			want: `Usage:
+  -in string
+    	Path to the input file (required)
+  -label value
+    	Labels
+  -n int
+    	Maximum number of characters to read (default -1)
+  -v	Verbose output
+`,
+		},
+		{
+			name: "grouped",
+			params: &struct {
+				In         string     `flag:"in|Path to the input file||required"`
+				ServerInfo serverInfo `flagGroup:"Server options"`
+				Logging    struct {
+					Verbose bool `flag:"v|Verbose output"`
+				}
+			}{},
+			want: `Usage:
+  -in string
+    	Path to the input file (required)
+
+Server options:
+  -a string
+    	Server host address (default "127.0.0.1")
+  -p int
+    	Server port (default 80)
+
+Logging:
+  -v	Verbose output
+`,
+		},
+		{
+			name: "definition order",
+			params: &struct {
+				Verbose bool   `flag:"v|Verbose output"`
+				In      string `flag:"in|Path to the input file||required"`
+				Len     int64  `flag:"n|Maximum number of characters to read|-1"`
+			}{},
+			opts: []Option{WithFlagOrder(DefinitionOrder), WithVersion("1.0.0")},
+			want: `Usage:
+  -v	Verbose output
+  -in string
+    	Path to the input file (required)
+  -n int
+    	Maximum number of characters to read (default -1)
+  -V	Prints the version information
+  -version
+    	Prints the version information
+`,
+		},
+		{
+			name: "placeholder",
+			params: &struct {
+				In      string `flag:"in|Path to the input file|in.txt|placeholder=FILE"`
+				Verbose bool   `flag:"v|Verbose output||placeholder=IGNORED"`
+			}{},
+			want: `Usage:
+  -in FILE
+    	Path to the input file (default "in.txt")
+  -v	Verbose output
+`,
+		},
+		{
+			name: "env",
+			params: &struct {
+				Host string `flag:"host|Server host|localhost" env:"APP_HOST"`
+				Port int    `flag:"port|Server port" env:"APP_PORT" example:"8080" required_env:"prod"`
+			}{},
+			want: `Usage:
+  -host string
+    	Server host (default "localhost") (env APP_HOST)
+  -port int
+    	Server port (required in prod) (example 8080) (env APP_PORT)
+`,
+		},
+		{
+			name: "colors",
+			params: &struct {
+				In      string `flag:"in|Path to the input file||required"`
+				Len     int64  `flag:"n|Maximum number of characters to read|-1"`
+				Verbose bool   `flag:"v|Verbose output"`
+			}{},
+			opts: []Option{WithColor(ColorAlways)},
+			want: "Usage:\n" +
+				"  \x1b[1m-in\x1b[0m string\n    \tPath to the input file \x1b[31m(required)\x1b[0m\n" +
+				"  \x1b[1m-n\x1b[0m int\n    \tMaximum number of characters to read \x1b[2m(default -1)\x1b[0m\n" +
+				"  \x1b[1m-v\x1b[0m\tVerbose output\n",
+		},
+		{
+			name: "messages",
+			params: &struct {
+				In  string `flag:"in|Vstupný súbor||required"`
+				Len int64  `flag:"n|Maximálny počet znakov|-1" env:"LEN"`
+			}{},
+			opts: []Option{WithMessages(Messages{Usage: "Použitie:", Required: "(povinný)", Default: "(predvolené %s)"})},
+			want: `Použitie:
+  -in string
+    	Vstupný súbor (povinný)
+  -n int
+    	Maximálny počet znakov (predvolené -1) (env LEN)
+`,
+		},
+		{
+			name: "escaped separator",
+			params: &struct {
+				Format string `flag:"fmt|Output format: json\\|yaml|json"`
+			}{},
+			want: `Usage:
+  -fmt string
+    	Output format: json|yaml (default "json")
+`,
+		},
+		{
+			name: "priority",
+			params: &struct {
+				Verbose bool   `flag:"v|Verbose output"`
+				In      string `flag:"in|Path to the input file||required,priority=2"`
+				Len     int64  `flag:"n|Maximum number of characters to read|-1|priority=1"`
+			}{},
+			want: `Usage:
+  -in string
+    	Path to the input file (required)
+  -n int
+    	Maximum number of characters to read (default -1)
+  -v	Verbose output
+`,
+		},
+		{
+			name:   "description and examples",
+			params: &describedParams{},
+			want: `Copies the input file to the standard output.
+
+Examples:
+  program -in file.txt
+  program -in file.txt -n 10
+
+Usage:
+  -in string
+    	Path to the input file (required)
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fb, err := newFlagBuilder(newParamsOptions(tt.params, tt.opts))
+			assert.NoError(t, err)
+			assert.NoError(t, fb.setUpFlags(tt.params))
+			var buf bytes.Buffer
+			fb.flagSet.SetOutput(&buf)
+			fb.printUsage()
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+type describedParams struct {
+	In string `flag:"in|Path to the input file||required"`
+}
+
+func (dp *describedParams) Description() string {
+	return "Copies the input file to the standard output."
+}
+
+func (dp *describedParams) Examples() []string {
+	return []string{"program -in file.txt", "program -in file.txt -n 10"}
+}
+
+func TestFlagBuilder_usageStyle(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want bool
+	}{
+		{name: "auto without terminal", opts: nil, want: false},
+		{name: "always", opts: []Option{WithColor(ColorAlways)}, want: true},
+		{name: "always with NO_COLOR", opts: []Option{WithColor(ColorAlways), WithEnvSource(MapEnv{"NO_COLOR": "1"})}, want: true},
+		{name: "never", opts: []Option{WithColor(ColorNever)}, want: false},
+		{name: "auto with NO_COLOR", opts: []Option{WithEnvSource(MapEnv{"NO_COLOR": ""})}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fb, err := newFlagBuilder(newOptions(tt.opts))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, fb.usageStyle(&bytes.Buffer{}).enabled)
+		})
+	}
+}
+
+func TestFlagBuilder_writeUsage_concurrent(t *testing.T) {
+	params := &struct {
+		In      string `flag:"in|Path to the input file||required"`
+		Verbose bool   `flag:"v|Verbose output"`
+	}{}
+	fb, err := newFlagBuilder(newOptions(nil))
+	assert.NoError(t, err)
+	assert.NoError(t, fb.setUpFlags(params))
+	var want bytes.Buffer
+	fb.writeUsage(&want)
+
+	var wg sync.WaitGroup
+	results := make([]bytes.Buffer, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(buf *bytes.Buffer) {
+			defer wg.Done()
+			fb.writeUsage(buf)
+		}(&results[i])
+	}
+	wg.Wait()
+	for _, got := range results {
+		assert.Equal(t, want.String(), got.String())
+	}
+}
+
+func TestUsageString(t *testing.T) {
+	type usageParams struct {
+		Port int    `flag:"port|Server port|80"`
+		In   string `flag:"in|Input file||required"`
+	}
+	p := usageParams{Port: 8080}
+	got, err := UsageString(&p, WithDescription("Serves the files."), WithExitFunc(func(int) { t.Fatal("unexpected exit") }))
+	assert.NoError(t, err)
+	assert.Equal(t, "Serves the files.\n\n"+
+		"Usage:\n"+
+		"  -in string\n"+
+		"    \tInput file (required)\n"+
+		"  -port int\n"+
+		"    \tServer port (default 80)\n", got)
+	assert.Equal(t, usageParams{Port: 8080}, p)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteUsage(&buf, &p, WithDescription("Serves the files.")))
+	assert.Equal(t, got, buf.String())
+
+	_, err = UsageString(p)
+	assert.Equal(t, &InvalidParamsError{Type: reflect.TypeOf(p)}, err)
+}
@@ -0,0 +1,131 @@
+package easyflag
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	mustExistValue  = "mustExist"
+	mustBeDirValue  = "mustBeDir"
+	mustBeFileValue = "mustBeFile"
+	urlValue        = "url"
+	urlValuePrefix  = "url="
+	notBlankValue   = "notblank"
+)
+
+// optionCheck is a validation of a string field value declared by a flag option, e.g. mustExist.
+type optionCheck struct {
+	option string
+	noun   string // the noun describing the value in the error message, e.g. path
+	check  func(string) error
+}
+
+// optionChecks returns the validations declared by the flag options.
+func (fm flagMetadata) optionChecks() []optionCheck {
+	var checks []optionCheck
+	if fm.pathCheck != "" {
+		check := fm.pathCheck
+		checks = append(checks, optionCheck{
+			option: check,
+			noun:   "path",
+			check:  func(path string) error { return checkPath(path, check) },
+		})
+	}
+	if fm.isURL {
+		schemes := strings.Fields(fm.urlSchemes)
+		checks = append(checks, optionCheck{
+			option: urlValue,
+			noun:   "URL",
+			check:  func(u string) error { return checkURL(u, schemes) },
+		})
+	}
+	if fm.isNotBlank {
+		checks = append(checks, optionCheck{option: notBlankValue, noun: "value", check: checkNotBlank})
+	}
+	return checks
+}
+
+// addOptionValidators adds the validations of the field value declared by the flag options, e.g. mustExist.
+// The ptr is the pointer to the field. The empty values of the flags not set by any source are not validated,
+// they are handled by the required option.
+func (fb *flagBuilder) addOptionValidators(fm flagMetadata, ptr interface{}) error {
+	checks := fm.optionChecks()
+	if len(checks) == 0 {
+		return nil
+	}
+	s, ok := ptr.(*string)
+	if !ok {
+		return fmt.Errorf("%s not supported for the flag -%s", checks[0].option, fm.name)
+	}
+	for _, c := range checks {
+		c := c
+		fb.valFns = append(fb.valFns, func() error {
+			if _, set := fb.sources[fm.name]; *s == "" && !set {
+				return nil
+			}
+			if err := c.check(*s); err != nil {
+				return fmt.Errorf("flag -%s: %s %q %w", fm.name, c.noun, fm.displayed(*s), err)
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+// checkPath checks that the path exists and that it is a directory or a regular file, if required by the check.
+func checkPath(path, check string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return errors.New("does not exist")
+	}
+	if err != nil {
+		return fmt.Errorf("cannot be accessed: %w", err)
+	}
+	switch {
+	case check == mustBeDirValue && !info.IsDir():
+		return errors.New("is not a directory")
+	case check == mustBeFileValue && !info.Mode().IsRegular():
+		return errors.New("is not a regular file")
+	}
+	return nil
+}
+
+// checkURL checks that the value is an absolute URL with a host using one of the schemes, if any.
+func checkURL(value string, schemes []string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("is not valid: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return errors.New("is not an absolute URL with a host")
+	}
+	if len(schemes) > 0 && !containsFold(schemes, u.Scheme) {
+		return fmt.Errorf("must use the %s scheme", strings.Join(schemes, " or "))
+	}
+	return nil
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNotBlank checks that the value is not empty or whitespace-only.
+func checkNotBlank(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return errors.New("is blank")
+	}
+	return nil
+}
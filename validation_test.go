@@ -0,0 +1,162 @@
+package easyflag
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAndLoad_pathChecks(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	assert.NoError(t, os.WriteFile(file, nil, 0o600))
+	missing := filepath.Join(dir, "missing")
+
+	type pathParams struct {
+		Any  string `flag:"any|Existing path||mustExist"`
+		Dir  string `flag:"dir|Existing directory||mustBeDir"`
+		File string `flag:"file|Existing file||mustBeFile"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "unset flags"},
+		{name: "valid paths", args: []string{"-any", file, "-dir", dir, "-file", file}},
+		{
+			name:    "missing path",
+			args:    []string{"-any", missing},
+			wantErr: `validation failed: flag -any: path "` + missing + `" does not exist`,
+		},
+		{
+			name:    "file instead of directory",
+			args:    []string{"-dir", file},
+			wantErr: `validation failed: flag -dir: path "` + file + `" is not a directory`,
+		},
+		{
+			name:    "directory instead of file",
+			args:    []string{"-file", dir},
+			wantErr: `validation failed: flag -file: path "` + dir + `" is not a regular file`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p pathParams
+			err := ParseAndLoad(&p, WithArgsSource(StaticArgs(append([]string{"program"}, tt.args...))),
+				WithOutput(io.Discard))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	var invalid struct {
+		Port int `flag:"port|Port||mustExist"`
+	}
+	err := ParseAndLoad(&invalid, WithArgsSource(StaticArgs{"program"}), WithOutput(io.Discard))
+	assert.EqualError(t, err, "invalid field Port: mustExist not supported for the flag -port")
+}
+
+func TestParseAndLoad_url(t *testing.T) {
+	type urlParams struct {
+		Endpoint string `flag:"endpoint|Service endpoint||url"`
+		Secure   string `flag:"secure|Secure endpoint|https://example.com|url=https"`
+		Web      string `flag:"web|Web page||url=http https"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "default values"},
+		{name: "valid URLs", args: []string{"-endpoint=grpc://localhost:9090", "-secure=HTTPS://example.com/x", "-web=http://a.b"}},
+		{
+			name:    "relative URL",
+			args:    []string{"-endpoint=example.com/api"},
+			wantErr: `validation failed: flag -endpoint: URL "example.com/api" is not an absolute URL with a host`,
+		},
+		{
+			name:    "invalid URL",
+			args:    []string{"-endpoint=http://exa mple.com"},
+			wantErr: `validation failed: flag -endpoint: URL "http://exa mple.com" is not valid: invalid character " " in host name`,
+		},
+		{
+			name:    "explicitly empty URL",
+			args:    []string{"-endpoint="},
+			wantErr: `validation failed: flag -endpoint: URL "" is not an absolute URL with a host`,
+		},
+		{
+			name:    "disallowed scheme",
+			args:    []string{"-secure=http://example.com"},
+			wantErr: `validation failed: flag -secure: URL "http://example.com" must use the https scheme`,
+		},
+		{
+			name:    "disallowed one of schemes",
+			args:    []string{"-web=ftp://example.com"},
+			wantErr: `validation failed: flag -web: URL "ftp://example.com" must use the http or https scheme`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p urlParams
+			err := ParseAndLoad(&p, WithArgsSource(StaticArgs(append([]string{"program"}, tt.args...))),
+				WithOutput(io.Discard))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestParseAndLoad_notBlank(t *testing.T) {
+	type nameParams struct {
+		Name  string `flag:"name|Service name||notblank,required"`
+		Token string `flag:"token|API token||notblank,secret" env:"TOKEN"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		env     MapEnv
+		wantErr string
+	}{
+		{name: "valid values", args: []string{"-name=api", "-token= abc "}},
+		{name: "unset optional flag", args: []string{"-name=api"}},
+		{name: "unset required flag", wantErr: `missing required flag "name" or its value`},
+		{
+			name:    "empty value",
+			args:    []string{"-name=api", "-token="},
+			wantErr: `validation failed: flag -token: value "***" is blank`,
+		},
+		{
+			name:    "whitespace-only value",
+			args:    []string{"-name", " \t"},
+			wantErr: `validation failed: flag -name: value " \t" is blank`,
+		},
+		{
+			name:    "blank secret value from the environment",
+			args:    []string{"-name=api"},
+			env:     MapEnv{"TOKEN": "  "},
+			wantErr: `validation failed: flag -token: value "***" is blank`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p nameParams
+			err := ParseAndLoad(&p, WithArgsSource(StaticArgs(append([]string{"program"}, tt.args...))),
+				WithEnvSource(tt.env), WithOutput(io.Discard))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package easyflag
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WhitespacePolicy defines how the leading and trailing whitespace of the flag values passed on the command line
+// is handled. The default values defined in the field tags are always trimmed.
+type WhitespacePolicy int
+
+const (
+	// KeepWhitespace passes the values to the fields as they are. This is the default policy.
+	KeepWhitespace WhitespacePolicy = iota
+	// TrimWhitespace removes the leading and trailing whitespace of the values.
+	TrimWhitespace
+	// RejectWhitespace makes the parsing fail if a value contains any leading or trailing whitespace.
+	RejectWhitespace
+)
+
+var (
+	errParse      = errors.New("parse error")
+	errRange      = errors.New("value out of range")
+	errWhitespace = errors.New("leading or trailing whitespace not allowed")
+)
+
+// value is the flag.Value implementation used for all the built-in field types.
+type value[T any] struct {
+	ptr        *T
+	parseFn    func(string) (T, error)
+	typeName   string // the name of the value shown in the usage message
+	isBool     bool
+	whitespace WhitespacePolicy
+	choices    []string
+	msgs       *Messages
+}
+
+func (v *value[T]) String() string {
+	if v.ptr == nil {
+		var zero T
+		return fmt.Sprint(zero)
+	}
+	return fmt.Sprint(*v.ptr)
+}
+
+func (v *value[T]) Set(s string) error {
+	switch v.whitespace {
+	case TrimWhitespace:
+		s = strings.TrimSpace(s)
+	case RejectWhitespace:
+		if strings.TrimSpace(s) != s {
+			return errWhitespace
+		}
+	}
+	if err := checkChoice(v.choices, s, v.msgs.ValueNotAllowed); err != nil {
+		return err
+	}
+	result, err := v.parseFn(s)
+	if err != nil {
+		return numError(err)
+	}
+	*v.ptr = result
+	return nil
+}
+
+func (v *value[T]) Get() interface{} {
+	return *v.ptr
+}
+
+func (v *value[T]) IsBoolFlag() bool {
+	return v.isBool
+}
+
+// parseBoolSynonym parses a boolean value accepting the yes/no and on/off synonyms case-insensitively
+// besides the values accepted by the strconv.ParseBool function.
+func parseBoolSynonym(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "yes", "on":
+		return true, nil
+	case "no", "off":
+		return false, nil
+	}
+	return strconv.ParseBool(strings.ToLower(s))
+}
+
+// numError simplifies the errors returned by the strconv package the same way the native flag package does it.
+func numError(err error) error {
+	var ne *strconv.NumError
+	if !errors.As(err, &ne) {
+		return err
+	}
+	if ne.Err == strconv.ErrSyntax {
+		return errParse
+	}
+	if ne.Err == strconv.ErrRange {
+		return errRange
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+/*
+Package vaultsecrets provides the easyflag secret provider reading the secrets from the KV version 2 secrets engine
+of HashiCorp Vault:
+
+	type params struct {
+		Password string `flag:"db-password|Database password||secret" secretRef:"db/password"`
+	}
+
+	client, err := vault.NewClient(vault.DefaultConfig()) // configured by VAULT_ADDR, VAULT_TOKEN, etc.
+	[...]
+	var p params
+	err = easyflag.ParseAndLoad(&p, easyflag.WithSecretProvider(vaultsecrets.New(client, "secret")))
+
+The reference of a secret is the path of the secret and the key of the value separated by #, e.g. db#password.
+Without the separator, the last element of the path is the key, so db/password refers to the password key
+of the db secret as well.
+*/
+package vaultsecrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	vault "github.com/hashicorp/vault/api"
+	"github.com/matusvla/easyflag"
+)
+
+type provider struct {
+	kv *vault.KVv2
+}
+
+// New returns the easyflag secret provider reading the secrets from the KV version 2 secrets engine
+// mounted at the mount path, e.g. secret.
+func New(client *vault.Client, mountPath string) easyflag.SecretProvider {
+	return provider{kv: client.KVv2(mountPath)}
+}
+
+// Secret returns the value of the key of the secret referenced by ref.
+func (p provider) Secret(ctx context.Context, ref string) (string, error) {
+	path, key, err := splitRef(ref)
+	if err != nil {
+		return "", err
+	}
+	secret, err := p.kv.Get(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	val, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in the secret %s", key, path)
+	}
+	s, ok := val.(string)
+	if !ok {
+		return "", fmt.Errorf("value of the key %s of the secret %s is not a string", key, path)
+	}
+	return s, nil
+}
+
+// splitRef splits the secret reference to the path of the secret and the key of the value.
+func splitRef(ref string) (string, string, error) {
+	path, key, found := strings.Cut(ref, "#")
+	if !found {
+		i := strings.LastIndex(ref, "/")
+		if i < 0 {
+			return "", "", fmt.Errorf("invalid secret reference %s: missing key", ref)
+		}
+		path, key = ref[:i], ref[i+1:]
+	}
+	if path == "" || key == "" {
+		return "", "", fmt.Errorf("invalid secret reference %s: empty path or key", ref)
+	}
+	return path, key, nil
+}
@@ -0,0 +1,52 @@
+package easyflag
+
+import (
+	"flag"
+	"strings"
+)
+
+// WithWindowsSyntax enables the Windows-style flag syntax besides the usual one: the /name value and /name:value
+// arguments are translated to -name value and -name=value before the flags are parsed, and /? requests the usage
+// message just like -h. Only the arguments naming the defined flags are translated, so the absolute Unix paths
+// passed as values or positional arguments, e.g. /tmp/file, are left as they are.
+func WithWindowsSyntax() Option {
+	return func(o *options) {
+		o.windowsSyntax = true
+	}
+}
+
+// translateWindowsArgs translates the Windows-style flag arguments to the usual syntax. The values of the flags
+// passed as separate arguments and the arguments after the -- terminator are not translated.
+func (fb *flagBuilder) translateWindowsArgs(args []string) []string {
+	translated := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(translated, args[i:]...)
+		}
+		if arg == "/?" {
+			translated = append(translated, helpArgShort)
+			continue
+		}
+		if rest, ok := cutPrefix(arg, "/"); ok {
+			if name, _, _ := strings.Cut(rest, ":"); name != "" && fb.flagSet.Lookup(name) != nil {
+				arg = "-" + strings.Replace(rest, ":", "=", 1)
+			}
+		}
+		translated = append(translated, arg)
+		// the value of the flag passed as a separate argument is kept as it is, even if it starts with /
+		if name, hasValue, ok := flagArgName(arg); ok && !hasValue && i+1 < len(args) {
+			if f := fb.flagSet.Lookup(name); f != nil && !isBoolFlag(f) {
+				i++
+				translated = append(translated, args[i])
+			}
+		}
+	}
+	return translated
+}
+
+// isBoolFlag reports whether the flag is a boolean flag which does not take a separate value argument.
+func isBoolFlag(f *flag.Flag) bool {
+	bf, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && bf.IsBoolFlag()
+}
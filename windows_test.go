@@ -0,0 +1,71 @@
+package easyflag
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_windowsSyntax(t *testing.T) {
+	type winParams struct {
+		Out     string `flag:"out|Output path"`
+		Verbose bool   `flag:"verbose|Verbose output"`
+		Level   int    `flag:"level|Compression level"`
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		want     winParams
+		wantArgs []string
+	}{
+		{
+			name: "colon syntax",
+			args: []string{"/out:C:\\temp\\x.zip", "/level:9", "/verbose"},
+			want: winParams{Out: `C:\temp\x.zip`, Verbose: true, Level: 9},
+		},
+		{
+			name: "separate values",
+			args: []string{"/out", "/tmp/x", "/level", "3", "file"},
+			want: winParams{Out: "/tmp/x", Level: 3}, wantArgs: []string{"file"},
+		},
+		{
+			name: "mixed syntax",
+			args: []string{"-out", "/verbose", "/level:1"},
+			want: winParams{Out: "/verbose", Level: 1},
+		},
+		{
+			name: "undefined flags and paths",
+			args: []string{"/verbose:true", "/tmp/file", "/unknown:1"},
+			want: winParams{Verbose: true}, wantArgs: []string{"/tmp/file", "/unknown:1"},
+		},
+		{
+			name:     "terminator",
+			args:     []string{"--", "/level:9"},
+			wantArgs: []string{"/level:9"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p winParams
+			res, err := Parse(&p, WithWindowsSyntax(), WithArgsSource(StaticArgs(append([]string{"program"}, tt.args...))),
+				WithOutput(io.Discard))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+			assert.ElementsMatch(t, tt.wantArgs, res.Args)
+		})
+	}
+}
+
+func TestParse_windowsSyntaxHelp(t *testing.T) {
+	var p struct {
+		Out string `flag:"out|Output path"`
+	}
+	var out bytes.Buffer
+	exitCode := -1
+	_, _ = Parse(&p, WithWindowsSyntax(), WithArgsSource(StaticArgs{"program", "/?"}), WithOutput(&out),
+		WithExitFunc(func(code int) { exitCode = code }))
+	assert.Equal(t, 0, exitCode)
+	assert.Contains(t, out.String(), "Output path")
+}